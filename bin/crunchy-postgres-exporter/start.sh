@@ -22,6 +22,7 @@ POSTGRES_EXPORTER_PIDFILE=/tmp/postgres_exporter.pid
 CONFIG_DIR='/opt/cpm/conf'
 QUERIES=(
     queries_backrest
+    queries_pgbackrest_archive
     queries_global
     queries_per_db
     queries_nodemx
@@ -0,0 +1,378 @@
+/*
+ Copyright 2021 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package postgrescluster
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crunchydata/postgres-operator/internal/kubeapi"
+	"github.com/crunchydata/postgres-operator/internal/logging"
+	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/internal/pgbackrest"
+	"github.com/crunchydata/postgres-operator/internal/postgres"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+// volumeAutoGrowInterval is how often filesystem usage is checked when any
+// volume of a cluster is configured to grow automatically.
+const volumeAutoGrowInterval = 5 * time.Minute
+
+// volumeUsage is the size and amount in use of a filesystem, in bytes.
+type volumeUsage struct {
+	Size, Used int64
+}
+
+// parseVolumeUsage parses the output of `df --block-size=1 --output=size,used`.
+func parseVolumeUsage(stdout string) (volumeUsage, error) {
+	var usage volumeUsage
+
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(lines) < 2 || len(fields) != 2 {
+		return usage, errors.Errorf("unexpected filesystem usage: %q", stdout)
+	}
+
+	size, err := strconv.ParseInt(fields[0], 10, 64)
+	if err == nil {
+		usage.Size = size
+		usage.Used, err = strconv.ParseInt(fields[1], 10, 64)
+	}
+
+	return usage, errors.WithStack(err)
+}
+
+// nextVolumeRequest returns the storage request that should replace current
+// when usage has passed the threshold of autoGrow. It returns false when the
+// volume should not or cannot grow.
+func nextVolumeRequest(
+	autoGrow *v1beta1.VolumeAutoGrow, current resource.Quantity, usage volumeUsage,
+) (resource.Quantity, bool) {
+	if usage.Size <= 0 || usage.Used*100 < int64(autoGrow.Threshold)*usage.Size {
+		return current, false
+	}
+
+	next := current.DeepCopy()
+	next.Add(autoGrow.Increase)
+
+	if next.Cmp(autoGrow.Limit) > 0 {
+		next = autoGrow.Limit.DeepCopy()
+	}
+	if next.Cmp(current) <= 0 {
+		return current, false
+	}
+
+	return next, true
+}
+
+// volumeResizePending returns true when the storage request of pvc has not
+// yet been fulfilled: its capacity is smaller than its request or Kubernetes is
+// still resizing its volume or filesystem. Until then, the filesystem reports
+// its old size.
+func volumeResizePending(pvc *corev1.PersistentVolumeClaim) bool {
+	for _, condition := range pvc.Status.Conditions {
+		switch condition.Type {
+		case
+			corev1.PersistentVolumeClaimResizing,
+			corev1.PersistentVolumeClaimFileSystemResizePending:
+			if condition.Status == corev1.ConditionTrue {
+				return true
+			}
+		}
+	}
+
+	request := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]
+	return !ok || capacity.Cmp(request) < 0
+}
+
+// keepVolumeRequest raises the storage request of intent to that of existing.
+// A volume that has grown automatically has a request larger than its spec,
+// and Kubernetes does not allow that request to shrink, even after autoGrow is
+// disabled.
+func keepVolumeRequest(intent, existing *corev1.PersistentVolumeClaim) {
+	if existing == nil {
+		return
+	}
+
+	current, ok := existing.Spec.Resources.Requests[corev1.ResourceStorage]
+	if request := intent.Spec.Resources.Requests[corev1.ResourceStorage]; ok && current.Cmp(request) > 0 {
+		// The requests map may belong to the cluster spec; make a copy.
+		intent.Spec.Resources.Requests = intent.Spec.Resources.Requests.DeepCopy()
+		if intent.Spec.Resources.Requests == nil {
+			intent.Spec.Resources.Requests = corev1.ResourceList{}
+		}
+		intent.Spec.Resources.Requests[corev1.ResourceStorage] = current
+	}
+}
+
+// setVolumeAutoGrowBlocked records in the status of cluster whether the volume
+// named name is past its threshold but cannot grow. It returns true when that
+// is different than before.
+func setVolumeAutoGrowBlocked(cluster *v1beta1.PostgresCluster, name string, blocked bool) bool {
+	names := sets.NewString(cluster.Status.VolumeAutoGrowBlocked...)
+	if names.Has(name) == blocked {
+		return false
+	}
+
+	if blocked {
+		names.Insert(name)
+	} else {
+		names.Delete(name)
+	}
+
+	cluster.Status.VolumeAutoGrowBlocked = nil
+	if names.Len() > 0 {
+		cluster.Status.VolumeAutoGrowBlocked = names.List()
+	}
+	return true
+}
+
+// findVolume returns the PVC in volumes that has name, if any.
+func findVolume(volumes []corev1.PersistentVolumeClaim, name string) *corev1.PersistentVolumeClaim {
+	for i := range volumes {
+		if volumes[i].Name == name {
+			return &volumes[i]
+		}
+	}
+	return nil
+}
+
+// volumeAutoGrowEnabled returns true when any volume of cluster is configured
+// to grow automatically.
+func volumeAutoGrowEnabled(cluster *v1beta1.PostgresCluster) bool {
+	for _, set := range cluster.Spec.InstanceSets {
+		if set.DataVolumeAutoGrow != nil || set.WALVolumeAutoGrow != nil {
+			return true
+		}
+	}
+	for _, repo := range cluster.Spec.Backups.PGBackRest.Repos {
+		if repo.Volume != nil && repo.Volume.AutoGrow != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// +kubebuilder:rbac:groups="",resources=pods,verbs=list
+// +kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
+
+// reconcileVolumeAutoGrow measures the filesystems of volumes that are
+// configured to grow automatically and increases their storage requests as
+// they fill. It measures at most once every volumeAutoGrowInterval and
+// requeues so that usage is measured again later.
+func (r *Reconciler) reconcileVolumeAutoGrow(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+	instances *observedInstances, clusterVolumes []corev1.PersistentVolumeClaim,
+) (reconcile.Result, error) {
+	if !volumeAutoGrowEnabled(cluster) {
+		cluster.Status.VolumeAutoGrowBlocked = nil
+		cluster.Status.VolumeAutoGrowObservedTime = nil
+		return reconcile.Result{}, nil
+	}
+
+	// Every change to the cluster or its status triggers a reconcile. Measure
+	// only when the interval has passed since the last time.
+	if observed := cluster.Status.VolumeAutoGrowObservedTime; observed != nil {
+		if elapsed := time.Since(observed.Time); elapsed < volumeAutoGrowInterval {
+			return reconcile.Result{RequeueAfter: volumeAutoGrowInterval - elapsed}, nil
+		}
+	}
+
+	now := metav1.Now()
+	cluster.Status.VolumeAutoGrowObservedTime = &now
+
+	var err error
+
+	for _, instance := range instances.forCluster {
+		if instance.Spec == nil || len(instance.Pods) != 1 {
+			continue
+		}
+		if running, known := instance.IsRunning(naming.ContainerDatabase); !running || !known {
+			continue
+		}
+
+		pod := instance.Pods[0]
+		for _, volume := range []struct {
+			autoGrow *v1beta1.VolumeAutoGrow
+			role     string
+			path     string
+		}{
+			{instance.Spec.DataVolumeAutoGrow, naming.RolePostgresData, postgres.DataVolumeMount().MountPath},
+			{instance.Spec.WALVolumeAutoGrow, naming.RolePostgresWAL, postgres.WALVolumeMount().MountPath},
+		} {
+			if volume.autoGrow == nil || err != nil {
+				continue
+			}
+
+			var name string
+			name, err = getPGPVCName(map[string]string{
+				naming.LabelCluster:  cluster.Name,
+				naming.LabelInstance: instance.Name,
+				naming.LabelRole:     volume.role,
+			}, clusterVolumes)
+
+			if existing := findVolume(clusterVolumes, name); err == nil && existing != nil {
+				err = r.autoGrowVolume(ctx, cluster, volume.autoGrow, existing,
+					pod, naming.ContainerDatabase, volume.path)
+			}
+		}
+	}
+
+	var repoHost *corev1.Pod
+	for _, repo := range cluster.Spec.Backups.PGBackRest.Repos {
+		if repo.Volume == nil || repo.Volume.AutoGrow == nil || err != nil {
+			continue
+		}
+
+		if repoHost == nil {
+			repoHost, err = r.observeRepoHostPod(ctx, cluster)
+			if err != nil || repoHost == nil {
+				break
+			}
+		}
+
+		var existing *corev1.PersistentVolumeClaim
+		for i := range clusterVolumes {
+			if clusterVolumes[i].Labels[naming.LabelPGBackRestRepo] == repo.Name {
+				if _, ok := clusterVolumes[i].Labels[naming.LabelPGBackRestRepoVolume]; ok {
+					existing = &clusterVolumes[i]
+				}
+			}
+		}
+		if existing != nil {
+			err = r.autoGrowVolume(ctx, cluster, repo.Volume.AutoGrow, existing,
+				repoHost, naming.PGBackRestRepoContainerName,
+				pgbackrest.RepoVolumeMount().MountPath+"/"+repo.Name)
+		}
+	}
+
+	// Forget volumes that no longer exist.
+	for _, name := range cluster.Status.VolumeAutoGrowBlocked {
+		if findVolume(clusterVolumes, name) == nil {
+			setVolumeAutoGrowBlocked(cluster, name, false)
+		}
+	}
+
+	return reconcile.Result{RequeueAfter: volumeAutoGrowInterval}, err
+}
+
+// observeRepoHostPod returns the running pgBackRest repository host Pod of
+// cluster, if any.
+func (r *Reconciler) observeRepoHostPod(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+) (*corev1.Pod, error) {
+	pods := &corev1.PodList{}
+	err := errors.WithStack(r.Client.List(ctx, pods,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabelsSelector{
+			Selector: naming.PGBackRestDedicatedSelector(cluster.Name),
+		}))
+
+	for i := range pods.Items {
+		for _, status := range pods.Items[i].Status.ContainerStatuses {
+			if status.Name == naming.PGBackRestRepoContainerName && status.State.Running != nil {
+				return &pods.Items[i], err
+			}
+		}
+	}
+	return nil, err
+}
+
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=patch
+
+// autoGrowVolume measures the filesystem mounted at path in container of pod
+// and increases the storage request of pvc when it has passed the threshold of
+// autoGrow. The resize admission plugin rejects the increase when the
+// StorageClass of pvc does not allow volume expansion; that is reported as a
+// condition and event. A volume that cannot grow any further is reported as an
+// event when it first passes its threshold.
+func (r *Reconciler) autoGrowVolume(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+	autoGrow *v1beta1.VolumeAutoGrow, pvc *corev1.PersistentVolumeClaim,
+	pod *corev1.Pod, container, path string,
+) error {
+	log := logging.FromContext(ctx).WithValues("volume", pvc.Name)
+
+	// The request can only change after the volume is bound. The filesystem
+	// reports its old size until any earlier increase is finished; measuring it
+	// before then would increase the request again.
+	if pvc.Status.Phase != corev1.ClaimBound || volumeResizePending(pvc) {
+		return nil
+	}
+
+	// NOTE: Calling PodExec may still fail due to a missing or stopped
+	// container. That is not an error for the cluster; try again later.
+	var stdout bytes.Buffer
+	err := errors.WithStack(r.PodExec(pod.Namespace, pod.Name, container,
+		nil, &stdout, nil, "df", "--block-size=1", "--output=size,used", path))
+
+	var usage volumeUsage
+	if err == nil {
+		usage, err = parseVolumeUsage(stdout.String())
+	}
+	if err != nil {
+		log.V(1).Info("unable to measure filesystem usage", "error", err.Error())
+		return nil
+	}
+
+	current := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	next, grow := nextVolumeRequest(autoGrow, current, usage)
+
+	if !grow {
+		full := usage.Size > 0 && usage.Used*100 >= int64(autoGrow.Threshold)*usage.Size
+
+		// Warn once each time the volume passes its threshold.
+		if setVolumeAutoGrowBlocked(cluster, pvc.Name, full) && full {
+			if autoGrow.Increase.Sign() <= 0 {
+				r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "VolumeAutoGrowIncrease",
+					"Volume %s is %d%% full and cannot grow by its increase of %s",
+					pvc.Name, usage.Used*100/usage.Size, autoGrow.Increase.String())
+			} else {
+				r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "VolumeAutoGrowLimit",
+					"Volume %s is %d%% full and cannot grow beyond its limit of %s",
+					pvc.Name, usage.Used*100/usage.Size, autoGrow.Limit.String())
+			}
+		}
+		return nil
+	}
+
+	patch := kubeapi.NewMergePatch().
+		Add("spec", "resources", "requests", "storage")(next.String())
+
+	err = errors.WithStack(r.patch(ctx, pvc.DeepCopy(), patch))
+	if err != nil {
+		return r.handlePersistentVolumeClaimError(cluster, err)
+	}
+
+	setVolumeAutoGrowBlocked(cluster, pvc.Name, false)
+	r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "VolumeAutoGrow",
+		"Volume %s is %d%% full; increasing its request from %s to %s",
+		pvc.Name, usage.Used*100/usage.Size, current.String(), next.String())
+
+	return nil
+}
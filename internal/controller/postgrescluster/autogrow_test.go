@@ -0,0 +1,364 @@
+/*
+ Copyright 2021 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package postgrescluster
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+func TestParseVolumeUsage(t *testing.T) {
+	usage, err := parseVolumeUsage("" +
+		"   1B-blocks       Used\n" +
+		"10724835328 9663676416\n")
+	assert.NilError(t, err)
+	assert.Equal(t, usage, volumeUsage{Size: 10724835328, Used: 9663676416})
+
+	for _, input := range []string{
+		"",
+		"   1B-blocks       Used\n",
+		"   1B-blocks       Used\n10724835328\n",
+		"   1B-blocks       Used\nsome thing\n",
+	} {
+		_, err := parseVolumeUsage(input)
+		assert.Assert(t, err != nil, "expected error for %q", input)
+	}
+}
+
+func TestNextVolumeRequest(t *testing.T) {
+	autoGrow := &v1beta1.VolumeAutoGrow{
+		Increase:  resource.MustParse("2Gi"),
+		Limit:     resource.MustParse("5Gi"),
+		Threshold: 80,
+	}
+
+	t.Run("BelowThreshold", func(t *testing.T) {
+		_, grow := nextVolumeRequest(autoGrow, resource.MustParse("1Gi"),
+			volumeUsage{Size: 100, Used: 79})
+		assert.Assert(t, !grow)
+	})
+
+	t.Run("UnknownSize", func(t *testing.T) {
+		_, grow := nextVolumeRequest(autoGrow, resource.MustParse("1Gi"),
+			volumeUsage{Size: 0, Used: 0})
+		assert.Assert(t, !grow)
+	})
+
+	t.Run("AboveThreshold", func(t *testing.T) {
+		next, grow := nextVolumeRequest(autoGrow, resource.MustParse("1Gi"),
+			volumeUsage{Size: 100, Used: 80})
+		assert.Assert(t, grow)
+		assert.Equal(t, next.String(), "3Gi")
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		next, grow := nextVolumeRequest(autoGrow, resource.MustParse("4Gi"),
+			volumeUsage{Size: 100, Used: 95})
+		assert.Assert(t, grow)
+		assert.Equal(t, next.String(), "5Gi")
+
+		_, grow = nextVolumeRequest(autoGrow, resource.MustParse("5Gi"),
+			volumeUsage{Size: 100, Used: 95})
+		assert.Assert(t, !grow)
+	})
+}
+
+func TestKeepVolumeRequest(t *testing.T) {
+	spec := corev1.PersistentVolumeClaimSpec{
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceStorage: resource.MustParse("1Gi"),
+			},
+		},
+	}
+
+	existing := &corev1.PersistentVolumeClaim{}
+	existing.Spec.Resources.Requests = corev1.ResourceList{
+		corev1.ResourceStorage: resource.MustParse("3Gi"),
+	}
+
+	t.Run("Missing", func(t *testing.T) {
+		intent := &corev1.PersistentVolumeClaim{Spec: spec}
+		keepVolumeRequest(intent, nil)
+
+		request := intent.Spec.Resources.Requests[corev1.ResourceStorage]
+		assert.Equal(t, request.String(), "1Gi")
+	})
+
+	// The request is kept whether or not the volume is configured to grow;
+	// it may have grown before autoGrow was disabled.
+	t.Run("Larger", func(t *testing.T) {
+		intent := &corev1.PersistentVolumeClaim{Spec: spec}
+		keepVolumeRequest(intent, existing)
+
+		request := intent.Spec.Resources.Requests[corev1.ResourceStorage]
+		assert.Equal(t, request.String(), "3Gi")
+
+		// The original spec is unchanged.
+		request = spec.Resources.Requests[corev1.ResourceStorage]
+		assert.Equal(t, request.String(), "1Gi")
+	})
+
+	t.Run("Smaller", func(t *testing.T) {
+		intent := &corev1.PersistentVolumeClaim{Spec: *spec.DeepCopy()}
+		intent.Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse("4Gi")
+		keepVolumeRequest(intent, existing)
+
+		request := intent.Spec.Resources.Requests[corev1.ResourceStorage]
+		assert.Equal(t, request.String(), "4Gi")
+	})
+}
+
+func TestSetVolumeAutoGrowBlocked(t *testing.T) {
+	cluster := &v1beta1.PostgresCluster{}
+
+	assert.Assert(t, !setVolumeAutoGrowBlocked(cluster, "a", false))
+	assert.Assert(t, cluster.Status.VolumeAutoGrowBlocked == nil)
+
+	assert.Assert(t, setVolumeAutoGrowBlocked(cluster, "b", true))
+	assert.Assert(t, setVolumeAutoGrowBlocked(cluster, "a", true))
+	assert.Assert(t, !setVolumeAutoGrowBlocked(cluster, "a", true))
+	assert.DeepEqual(t, cluster.Status.VolumeAutoGrowBlocked, []string{"a", "b"})
+
+	assert.Assert(t, setVolumeAutoGrowBlocked(cluster, "a", false))
+	assert.Assert(t, setVolumeAutoGrowBlocked(cluster, "b", false))
+	assert.Assert(t, cluster.Status.VolumeAutoGrowBlocked == nil)
+}
+
+func TestVolumeAutoGrowEnabled(t *testing.T) {
+	cluster := &v1beta1.PostgresCluster{}
+	cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{{Name: "00"}}
+	cluster.Spec.Backups.PGBackRest.Repos = []v1beta1.PGBackRestRepo{
+		{Name: "repo1", Volume: &v1beta1.RepoPVC{}},
+	}
+	assert.Assert(t, !volumeAutoGrowEnabled(cluster))
+
+	cluster.Spec.InstanceSets[0].WALVolumeAutoGrow = &v1beta1.VolumeAutoGrow{}
+	assert.Assert(t, volumeAutoGrowEnabled(cluster))
+
+	cluster.Spec.InstanceSets[0].WALVolumeAutoGrow = nil
+	cluster.Spec.Backups.PGBackRest.Repos[0].Volume.AutoGrow = &v1beta1.VolumeAutoGrow{}
+	assert.Assert(t, volumeAutoGrowEnabled(cluster))
+}
+
+func TestVolumeResizePending(t *testing.T) {
+	pvc := &corev1.PersistentVolumeClaim{}
+	pvc.Spec.Resources.Requests = corev1.ResourceList{
+		corev1.ResourceStorage: resource.MustParse("2Gi"),
+	}
+
+	t.Run("NoCapacity", func(t *testing.T) {
+		assert.Assert(t, volumeResizePending(pvc))
+	})
+
+	pvc.Status.Capacity = corev1.ResourceList{
+		corev1.ResourceStorage: resource.MustParse("1Gi"),
+	}
+
+	t.Run("SmallerCapacity", func(t *testing.T) {
+		assert.Assert(t, volumeResizePending(pvc))
+	})
+
+	pvc.Status.Capacity[corev1.ResourceStorage] = resource.MustParse("2Gi")
+
+	t.Run("Fulfilled", func(t *testing.T) {
+		assert.Assert(t, !volumeResizePending(pvc))
+	})
+
+	for _, conditionType := range []corev1.PersistentVolumeClaimConditionType{
+		corev1.PersistentVolumeClaimResizing,
+		corev1.PersistentVolumeClaimFileSystemResizePending,
+	} {
+		t.Run(string(conditionType), func(t *testing.T) {
+			pvc := pvc.DeepCopy()
+			pvc.Status.Conditions = []corev1.PersistentVolumeClaimCondition{{
+				Type: conditionType, Status: corev1.ConditionTrue,
+			}}
+			assert.Assert(t, volumeResizePending(pvc))
+
+			pvc.Status.Conditions[0].Status = corev1.ConditionFalse
+			assert.Assert(t, !volumeResizePending(pvc))
+		})
+	}
+}
+
+func TestReconcileVolumeAutoGrow(t *testing.T) {
+	ctx := context.Background()
+
+	var calls int
+	usage := "1B-blocks Used\n100 10\n"
+	recorder := record.NewFakeRecorder(10)
+	r := &Reconciler{
+		Recorder: recorder,
+		PodExec: func(
+			namespace, pod, container string,
+			_ io.Reader, stdout, _ io.Writer, command ...string,
+		) error {
+			calls++
+			assert.Equal(t, pod, "hippo-00-abcd-0")
+			assert.Equal(t, command[0], "df")
+			_, _ = stdout.Write([]byte(usage))
+			return nil
+		},
+	}
+
+	cluster := &v1beta1.PostgresCluster{}
+	cluster.Name = "hippo"
+	cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{{
+		Name: "00",
+		DataVolumeAutoGrow: &v1beta1.VolumeAutoGrow{
+			Increase: resource.MustParse("1Gi"), Limit: resource.MustParse("5Gi"),
+			Threshold: 80,
+		},
+	}}
+
+	pod := &corev1.Pod{}
+	pod.Name = "hippo-00-abcd-0"
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: naming.ContainerDatabase}}
+	pod.Status.ContainerStatuses[0].State.Running = new(corev1.ContainerStateRunning)
+	instances := &observedInstances{forCluster: []*Instance{{
+		Name: "hippo-00-abcd", Spec: &cluster.Spec.InstanceSets[0], Pods: []*corev1.Pod{pod},
+	}}}
+
+	volume := corev1.PersistentVolumeClaim{}
+	volume.Name = "hippo-00-abcd-pgdata"
+	volume.Labels = map[string]string{
+		naming.LabelCluster:  "hippo",
+		naming.LabelInstance: "hippo-00-abcd",
+		naming.LabelRole:     naming.RolePostgresData,
+	}
+	volume.Spec.Resources.Requests = corev1.ResourceList{
+		corev1.ResourceStorage: resource.MustParse("2Gi"),
+	}
+	volume.Status.Phase = corev1.ClaimBound
+	volume.Status.Capacity = corev1.ResourceList{
+		corev1.ResourceStorage: resource.MustParse("1Gi"),
+	}
+	volumes := []corev1.PersistentVolumeClaim{volume}
+
+	t.Run("ResizePending", func(t *testing.T) {
+		result, err := r.reconcileVolumeAutoGrow(ctx, cluster, instances, volumes)
+		assert.NilError(t, err)
+		assert.Equal(t, result.RequeueAfter, volumeAutoGrowInterval)
+		assert.Equal(t, calls, 0, "expected no measurement while resizing")
+		assert.Assert(t, cluster.Status.VolumeAutoGrowObservedTime != nil)
+	})
+
+	volumes[0].Status.Capacity[corev1.ResourceStorage] = resource.MustParse("2Gi")
+
+	t.Run("Interval", func(t *testing.T) {
+		result, err := r.reconcileVolumeAutoGrow(ctx, cluster, instances, volumes)
+		assert.NilError(t, err)
+		assert.Assert(t, result.RequeueAfter > 0)
+		assert.Assert(t, result.RequeueAfter <= volumeAutoGrowInterval)
+		assert.Equal(t, calls, 0, "expected no measurement before the interval")
+	})
+
+	t.Run("Measured", func(t *testing.T) {
+		earlier := metav1.NewTime(time.Now().Add(-volumeAutoGrowInterval))
+		cluster.Status.VolumeAutoGrowObservedTime = &earlier
+
+		result, err := r.reconcileVolumeAutoGrow(ctx, cluster, instances, volumes)
+		assert.NilError(t, err)
+		assert.Equal(t, result.RequeueAfter, volumeAutoGrowInterval)
+		assert.Equal(t, calls, 1)
+		assert.Assert(t, earlier.Before(cluster.Status.VolumeAutoGrowObservedTime))
+	})
+
+	// expire pretends the interval has passed since volumes were measured.
+	expire := func() {
+		earlier := metav1.NewTime(time.Now().Add(-volumeAutoGrowInterval))
+		cluster.Status.VolumeAutoGrowObservedTime = &earlier
+	}
+
+	t.Run("Limit", func(t *testing.T) {
+		autoGrow := cluster.Spec.InstanceSets[0].DataVolumeAutoGrow
+		autoGrow.Limit = resource.MustParse("2Gi")
+		t.Cleanup(func() { autoGrow.Limit = resource.MustParse("5Gi") })
+		usage = "1B-blocks Used\n100 95\n"
+
+		expire()
+		_, err := r.reconcileVolumeAutoGrow(ctx, cluster, instances, volumes)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, cluster.Status.VolumeAutoGrowBlocked, []string{"hippo-00-abcd-pgdata"})
+		assert.Equal(t, len(recorder.Events), 1)
+		event := <-recorder.Events
+		assert.Assert(t, strings.HasPrefix(event, "Warning VolumeAutoGrowLimit"), "got %q", event)
+
+		// The Event is recorded once while the volume stays full.
+		expire()
+		_, err = r.reconcileVolumeAutoGrow(ctx, cluster, instances, volumes)
+		assert.NilError(t, err)
+		assert.Equal(t, len(recorder.Events), 0)
+
+		// The volume is no longer blocked when it is below its threshold.
+		usage = "1B-blocks Used\n100 10\n"
+		expire()
+		_, err = r.reconcileVolumeAutoGrow(ctx, cluster, instances, volumes)
+		assert.NilError(t, err)
+		assert.Assert(t, cluster.Status.VolumeAutoGrowBlocked == nil)
+		assert.Equal(t, len(recorder.Events), 0)
+	})
+
+	t.Run("ZeroIncrease", func(t *testing.T) {
+		autoGrow := cluster.Spec.InstanceSets[0].DataVolumeAutoGrow
+		autoGrow.Increase = resource.MustParse("0")
+		t.Cleanup(func() { autoGrow.Increase = resource.MustParse("1Gi") })
+		usage = "1B-blocks Used\n100 95\n"
+		t.Cleanup(func() { usage = "1B-blocks Used\n100 10\n" })
+
+		expire()
+		_, err := r.reconcileVolumeAutoGrow(ctx, cluster, instances, volumes)
+		assert.NilError(t, err)
+		assert.Equal(t, len(recorder.Events), 1)
+		event := <-recorder.Events
+		assert.Assert(t, strings.HasPrefix(event, "Warning VolumeAutoGrowIncrease"), "got %q", event)
+	})
+
+	t.Run("Removed", func(t *testing.T) {
+		cluster.Status.VolumeAutoGrowBlocked = []string{"gone"}
+
+		expire()
+		_, err := r.reconcileVolumeAutoGrow(ctx, cluster, instances, volumes)
+		assert.NilError(t, err)
+		assert.Assert(t, cluster.Status.VolumeAutoGrowBlocked == nil)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.InstanceSets[0].DataVolumeAutoGrow = nil
+		cluster.Status.VolumeAutoGrowBlocked = []string{"hippo-00-abcd-pgdata"}
+
+		result, err := r.reconcileVolumeAutoGrow(ctx, cluster, instances, volumes)
+		assert.NilError(t, err)
+		assert.Equal(t, result, reconcile.Result{})
+		assert.Assert(t, cluster.Status.VolumeAutoGrowObservedTime == nil)
+		assert.Assert(t, cluster.Status.VolumeAutoGrowBlocked == nil)
+	})
+}
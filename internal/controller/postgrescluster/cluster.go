@@ -230,6 +230,11 @@ func (r *Reconciler) generateClusterReplicaService(
 		naming.LabelRole:    naming.RolePatroniReplica,
 	}
 
+	// Leave out instances that are tagged "noloadbalance" when there are any.
+	if patroni.AnyNoLoadBalance(cluster) {
+		service.Spec.Selector[naming.LabelLoadBalance] = "true"
+	}
+
 	// The TargetPort must be the name (not the number) of the PostgreSQL
 	// ContainerPort. This name allows the port number to differ between Pods,
 	// which can happen during a rolling update.
@@ -280,7 +285,7 @@ func (r *Reconciler) reconcileDataSource(ctx context.Context,
 
 	// observe all resources currently relevant to reconciling data sources, and update status
 	// accordingly
-	endpoints, restoreJob, err := r.observeRestoreEnv(ctx, cluster)
+	currentDCS, restoreJob, err := r.observeRestoreEnv(ctx, cluster)
 	if err != nil {
 		return false, errors.WithStack(err)
 	}
@@ -352,6 +357,33 @@ func (r *Reconciler) reconcileDataSource(ctx context.Context,
 			(configHash != restoreJob.GetAnnotations()[naming.PGBackRestConfigHash])
 	}
 
+	// Only the state Patroni keeps in Kubernetes can be removed before the
+	// cluster bootstraps again; see observeRestoreEnv. Record a new request as
+	// finished so that the Event is emitted once.
+	if dcs := patroni.DCSType(cluster); restoreInPlaceRequested && !restoringInPlace &&
+		dcs == "Etcd" {
+		if restoreIDChanged {
+			r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "RestoreInPlaceUnsupported",
+				"in-place restore is not supported when Patroni uses %s", dcs)
+		}
+		if cluster.Status.PGBackRest == nil {
+			cluster.Status.PGBackRest = &v1beta1.PGBackRestStatus{}
+		}
+		if restoreIDChanged {
+			cluster.Status.PGBackRest.Restore = &v1beta1.PGBackRestJobStatus{
+				ID: restoreID, Finished: true,
+			}
+		}
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			ObservedGeneration: cluster.GetGeneration(),
+			Type:               ConditionPGBackRestRestoreProgressing,
+			Status:             metav1.ConditionFalse,
+			Reason:             "RestoreInPlaceUnsupported",
+			Message:            "In-place restore is not supported when Patroni uses " + dcs,
+		})
+		return false, nil
+	}
+
 	// Proceed with preparing the cluster for restore (e.g. tearing down runners, the DCS,
 	// etc.) if:
 	// - A restore is already in progress, but the cluster has not yet been prepared
@@ -359,7 +391,7 @@ func (r *Reconciler) reconcileDataSource(ctx context.Context,
 	// - The restore ID has changed (i.e. the user provide a new value for the restore
 	//   annotation, indicating they want a new in-place restore)
 	if (restoringInPlace && (!readyForRestore || configChanged)) || restoreIDChanged {
-		if err := r.prepareForRestore(ctx, cluster, observed, endpoints,
+		if err := r.prepareForRestore(ctx, cluster, observed, currentDCS,
 			restoreJob, restoreID); err != nil {
 			return true, err
 		}
@@ -32,13 +32,16 @@ import (
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -905,4 +908,62 @@ postgres-operator.crunchydata.com/cluster: pg2
 postgres-operator.crunchydata.com/role: replica
 		`))
 	})
+
+	t.Run("NoLoadBalance", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{
+			{Name: "one"},
+			{Name: "two", PatroniTags: &v1beta1.PatroniTags{NoLoadBalance: true}},
+		}
+
+		service, err := reconciler.generateClusterReplicaService(cluster)
+		assert.NilError(t, err)
+
+		// Only instances that allow load balancing are selected.
+		assert.Assert(t, marshalMatches(service.Spec.Selector, `
+postgres-operator.crunchydata.com/cluster: pg2
+postgres-operator.crunchydata.com/load-balance: "true"
+postgres-operator.crunchydata.com/role: replica
+		`))
+	})
+}
+
+func TestReconcileDataSourceRestoreInPlaceDCS(t *testing.T) {
+	ctx := context.Background()
+	recorder := record.NewFakeRecorder(10)
+	r := &Reconciler{Client: fake.NewClientBuilder().Build(), Recorder: recorder}
+
+	cluster := testCluster()
+	cluster.Namespace = "ns1"
+	cluster.Annotations = map[string]string{naming.PGBackRestRestore: "one"}
+	cluster.Spec.Backups.PGBackRest.Restore = &v1beta1.PGBackRestRestore{
+		Enabled: initialize.Bool(true),
+		PostgresClusterDataSource: &v1beta1.PostgresClusterDataSource{
+			RepoName: "repo1",
+		},
+	}
+
+	// Patroni keeps its state in etcd, which the operator cannot remove.
+	cluster.Status.Patroni.DCS = "Etcd"
+
+	returnEarly, err := r.reconcileDataSource(ctx, cluster, &observedInstances{}, nil)
+	assert.NilError(t, err)
+	assert.Assert(t, !returnEarly)
+
+	condition := meta.FindStatusCondition(cluster.Status.Conditions,
+		ConditionPGBackRestRestoreProgressing)
+	assert.Assert(t, condition != nil)
+	assert.Equal(t, condition.Status, metav1.ConditionFalse)
+	assert.Equal(t, condition.Reason, "RestoreInPlaceUnsupported")
+	assert.Equal(t, cluster.Status.PGBackRest.Restore.ID, "one")
+
+	assert.Equal(t, len(recorder.Events), 1)
+	event := <-recorder.Events
+	assert.Assert(t, strings.Contains(event, "Etcd"), event)
+
+	// The same request is rejected only once.
+	returnEarly, err = r.reconcileDataSource(ctx, cluster, &observedInstances{}, nil)
+	assert.NilError(t, err)
+	assert.Assert(t, !returnEarly)
+	assert.Equal(t, len(recorder.Events), 0)
 }
@@ -26,6 +26,7 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -68,6 +69,8 @@ type Reconciler struct {
 		namespace, pod, container string,
 		stdin io.Reader, stdout, stderr io.Writer, command ...string,
 	) error
+
+	patroniClients patroniHTTPClients
 }
 
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
@@ -100,6 +103,8 @@ func (r *Reconciler) Reconcile(
 		if err = client.IgnoreNotFound(err); err != nil {
 			log.Error(err, "unable to fetch PostgresCluster")
 			span.RecordError(err)
+		} else {
+			r.forgetPatroniHTTPClient(request.NamespacedName)
 		}
 		return result, err
 	}
@@ -204,11 +209,23 @@ func (r *Reconciler) Reconcile(
 	if err == nil {
 		instances, err = r.observeInstances(ctx, cluster)
 	}
+	if err == nil {
+		err = r.reconcilePatroniDCS(ctx, cluster, instances)
+	}
 	if err == nil {
 		err = updateResult(r.reconcilePatroniStatus(ctx, cluster, instances))
 	}
 	if err == nil {
-		err = r.reconcilePatroniSwitchover(ctx, cluster, instances)
+		result = updateReconcileResult(result, r.reconcilePatroniMembers(ctx, cluster, instances))
+	}
+	if err == nil {
+		err = updateResult(r.reconcilePatroniReinitialize(ctx, cluster, instances))
+	}
+	if err == nil {
+		err = r.reconcilePatroniPrimary(ctx, cluster, instances)
+	}
+	if err == nil {
+		err = updateResult(r.reconcilePatroniSwitchover(ctx, cluster, instances))
 	}
 	// reconcile the Pod service before reconciling any data source in case it is necessary
 	// to start Pods during data source reconciliation that require network connections (e.g.
@@ -303,6 +320,15 @@ func (r *Reconciler) Reconcile(
 	if err == nil {
 		err = updateResult(r.reconcilePGBackRest(ctx, cluster, instances))
 	}
+	if err == nil {
+		result = updateReconcileResult(result, r.reconcileArchiving(ctx, cluster, instances))
+	}
+	if err == nil {
+		err = updateResult(r.reconcileVolumeAutoGrow(ctx, cluster, instances, clusterVolumes))
+	}
+	if err == nil {
+		result = updateReconcileResult(result, r.reconcileReplayDelay(ctx, cluster, instances))
+	}
 	if err == nil {
 		err = r.reconcilePGBouncer(ctx, cluster, instances, primaryCertificate, rootCA)
 	}
@@ -393,6 +419,7 @@ func (r *Reconciler) setOwnerReference(
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=get;list;watch
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;list;watch
 // +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch
 
 // SetupWithManager adds the PostgresCluster controller to the provided runtime manager
 func (r *Reconciler) SetupWithManager(mgr manager.Manager) error {
@@ -421,6 +448,7 @@ func (r *Reconciler) SetupWithManager(mgr manager.Manager) error {
 		Owns(&rbacv1.Role{}).
 		Owns(&rbacv1.RoleBinding{}).
 		Owns(&batchv1beta1.CronJob{}).
+		Owns(&policyv1beta1.PodDisruptionBudget{}).
 		Watches(&source.Kind{Type: &corev1.Pod{}}, r.watchPods()).
 		Watches(&source.Kind{Type: &appsv1.StatefulSet{}},
 			r.controllerRefHandlerFuncs()). // watch all StatefulSets
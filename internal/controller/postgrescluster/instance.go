@@ -29,7 +29,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -164,8 +164,10 @@ func (i Instance) IsWritable() (writable, known bool) {
 	member := i.Pods[0].Annotations["status"]
 	role := strings.Index(member, `"role":`)
 
+	// Patroni writes the "status" annotation only when it uses Kubernetes for
+	// DCS. Otherwise, its callback labels the Pod and records the role.
 	if role < 0 {
-		return false, false
+		return i.isWritableByCallback()
 	}
 
 	// TODO(cbandy): Update this to consider when Patroni is paused.
@@ -173,6 +175,33 @@ func (i Instance) IsWritable() (writable, known bool) {
 	return strings.HasPrefix(member[role:], `"role":"master"`), true
 }
 
+// isWritableByCallback returns whether or not the most recent Patroni callback
+// of this instance reports a writable primary.
+func (i Instance) isWritableByCallback() (writable, known bool) {
+	callback, ok := patroni.PodCallback(i.Pods[0])
+	if !ok || i.Pods[0].Labels[naming.LabelRole] == "" {
+		return false, false
+	}
+
+	// A primary that crashes keeps its role label and callback. Trust them only
+	// when the callback ran since the database container last started.
+	for _, status := range i.Pods[0].Status.ContainerStatuses {
+		if status.Name == naming.ContainerDatabase {
+			if status.State.Running == nil {
+				return false, true
+			}
+			if callback.Time.Before(&status.State.Running.StartedAt) {
+				return false, false
+			}
+		}
+	}
+
+	// PostgreSQL is stopped after "on_stop", and a standby leader is read-only.
+	return callback.Action != "on_stop" &&
+		(callback.Role == "master" || callback.Role == "primary") &&
+		i.Pods[0].Labels[naming.LabelRole] == naming.RolePatroniLeader, true
+}
+
 // PodMatchesPodTemplate returns whether or not the Pod for this instance
 // matches its specified PodTemplate. When it does not match, the Pod needs to
 // be redeployed.
@@ -348,10 +377,18 @@ func (r *Reconciler) observeInstances(
 
 	observed := newObservedInstances(cluster, runners.Items, pods.Items)
 
+	// Keep measurements that are not taken by this function.
+	previous := make(map[string]v1beta1.PostgresInstanceSetStatus)
+	for _, status := range cluster.Status.InstanceSets {
+		previous[status.Name] = status
+	}
+
 	// Fill out status sorted by set name.
 	cluster.Status.InstanceSets = cluster.Status.InstanceSets[:0]
 	for _, name := range observed.setNames.List() {
 		status := v1beta1.PostgresInstanceSetStatus{Name: name}
+		status.ReplayDelay = previous[name].ReplayDelay
+		status.ReplayDelayObservedTime = previous[name].ReplayDelayObservedTime
 		for _, instance := range observed.bySet[name] {
 			if ready, known := instance.IsReady(); known && ready {
 				status.ReadyReplicas++
@@ -588,6 +625,18 @@ func (r *Reconciler) reconcileInstanceSets(
 		if err != nil {
 			return err
 		}
+
+		err = r.reconcileInstanceSetPodDisruptionBudget(
+			ctx, cluster, &cluster.Spec.InstanceSets[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	// Remove the PodDisruptionBudgets of any instance sets that have been
+	// removed from the spec.
+	if err := r.cleanupPodDisruptionBudgets(ctx, cluster); err != nil {
+		return err
 	}
 
 	// Scaledown is called on the whole cluster in order to consider all
@@ -607,6 +656,51 @@ func (r *Reconciler) reconcileInstanceSets(
 	return err
 }
 
+// reconcileInstanceSetPodDisruptionBudget writes the PodDisruptionBudget
+// that limits voluntary evictions of the Pods in set. See getMinAvailable for
+// how the minimum is determined when it is not specified.
+func (r *Reconciler) reconcileInstanceSetPodDisruptionBudget(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+	set *v1beta1.PostgresInstanceSetSpec,
+) error {
+	return r.reconcileComponentPodDisruptionBudget(ctx, cluster,
+		naming.InstanceSet(cluster, set),
+		naming.ClusterInstanceSet(cluster.Name, set.Name).MatchLabels,
+		set.Metadata, *set.Replicas, set.MinAvailable)
+}
+
+// +kubebuilder:rbac:groups="policy",resources="poddisruptionbudgets",verbs={list}
+
+// cleanupPodDisruptionBudgets deletes the PodDisruptionBudgets of instance
+// sets that are no longer in the spec of cluster.
+func (r *Reconciler) cleanupPodDisruptionBudgets(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+) error {
+	specified := sets.NewString()
+	for i := range cluster.Spec.InstanceSets {
+		specified.Insert(cluster.Spec.InstanceSets[i].Name)
+	}
+
+	pdbs := &policyv1beta1.PodDisruptionBudgetList{}
+	selector, err := naming.AsSelector(naming.ClusterInstanceSets(cluster.Name))
+	if err == nil {
+		err = errors.WithStack(
+			r.Client.List(ctx, pdbs,
+				client.InNamespace(cluster.Namespace),
+				client.MatchingLabelsSelector{Selector: selector},
+			))
+	}
+
+	for i := range pdbs.Items {
+		if err == nil && !specified.Has(pdbs.Items[i].Labels[naming.LabelInstanceSet]) {
+			err = errors.WithStack(client.IgnoreNotFound(
+				r.deleteControlled(ctx, cluster, &pdbs.Items[i])))
+		}
+	}
+
+	return err
+}
+
 // TODO (andrewlecuyer): If relevant instance volume (PVC) information is captured for each
 // Instance contained within observedInstances, this function might no longer be necessary.
 // Instead, available names could be derived by looking at observed Instances that have data
@@ -699,6 +793,14 @@ func (r *Reconciler) rolloutInstance(
 	primary, known := instance.IsPrimary()
 	primary = primary && known
 
+	// Count the other instances that Patroni may promote.
+	candidates := 0
+	for _, other := range instances.forCluster {
+		if other != instance && !patroni.NoFailover(other.Spec) {
+			candidates++
+		}
+	}
+
 	// When the cluster has more than one instance participating in failover,
 	// perform a controlled switchover to one of those instances. Patroni will
 	// choose the best candidate and demote the primary. It stops PostgreSQL
@@ -710,12 +812,17 @@ func (r *Reconciler) rolloutInstance(
 	//
 	// NOTE(cbandy): The StatefulSet controlling this Pod reflects this change
 	// in its Status and triggers another reconcile.
-	if primary && len(instances.forCluster) > 1 {
+	if primary && candidates > 0 {
 		var span trace.Span
 		ctx, span = r.Tracer.Start(ctx, "patroni-change-primary")
 		defer span.End()
 
-		success, err := patroni.Executor(exec).ChangePrimaryAndWait(ctx, pod.Name, "")
+		api, err := r.patroniAPI(ctx, cluster, pod)
+
+		var success bool
+		if err == nil {
+			success, err = api.ChangePrimaryAndWait(ctx, pod.Name, "")
+		}
 		if err = errors.WithStack(err); err == nil && !success {
 			err = errors.New("unable to switchover")
 		}
@@ -808,6 +915,16 @@ func (r *Reconciler) rolloutInstances(
 	ctx, span := r.Tracer.Start(ctx, "rollout-instances")
 	defer span.End()
 
+	// Patroni does not change the primary while paused, so instances cannot
+	// be redeployed safely. Wait until it resumes.
+	if patroni.ClusterPaused(cluster) {
+		return nil
+	}
+
+	// Redeploying the primary changes it. When asked, wait for WAL archiving
+	// to succeed so that WAL files it has not archived are not lost.
+	pausePrimary := archivingPausesPrimary(cluster)
+
 	for _, set := range cluster.Spec.InstanceSets {
 		numSpecified += int(*set.Replicas)
 	}
@@ -855,6 +972,9 @@ func (r *Reconciler) rolloutInstances(
 	// unavailable instances.
 	// - https://issue.k8s.io/67250
 	for _, instance := range consider {
+		if primary, known := instance.IsPrimary(); pausePrimary && (primary || !known) {
+			continue
+		}
 		if err == nil {
 			if available, known := instance.IsAvailable(); known && !available {
 				err = redeploy(ctx, instance)
@@ -869,6 +989,24 @@ func (r *Reconciler) rolloutInstances(
 	return err
 }
 
+// archivingPausesPrimary returns whether or not cluster asks to wait for WAL
+// archiving before redeploying its primary and archiving is failing. A failure
+// older than archivingFailureStale no longer counts; see its description.
+func archivingPausesPrimary(cluster *v1beta1.PostgresCluster) bool {
+	if !cluster.Spec.Backups.PGBackRest.PauseRolloutsOnArchiveFailure ||
+		!meta.IsStatusConditionFalse(cluster.Status.Conditions, ConditionArchivingHealthy) {
+		return false
+	}
+
+	var archiving *v1beta1.PGBackRestArchivingStatus
+	if cluster.Status.PGBackRest != nil {
+		archiving = cluster.Status.PGBackRest.Archiving
+	}
+
+	return archiving != nil && archiving.LastFailedTime != nil &&
+		time.Since(archiving.LastFailedTime.Time) < archivingFailureStale
+}
+
 // scaleDownInstances removes extra instances from a cluster until it matches
 // the spec. This function can delete the primary instance and force the
 // cluster to failover under two conditions:
@@ -1069,6 +1207,8 @@ func (r *Reconciler) reconcileInstance(
 		instanceCertificates *corev1.Secret
 		postgresDataVolume   *corev1.PersistentVolumeClaim
 		postgresWALVolume    *corev1.PersistentVolumeClaim
+		tablespaceVolumes    map[string]*corev1.PersistentVolumeClaim
+		pgBackRestSpool      *corev1.PersistentVolumeClaim
 	)
 
 	if err == nil {
@@ -1084,6 +1224,12 @@ func (r *Reconciler) reconcileInstance(
 	if err == nil {
 		postgresWALVolume, err = r.reconcilePostgresWALVolume(ctx, cluster, spec, instance, observed, clusterVolumes)
 	}
+	if err == nil {
+		tablespaceVolumes, err = r.reconcilePostgresTablespaceVolumes(ctx, cluster, spec, instance)
+	}
+	if err == nil {
+		pgBackRestSpool, err = r.reconcilePGBackRestSpoolVolume(ctx, cluster, spec, instance)
+	}
 	if err == nil {
 		postgres.InstancePod(
 			ctx, cluster, spec,
@@ -1098,7 +1244,13 @@ func (r *Reconciler) reconcileInstance(
 
 	// Add pgBackRest containers, volumes, etc. to the instance Pod spec
 	if err == nil {
-		err = addPGBackRestToInstancePodSpec(cluster, &instance.Spec.Template)
+		err = addPGBackRestToInstancePodSpec(cluster, &instance.Spec.Template, pgBackRestSpool)
+	}
+
+	// Mount tablespace volumes wherever the data volume is mounted, including
+	// the pgBackRest container.
+	if err == nil {
+		postgres.AddTablespaceVolumes(tablespaceVolumes, &instance.Spec.Template.Spec)
 	}
 
 	// Add pgMonitor resources to the instance Pod spec
@@ -1259,9 +1411,9 @@ func generateInstanceStatefulSetIntent(_ context.Context,
 // includes adding an SSH sidecar if a pgBackRest repoHost is enabled per the current
 // PostgresCluster spec, mounting pgBackRest repo volumes if a dedicated repository is not
 // configured, and then mounting the proper pgBackRest configuration resources (ConfigMaps
-// and Secrets)
+// and Secrets) and the spool volume used for asynchronous archiving
 func addPGBackRestToInstancePodSpec(cluster *v1beta1.PostgresCluster,
-	template *corev1.PodTemplateSpec) error {
+	template *corev1.PodTemplateSpec, spoolVolume *corev1.PersistentVolumeClaim) error {
 
 	dedicatedRepoEnabled := pgbackrest.DedicatedRepoHostEnabled(cluster)
 	pgBackRestConfigContainers := []string{naming.ContainerDatabase}
@@ -1283,10 +1435,75 @@ func addPGBackRestToInstancePodSpec(cluster *v1beta1.PostgresCluster,
 		pgBackRestConfigContainers...); err != nil {
 		return errors.WithStack(err)
 	}
+	if err := pgbackrest.AddSharedRepoVolumesToPod(cluster, template,
+		naming.ContainerDatabase); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := pgbackrest.AddSpoolVolumeToPod(cluster, template, spoolVolume,
+		pgBackRestConfigContainers...); err != nil {
+		return errors.WithStack(err)
+	}
 
 	return nil
 }
 
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=create;delete;patch
+
+// reconcilePGBackRestSpoolVolume writes the PersistentVolumeClaim for instance's pgBackRest
+// spool. It returns nil when the spool is an emptyDir volume or asynchronous archiving is
+// disabled; any existing PVC is deleted then.
+func (r *Reconciler) reconcilePGBackRestSpoolVolume(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+	instanceSpec *v1beta1.PostgresInstanceSetSpec, instance *appsv1.StatefulSet,
+) (*corev1.PersistentVolumeClaim, error) {
+	pvc := &corev1.PersistentVolumeClaim{ObjectMeta: naming.InstancePGBackRestSpoolVolume(instance)}
+	pvc.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim"))
+
+	archiveAsync := cluster.Spec.Backups.PGBackRest.ArchiveAsync
+	if archiveAsync == nil || archiveAsync.VolumeClaimSpec == nil {
+		// No spool volume is specified; delete the PVC if it exists. The spool
+		// only tracks WAL files that are also in pg_wal or the repositories.
+		// The PVC will continue to exist until all Pods using it are deleted.
+		// - https://docs.k8s.io/concepts/storage/persistent-volumes/#storage-object-in-use-protection
+		key := client.ObjectKeyFromObject(pvc)
+		err := errors.WithStack(r.Client.Get(ctx, key, pvc))
+		if err == nil && pvc.DeletionTimestamp == nil {
+			err = errors.WithStack(r.deleteControlled(ctx, cluster, pvc))
+		}
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	err := errors.WithStack(r.setControllerReference(cluster, pvc))
+
+	pvc.Annotations = naming.Merge(
+		cluster.Spec.Metadata.GetAnnotationsOrNil(),
+		instanceSpec.Metadata.GetAnnotationsOrNil(),
+		cluster.Spec.Backups.PGBackRest.Metadata.GetAnnotationsOrNil())
+
+	pvc.Labels = naming.Merge(
+		cluster.Spec.Metadata.GetLabelsOrNil(),
+		instanceSpec.Metadata.GetLabelsOrNil(),
+		cluster.Spec.Backups.PGBackRest.Metadata.GetLabelsOrNil(),
+		map[string]string{
+			naming.LabelCluster:     cluster.Name,
+			naming.LabelInstanceSet: instanceSpec.Name,
+			naming.LabelInstance:    instance.Name,
+			naming.LabelRole:        naming.RolePGBackRestSpool,
+			naming.LabelData:        naming.DataPGBackRest,
+		},
+	)
+
+	pvc.Spec = *archiveAsync.VolumeClaimSpec
+
+	if err == nil {
+		err = r.handlePersistentVolumeClaimError(cluster,
+			errors.WithStack(r.apply(ctx, pvc)))
+	}
+
+	return pvc, err
+}
+
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=create;patch
 
 // reconcileInstanceConfigMap writes the ConfigMap that contains generated
@@ -1402,13 +1619,32 @@ func (r *Reconciler) reconcileUpgradeJob(
 		if cluster.Spec.PostgresVersion > cluster.Spec.Upgrade.FromPostgresVersion {
 			// before creating the upgrade job, observe all resources currently
 			// relevant to reconciling data sources, and update status accordingly
-			endpoints, upgradeJob, err := r.observeUpgradeEnv(ctx, cluster)
+			currentDCS, upgradeJob, err := r.observeUpgradeEnv(ctx, cluster)
 			if err != nil {
 				return false, errors.WithStack(err)
 			}
 
+			// Only the state Patroni keeps in Kubernetes can be removed before
+			// the cluster bootstraps again; see observeUpgradeEnv.
+			if dcs := patroni.DCSType(cluster); upgradeJob == nil && dcs == "Etcd" {
+				condition := meta.FindStatusCondition(cluster.Status.Conditions,
+					ConditionPGUpgradeCompleted)
+				if condition == nil || condition.Reason != "PGUpgradeUnsupported" {
+					r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "pgUpgradeFailed",
+						"major upgrade is not supported when Patroni uses %s", dcs)
+				}
+				meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+					ObservedGeneration: cluster.GetGeneration(),
+					Type:               ConditionPGUpgradeCompleted,
+					Status:             metav1.ConditionFalse,
+					Reason:             "PGUpgradeUnsupported",
+					Message:            "Major upgrade is not supported when Patroni uses " + dcs,
+				})
+				return false, nil
+			}
+
 			if upgradeJob == nil {
-				err = r.prepareForUpgrade(ctx, cluster, observed, endpoints, upgradeJob)
+				err = r.prepareForUpgrade(ctx, cluster, observed, currentDCS, upgradeJob)
 				if err != nil {
 					return false, errors.WithStack(err)
 				}
@@ -1423,11 +1659,11 @@ func (r *Reconciler) reconcileUpgradeJob(
 					return true, nil
 				}
 
-				// After all observed instances are deleted, delete endpoints to
-				// remove the old DCS information.
-				if len(endpoints) > 0 {
-					for i := range endpoints {
-						if err := r.Client.Delete(ctx, &endpoints[i]); client.IgnoreNotFound(err) != nil {
+				// After all observed instances are deleted, delete Endpoints or
+				// ConfigMaps to remove the old DCS information.
+				if len(currentDCS) > 0 {
+					for i := range currentDCS {
+						if err := r.Client.Delete(ctx, currentDCS[i]); client.IgnoreNotFound(err) != nil {
 							return false, errors.WithStack(err)
 						}
 					}
@@ -1473,12 +1709,17 @@ func (r *Reconciler) reconcileUpgradeJob(
 				if err != nil {
 					return false, errors.WithStack(err)
 				}
+				tablespaceVolumes, err := r.reconcilePostgresTablespaceVolumes(ctx, cluster, spec, fakeSTS)
+				if err != nil {
+					return false, errors.WithStack(err)
+				}
 
 				upgradeJob, err := postgres.GenerateUpgradeJobIntent(cluster, sa, spec,
 					clusterCerts, clientCerts, dataVolume, walVolume)
 				if err != nil {
 					return false, err
 				}
+				postgres.AddTablespaceVolumes(tablespaceVolumes, &upgradeJob.Spec.Template.Spec)
 
 				// set gvk and ownership refs
 				upgradeJob.SetGroupVersionKind(batchv1.SchemeGroupVersion.WithKind("Job"))
@@ -1553,34 +1794,14 @@ func (r *Reconciler) reconcileUpgradeJob(
 // created by Patroni (i.e. DCS, leader and failover Endpoints), while then also finding any existing
 // upgrade Jobs and then updating upgrade status accordingly.
 func (r *Reconciler) observeUpgradeEnv(ctx context.Context,
-	cluster *v1beta1.PostgresCluster) ([]corev1.Endpoints, *batchv1.Job, error) {
-
-	// lookup the various patroni endpoints
-	leaderEP, dcsEP, failoverEP := corev1.Endpoints{}, corev1.Endpoints{}, corev1.Endpoints{}
-	currentEndpoints := []corev1.Endpoints{}
-	if err := r.Client.Get(ctx, naming.AsObjectKey(naming.PatroniLeaderEndpoints(cluster)),
-		&leaderEP); err != nil {
-		if !apierrors.IsNotFound(err) {
-			return nil, nil, errors.WithStack(err)
-		}
-	} else {
-		currentEndpoints = append(currentEndpoints, leaderEP)
-	}
-	if err := r.Client.Get(ctx, naming.AsObjectKey(naming.PatroniDistributedConfiguration(cluster)),
-		&dcsEP); err != nil {
-		if !apierrors.IsNotFound(err) {
-			return nil, nil, errors.WithStack(err)
-		}
-	} else {
-		currentEndpoints = append(currentEndpoints, dcsEP)
-	}
-	if err := r.Client.Get(ctx, naming.AsObjectKey(naming.PatroniTrigger(cluster)),
-		&failoverEP); err != nil {
-		if !apierrors.IsNotFound(err) {
-			return nil, nil, errors.WithStack(err)
-		}
-	} else {
-		currentEndpoints = append(currentEndpoints, failoverEP)
+	cluster *v1beta1.PostgresCluster) ([]client.Object, *batchv1.Job, error) {
+
+	// lookup the objects in which Patroni stores its state
+	// NOTE: Major upgrades are rejected when Patroni keeps its state outside of
+	// Kubernetes; see reconcileUpgradeJob.
+	currentDCS, err := r.observePatroniDCS(ctx, cluster)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// check for existing upgrade job, as in reconcileManualBackup
@@ -1588,7 +1809,7 @@ func (r *Reconciler) observeUpgradeEnv(ctx context.Context,
 	if err := r.Client.List(ctx, upgradeJobs, &client.ListOptions{
 		LabelSelector: naming.PGUpgradeJobSelector(cluster.GetName()),
 	}); err != nil {
-		return currentEndpoints, nil, errors.WithStack(err)
+		return currentDCS, nil, errors.WithStack(err)
 	}
 	var upgradeJob *batchv1.Job
 	if len(upgradeJobs.Items) > 1 {
@@ -1646,7 +1867,7 @@ func (r *Reconciler) observeUpgradeEnv(ctx context.Context,
 			})
 		}
 	}
-	return currentEndpoints, upgradeJob, nil
+	return currentDCS, upgradeJob, nil
 }
 
 // +kubebuilder:rbac:groups="",resources=endpoints,verbs=delete
@@ -1656,12 +1877,12 @@ func (r *Reconciler) observeUpgradeEnv(ctx context.Context,
 // prepareForUpgrade is responsible for preparing the cluster before reconciling a
 // major Postgres upgrade of the PostgresCluster. This includes setting conditions,
 // setting the appropriate instance and instance set for startup after the upgrade
-// completes, removing all existing instance runners and any Endpoints created by
-// Patroni and clearing any statuses/conditions related to re-initialization, which
+// completes, removing all existing instance runners and any Endpoints or ConfigMaps
+// created by Patroni and clearing any statuses/conditions related to re-initialization, which
 // will cause the cluster to re-bootstrap using the new data directory.
 func (r *Reconciler) prepareForUpgrade(ctx context.Context,
 	cluster *v1beta1.PostgresCluster, observed *observedInstances,
-	currentEndpoints []corev1.Endpoints, upgradeJob *batchv1.Job) error {
+	currentDCS []client.Object, upgradeJob *batchv1.Job) error {
 
 	setPreparingClusterCondition := func(resource string) {
 		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
@@ -1737,7 +1958,7 @@ func (r *Reconciler) prepareForUpgrade(ctx context.Context,
 	}
 
 	// if everything is gone, proceed with re-bootstrapping the cluster
-	if len(currentEndpoints) == 0 {
+	if len(currentDCS) == 0 {
 		if len(cluster.Status.Conditions) > 0 {
 			// TODO: remove guard with move to controller-runtime 0.9.0 https://issue.k8s.io/99714
 			meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionPostgresDataInitialized)
@@ -22,6 +22,7 @@ import (
 	"io/ioutil"
 	"strings"
 	"testing"
+	"time"
 
 	"go.opentelemetry.io/otel/oteltest"
 	"gotest.tools/v3/assert"
@@ -29,6 +30,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -171,6 +173,52 @@ func TestReconcilerRolloutInstance(t *testing.T) {
 			assert.ErrorContains(t, err, "switchover")
 		})
 	})
+
+	t.Run("NoFailover", func(t *testing.T) {
+		instances := []*Instance{
+			{
+				Name: "primary",
+				Pods: []*corev1.Pod{{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "ns1",
+						Name:      "the-pod",
+						Labels: map[string]string{
+							"controller-revision-hash":               "gamma",
+							"postgres-operator.crunchydata.com/role": "master",
+						},
+					},
+				}},
+				Runner: &appsv1.StatefulSet{},
+			},
+			{
+				Name:   "other",
+				Pods:   []*corev1.Pod{{}},
+				Runner: &appsv1.StatefulSet{},
+				Spec: &v1beta1.PostgresInstanceSetSpec{
+					PatroniTags: &v1beta1.PatroniTags{NoFailover: true},
+				},
+			},
+		}
+		observed := &observedInstances{forCluster: instances}
+
+		key := client.ObjectKey{Namespace: "ns1", Name: "the-pod"}
+		reconciler := &Reconciler{}
+		reconciler.Client = fake.NewClientBuilder().WithObjects(instances[0].Pods[0]).Build()
+		reconciler.Tracer = oteltest.DefaultTracer()
+		reconciler.PodExec = func(
+			_, _, _ string, _ io.Reader, _, _ io.Writer, command ...string,
+		) error {
+			// Checkpoint rather than switchover; no other instance can be promoted.
+			assert.Assert(t, cmp.Contains(strings.Join(command, " "), "psql"))
+			return nil
+		}
+
+		assert.NilError(t, reconciler.rolloutInstance(ctx, cluster, observed, instances[0]))
+
+		err := reconciler.Client.Get(ctx, key, &corev1.Pod{})
+		assert.Assert(t, apierrors.IsNotFound(err),
+			"expected pod to be deleted, got: %#v", err)
+	})
 }
 
 func TestReconcilerRolloutInstances(t *testing.T) {
@@ -298,6 +346,59 @@ func TestReconcilerRolloutInstances(t *testing.T) {
 		assert.NilError(t, reconciler.rolloutInstances(ctx, cluster, observed, accumulate(&redeploys)))
 		assert.Equal(t, len(redeploys), 1)
 		assert.Equal(t, redeploys[0].Name, "one")
+
+		t.Run("Paused", func(t *testing.T) {
+			cluster := cluster.DeepCopy()
+			cluster.Spec.Patroni = &v1beta1.PatroniSpec{Paused: true}
+
+			logSpanAttributes(t)
+			assert.NilError(t, reconciler.rolloutInstances(ctx, cluster, observed,
+				func(context.Context, *Instance) error {
+					t.Fatal("expected no redeploys")
+					return nil
+				}))
+		})
+
+		t.Run("ArchivingFailed", func(t *testing.T) {
+			cluster := cluster.DeepCopy()
+			meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+				Type:   ConditionArchivingHealthy,
+				Status: metav1.ConditionFalse,
+				Reason: "ArchivingFailed",
+			})
+
+			cluster.Status.PGBackRest = &v1beta1.PGBackRestStatus{
+				Archiving: &v1beta1.PGBackRestArchivingStatus{
+					LastFailedTime: &metav1.Time{Time: time.Now().Add(-time.Minute)},
+				},
+			}
+
+			// Rollouts continue unless they are paused by the spec.
+			var redeploys []*Instance
+			assert.NilError(t, reconciler.rolloutInstances(ctx, cluster, observed, accumulate(&redeploys)))
+			assert.Equal(t, len(redeploys), 1)
+
+			cluster.Spec.Backups.PGBackRest.PauseRolloutsOnArchiveFailure = true
+
+			logSpanAttributes(t)
+			assert.NilError(t, reconciler.rolloutInstances(ctx, cluster, observed,
+				func(context.Context, *Instance) error {
+					t.Fatal("expected no redeploys")
+					return nil
+				}))
+
+			t.Run("Stale", func(t *testing.T) {
+				cluster := cluster.DeepCopy()
+				cluster.Status.PGBackRest.Archiving.LastFailedTime =
+					&metav1.Time{Time: time.Now().Add(-time.Hour)}
+
+				// The primary is redeployed when archiving has not failed lately.
+				var redeploys []*Instance
+				assert.NilError(t, reconciler.rolloutInstances(ctx, cluster, observed, accumulate(&redeploys)))
+				assert.Equal(t, len(redeploys), 1)
+				assert.Equal(t, redeploys[0].Name, "one")
+			})
+		})
 	})
 
 	// Two ready instances do not match PodTemplate, no primary.
@@ -439,6 +540,27 @@ func TestReconcilerRolloutInstances(t *testing.T) {
 		assert.NilError(t, reconciler.rolloutInstances(ctx, cluster, observed, accumulate(&redeploys)))
 		assert.Equal(t, len(redeploys), 1)
 		assert.Equal(t, redeploys[0].Name, "not-primary")
+
+		t.Run("ArchivingFailed", func(t *testing.T) {
+			cluster := cluster.DeepCopy()
+			cluster.Spec.Backups.PGBackRest.PauseRolloutsOnArchiveFailure = true
+			cluster.Status.PGBackRest = &v1beta1.PGBackRestStatus{
+				Archiving: &v1beta1.PGBackRestArchivingStatus{
+					LastFailedTime: &metav1.Time{Time: time.Now().Add(-time.Minute)},
+				},
+			}
+			meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+				Type:   ConditionArchivingHealthy,
+				Status: metav1.ConditionFalse,
+				Reason: "ArchivingFailed",
+			})
+
+			// Replicas are redeployed while the primary waits.
+			var redeploys []*Instance
+			assert.NilError(t, reconciler.rolloutInstances(ctx, cluster, observed, accumulate(&redeploys)))
+			assert.Equal(t, len(redeploys), 1)
+			assert.Equal(t, redeploys[0].Name, "not-primary")
+		})
 	})
 
 	// Two instances do not match PodTemplate, one is not ready. Redeploy that one.
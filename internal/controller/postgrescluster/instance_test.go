@@ -34,6 +34,8 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -145,6 +147,83 @@ func TestInstanceIsWritable(t *testing.T) {
 	assert.Assert(t, !writable)
 }
 
+func TestInstanceIsWritableEtcd(t *testing.T) {
+	// Patroni does not write the "status" annotation when it uses etcd. Its
+	// callback labels the Pod and records the role instead.
+	instance := Instance{Pods: []*corev1.Pod{{}}}
+	pod := instance.Pods[0]
+	pod.Annotations = map[string]string{}
+	pod.Labels = map[string]string{}
+
+	set := func(action, role, label string) {
+		pod.Annotations[naming.PatroniCallback] = `{"action":"` + action +
+			`","role":"` + role + `","time":"2021-10-31T12:34:56Z"}`
+		pod.Labels[naming.LabelRole] = label
+	}
+
+	// No label
+	pod.Annotations[naming.PatroniCallback] = `{"action":"on_start","role":"master"}`
+	writable, known := instance.IsWritable()
+	assert.Assert(t, !known)
+	assert.Assert(t, !writable)
+
+	// Patroni leader
+	set("on_role_change", "master", naming.RolePatroniLeader)
+	writable, known = instance.IsWritable()
+	assert.Assert(t, known)
+	assert.Assert(t, writable)
+
+	set("on_start", "primary", naming.RolePatroniLeader)
+	writable, known = instance.IsWritable()
+	assert.Assert(t, known)
+	assert.Assert(t, writable)
+
+	// Patroni replica
+	set("on_role_change", "replica", naming.RolePatroniReplica)
+	writable, known = instance.IsWritable()
+	assert.Assert(t, known)
+	assert.Assert(t, !writable)
+
+	// Patroni standby leader
+	set("on_start", "standby_leader", naming.RolePatroniLeader)
+	writable, known = instance.IsWritable()
+	assert.Assert(t, known)
+	assert.Assert(t, !writable)
+
+	// Stopped leader; the callback does not change the label.
+	set("on_stop", "master", naming.RolePatroniLeader)
+	writable, known = instance.IsWritable()
+	assert.Assert(t, known)
+	assert.Assert(t, !writable)
+
+	// Leader whose callback ran while the container was running.
+	set("on_role_change", "master", naming.RolePatroniLeader)
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name: naming.ContainerDatabase,
+		State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{
+			StartedAt: metav1.NewTime(time.Date(2021, 10, 31, 12, 0, 0, 0, time.UTC)),
+		}},
+	}}
+	writable, known = instance.IsWritable()
+	assert.Assert(t, known)
+	assert.Assert(t, writable)
+
+	// Leader that crashed; the label and callback are from before it restarted.
+	pod.Status.ContainerStatuses[0].State.Running.StartedAt =
+		metav1.NewTime(time.Date(2021, 10, 31, 13, 0, 0, 0, time.UTC))
+	writable, known = instance.IsWritable()
+	assert.Assert(t, !known)
+	assert.Assert(t, !writable)
+
+	// Leader that is not running.
+	pod.Status.ContainerStatuses[0].State = corev1.ContainerState{
+		Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+	}
+	writable, known = instance.IsWritable()
+	assert.Assert(t, known)
+	assert.Assert(t, !writable)
+}
+
 func TestNewObservedInstances(t *testing.T) {
 	t.Run("Empty", func(t *testing.T) {
 		cluster := new(v1beta1.PostgresCluster)
@@ -501,7 +580,7 @@ func TestAddPGBackRestToInstancePodSpec(t *testing.T) {
 				}
 			}
 
-			err := addPGBackRestToInstancePodSpec(postgresCluster, template)
+			err := addPGBackRestToInstancePodSpec(postgresCluster, template, nil)
 			assert.NilError(t, err)
 
 			// if a repo host is configured, then verify SSH is enabled
@@ -1676,6 +1755,19 @@ func TestReconcileUpgrade(t *testing.T) {
 		},
 		expectReconcile:     true,
 		expectedReturnEarly: true,
+	}, {
+		testDesc:        "upgrade enabled, no upgrade job, patroni uses etcd",
+		createEndpoints: false,
+		upgrade: &v1beta1.PGMajorUpgrade{
+			Enabled:             initialize.Bool(true),
+			FromPostgresVersion: 12,
+			Image:               initialize.String("upgrade-image"),
+		},
+		status: &v1beta1.PostgresClusterStatus{
+			Patroni: v1beta1.PatroniStatus{DCS: "Etcd"},
+		},
+		expectReconcile:     false,
+		expectedReturnEarly: false,
 	}, {
 		testDesc:        "upgrade enabled, created",
 		createEndpoints: false,
@@ -2077,13 +2169,13 @@ func TestPrepareForUpgrade(t *testing.T) {
 
 	testCases := []struct {
 		desc            string
-		createResources func(t *testing.T, cluster *v1beta1.PostgresCluster) (*batchv1.Job, []corev1.Endpoints)
+		createResources func(t *testing.T, cluster *v1beta1.PostgresCluster) (*batchv1.Job, []client.Object)
 		upgradeEnabled  bool
 		result          testResult
 	}{{
 		desc: "remove upgrade jobs",
 		createResources: func(t *testing.T,
-			cluster *v1beta1.PostgresCluster) (*batchv1.Job, []corev1.Endpoints) {
+			cluster *v1beta1.PostgresCluster) (*batchv1.Job, []client.Object) {
 			job := generateJob(cluster.Name)
 			assert.NilError(t, r.Client.Create(ctx, job))
 			return job, nil
@@ -2101,8 +2193,8 @@ func TestPrepareForUpgrade(t *testing.T) {
 	}, {
 		desc: "cluster fully prepared, primary as startup instance",
 		createResources: func(t *testing.T,
-			cluster *v1beta1.PostgresCluster) (*batchv1.Job, []corev1.Endpoints) {
-			return nil, []corev1.Endpoints{}
+			cluster *v1beta1.PostgresCluster) (*batchv1.Job, []client.Object) {
+			return nil, []client.Object{}
 		},
 		result: testResult{
 			upgradeJobExists:  false,
@@ -2271,3 +2363,84 @@ func fakeUpgradeCluster(clusterName, namespace, clusterUID string) *v1beta1.Post
 
 	return cluster
 }
+
+func TestReconcileInstanceSetPodDisruptionBudget(t *testing.T) {
+	ctx := context.Background()
+	env, cc, _ := setupTestEnv(t, ControllerName)
+	t.Cleanup(func() { teardownTestEnv(t, env) })
+
+	ns := &corev1.Namespace{}
+	ns.GenerateName = "postgres-operator-test-"
+	assert.NilError(t, cc.Create(ctx, ns))
+	t.Cleanup(func() { assert.Check(t, cc.Delete(ctx, ns)) })
+
+	r := &Reconciler{Client: cc, Owner: client.FieldOwner(t.Name())}
+
+	cluster := fakePostgresCluster("hippo", ns.Name, "", false)
+	cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{
+		{Name: "one", Replicas: initialize.Int32(1)},
+		{Name: "two", Replicas: initialize.Int32(2)},
+	}
+	assert.NilError(t, cc.Create(ctx, cluster))
+	t.Cleanup(func() { assert.Check(t, client.IgnoreNotFound(cc.Delete(ctx, cluster))) })
+
+	exists := func(t *testing.T, set *v1beta1.PostgresInstanceSetSpec) (
+		*policyv1beta1.PodDisruptionBudget, bool,
+	) {
+		pdb := &policyv1beta1.PodDisruptionBudget{ObjectMeta: naming.InstanceSet(cluster, set)}
+		err := cc.Get(ctx, client.ObjectKeyFromObject(pdb), pdb)
+		if apierrors.IsNotFound(err) {
+			return nil, false
+		}
+		assert.NilError(t, err)
+		return pdb, true
+	}
+
+	one, two := &cluster.Spec.InstanceSets[0], &cluster.Spec.InstanceSets[1]
+
+	t.Run("OneReplica", func(t *testing.T) {
+		assert.NilError(t, r.reconcileInstanceSetPodDisruptionBudget(ctx, cluster, one))
+
+		_, found := exists(t, one)
+		assert.Assert(t, !found, "expected no PodDisruptionBudget")
+	})
+
+	t.Run("Replicas", func(t *testing.T) {
+		assert.NilError(t, r.reconcileInstanceSetPodDisruptionBudget(ctx, cluster, two))
+
+		pdb, found := exists(t, two)
+		assert.Assert(t, found, "expected a PodDisruptionBudget")
+		assert.DeepEqual(t, pdb.Spec.MinAvailable, initialize.IntOrStringInt32(1))
+		assert.DeepEqual(t, pdb.Spec.Selector.MatchLabels, map[string]string{
+			naming.LabelCluster:     "hippo",
+			naming.LabelInstanceSet: "two",
+		})
+		assert.Assert(t, metav1.IsControlledBy(pdb, cluster))
+	})
+
+	t.Run("Specified", func(t *testing.T) {
+		one.MinAvailable = initialize.IntOrStringInt32(1)
+		assert.NilError(t, r.reconcileInstanceSetPodDisruptionBudget(ctx, cluster, one))
+
+		_, found := exists(t, one)
+		assert.Assert(t, found, "expected a PodDisruptionBudget")
+	})
+
+	t.Run("Cleanup", func(t *testing.T) {
+		// Sets in the spec keep their PodDisruptionBudgets.
+		assert.NilError(t, r.cleanupPodDisruptionBudgets(ctx, cluster))
+		_, found := exists(t, one)
+		assert.Assert(t, found, "expected a PodDisruptionBudget")
+		_, found = exists(t, two)
+		assert.Assert(t, found, "expected a PodDisruptionBudget")
+
+		removed := *two
+		cluster.Spec.InstanceSets = cluster.Spec.InstanceSets[:1]
+		assert.NilError(t, r.cleanupPodDisruptionBudgets(ctx, cluster))
+
+		_, found = exists(t, one)
+		assert.Assert(t, found, "expected a PodDisruptionBudget")
+		_, found = exists(t, &removed)
+		assert.Assert(t, !found, "expected no PodDisruptionBudget")
+	})
+}
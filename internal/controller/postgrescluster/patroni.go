@@ -17,18 +17,29 @@ package postgrescluster
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/yaml"
 
 	"github.com/crunchydata/postgres-operator/internal/initialize"
+	"github.com/crunchydata/postgres-operator/internal/kubeapi"
 	"github.com/crunchydata/postgres-operator/internal/logging"
 	"github.com/crunchydata/postgres-operator/internal/naming"
 	"github.com/crunchydata/postgres-operator/internal/patroni"
@@ -37,6 +48,117 @@ import (
 	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
 
+// +kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get
+
+// patroniAPI returns a patroni.API that calls the Patroni of pod. By default,
+// it runs "patronictl" in the database container of pod. When the cluster
+// spec asks for REST, it calls the Patroni REST API of pod over mutual TLS
+// using a certificate signed by the cluster certificate authority.
+func (r *Reconciler) patroniAPI(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, pod *corev1.Pod,
+) (patroni.API, error) {
+	if cluster.Spec.Patroni == nil || cluster.Spec.Patroni.APIClient != "REST" {
+		return patroni.Executor(func(
+			_ context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			return r.PodExec(pod.Namespace, pod.Name, naming.ContainerDatabase,
+				stdin, stdout, stderr, command...)
+		}), nil
+	}
+
+	// StatefulSet Pods have a stable DNS name based on their hostname and
+	// subdomain. That name is in the certificate of the instance. Patroni
+	// members are named after their Pods.
+	// - https://docs.k8s.io/concepts/services-networking/dns-pod-service/#pods
+	memberURL := func(member string) *url.URL {
+		return &url.URL{Scheme: "https", Host: fmt.Sprintf("%s.%s.%s.svc:%d",
+			member, naming.ClusterPodService(cluster).Name, cluster.Namespace,
+			*cluster.Spec.Patroni.Port)}
+	}
+
+	httpClient, err := r.patroniHTTPClient(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	return &patroni.Client{
+		BaseURL:   memberURL(pod.Spec.Hostname).String(),
+		MemberURL: memberURL,
+		HTTP:      httpClient,
+	}, nil
+}
+
+// patroniHTTPClients holds one HTTP client per cluster for calling the
+// Patroni REST API so that connections are reused between reconciles.
+type patroniHTTPClients struct {
+	sync.Mutex
+	clients map[client.ObjectKey]patroniHTTPClient
+}
+
+// patroniHTTPClient is an HTTP client that presents a certificate signed by
+// root.
+type patroniHTTPClient struct {
+	root *pki.Certificate
+	http *http.Client
+}
+
+// patroniHTTPClient returns the HTTP client that calls the Patroni REST API of
+// cluster. Patroni trusts any client certificate signed by the root
+// certificate, so one is generated rather than stored anywhere. That happens
+// again only when the root certificate changes.
+func (r *Reconciler) patroniHTTPClient(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+) (*http.Client, error) {
+	root, err := r.getRootCertificate(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	key := client.ObjectKeyFromObject(cluster)
+	r.patroniClients.Lock()
+	defer r.patroniClients.Unlock()
+
+	cached, ok := r.patroniClients.clients[key]
+	if ok && cached.root.Equal(*root.Certificate) {
+		return cached.http, nil
+	}
+
+	leaf := pki.NewLeafCertificate("postgres-operator", nil, nil)
+	err = errors.WithStack(leaf.Generate(root))
+
+	var created *http.Client
+	if err == nil {
+		created, err = patroni.NewHTTPClient(root.Certificate, leaf.Certificate, leaf.PrivateKey)
+		err = errors.WithStack(err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		cached.http.CloseIdleConnections()
+	}
+	if r.patroniClients.clients == nil {
+		r.patroniClients.clients = make(map[client.ObjectKey]patroniHTTPClient)
+	}
+	r.patroniClients.clients[key] = patroniHTTPClient{root: root.Certificate, http: created}
+
+	return created, nil
+}
+
+// forgetPatroniHTTPClient closes and discards the HTTP client of the cluster
+// identified by key, if any.
+func (r *Reconciler) forgetPatroniHTTPClient(key client.ObjectKey) {
+	r.patroniClients.Lock()
+	defer r.patroniClients.Unlock()
+
+	if cached, ok := r.patroniClients.clients[key]; ok {
+		cached.http.CloseIdleConnections()
+		delete(r.patroniClients.clients, key)
+	}
+}
+
 // +kubebuilder:rbac:groups="",resources=endpoints,verbs=deletecollection
 
 func (r *Reconciler) deletePatroniArtifacts(
@@ -64,6 +186,11 @@ func (r *Reconciler) handlePatroniRestarts(
 	const container = naming.ContainerDatabase
 	var primaryNeedsRestart, replicaNeedsRestart *Instance
 
+	// Patroni refuses to restart members while paused. Wait until it resumes.
+	if patroni.ClusterPaused(cluster) {
+		return nil
+	}
+
 	// Look for one primary and one replica that need to restart. Ignore
 	// containers that are terminating or not running; Kubernetes will start
 	// them again, and calls to their Patroni API will likely be interrupted anyway.
@@ -99,14 +226,12 @@ func (r *Reconciler) handlePatroniRestarts(
 	// replicas here, replicas will typically restart first because we see them
 	// first.
 	if primaryNeedsRestart != nil {
-		exec := patroni.Executor(func(
-			ctx context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
-		) error {
-			pod := primaryNeedsRestart.Pods[0]
-			return r.PodExec(pod.Namespace, pod.Name, container, stdin, stdout, stderr, command...)
-		})
-
-		return errors.WithStack(exec.RestartPendingMembers(ctx, "master", naming.PatroniScope(cluster)))
+		api, err := r.patroniAPI(ctx, cluster, primaryNeedsRestart.Pods[0])
+		if err == nil {
+			err = errors.WithStack(
+				api.RestartPendingMembers(ctx, "master", naming.PatroniScope(cluster)))
+		}
+		return err
 	}
 
 	// When the primary does not need to restart but a replica does, restart all
@@ -124,14 +249,12 @@ func (r *Reconciler) handlePatroniRestarts(
 	// how we decide when to restart.
 	// - https://www.postgresql.org/docs/current/runtime-config-replication.html
 	if replicaNeedsRestart != nil {
-		exec := patroni.Executor(func(
-			ctx context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
-		) error {
-			pod := replicaNeedsRestart.Pods[0]
-			return r.PodExec(pod.Namespace, pod.Name, container, stdin, stdout, stderr, command...)
-		})
-
-		return errors.WithStack(exec.RestartPendingMembers(ctx, "replica", naming.PatroniScope(cluster)))
+		api, err := r.patroniAPI(ctx, cluster, replicaNeedsRestart.Pods[0])
+		if err == nil {
+			err = errors.WithStack(
+				api.RestartPendingMembers(ctx, "replica", naming.PatroniScope(cluster)))
+		}
+		return err
 	}
 
 	// Nothing needs to restart.
@@ -187,6 +310,33 @@ func (r *Reconciler) reconcilePatroniDynamicConfiguration(
 	ctx context.Context, cluster *v1beta1.PostgresCluster, instances *observedInstances,
 	pgHBAs postgres.HBAs, pgParameters postgres.Parameters,
 ) error {
+	// Report whether or not the typed synchronous settings can be applied.
+	// The CRD cannot compare the standby count to the number of replicas, so
+	// the settings are left out of the dynamic configuration when there are not
+	// enough instances for the requested number of synchronous standbys.
+	if count := patroni.SynchronousStandbyCount(cluster); count > 0 {
+		condition := metav1.Condition{
+			ObservedGeneration: cluster.GetGeneration(),
+			Type:               ConditionSynchronousReplication,
+			Status:             metav1.ConditionTrue,
+			Reason:             "Applied",
+			Message:            "Synchronous replication settings applied",
+		}
+		if candidates := patroni.SynchronousStandbyCandidates(cluster); candidates < count {
+			path := field.NewPath("spec", "patroni", "synchronous", "standbyCount")
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = "NotEnoughInstances"
+			condition.Message = field.Invalid(path, count, fmt.Sprintf(
+				"exceeds the %d instances that can be synchronous standbys", candidates)).Error()
+		}
+		meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+	} else {
+		// TODO: remove guard with move to controller-runtime 0.9.0 https://issue.k8s.io/99714
+		if len(cluster.Status.Conditions) > 0 {
+			meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionSynchronousReplication)
+		}
+	}
+
 	if !patroni.ClusterBootstrapped(cluster) {
 		// Patroni has not yet bootstrapped. Dynamic configuration happens through
 		// configuration files during bootstrap, so there's nothing to do here.
@@ -212,8 +362,9 @@ func (r *Reconciler) reconcilePatroniDynamicConfiguration(
 	// NOTE(cbandy): Despite the guards above, calling PodExec may still fail
 	// due to a missing or stopped container.
 
-	exec := func(_ context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string) error {
-		return r.PodExec(pod.Namespace, pod.Name, naming.ContainerDatabase, stdin, stdout, stderr, command...)
+	api, err := r.patroniAPI(ctx, cluster, pod)
+	if err != nil {
+		return err
 	}
 
 	// Deserialize the schemaless field. There will be no error because the
@@ -225,8 +376,27 @@ func (r *Reconciler) reconcilePatroniDynamicConfiguration(
 
 	configuration = patroni.DynamicConfiguration(cluster, configuration, pgHBAs, pgParameters)
 
-	return errors.WithStack(
-		patroni.Executor(exec).ReplaceConfiguration(ctx, configuration))
+	err = errors.WithStack(api.ReplaceConfiguration(ctx, configuration))
+
+	// The configuration is stored in DCS; Patroni is now in or out of
+	// maintenance mode.
+	if err == nil {
+		condition := metav1.Condition{
+			ObservedGeneration: cluster.GetGeneration(),
+			Type:               v1beta1.PatroniPaused,
+			Status:             metav1.ConditionFalse,
+			Reason:             "Resumed",
+			Message:            "Patroni is managing PostgreSQL",
+		}
+		if patroni.ClusterPaused(cluster) {
+			condition.Status = metav1.ConditionTrue
+			condition.Reason = "Paused"
+			condition.Message = "Patroni is in maintenance mode"
+		}
+		meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+	}
+
+	return err
 }
 
 // generatePatroniLeaderLeaseService returns a v1.Service that exposes the
@@ -250,6 +420,16 @@ func (r *Reconciler) generatePatroniLeaderLeaseService(
 	// Patroni will ensure that they always route to the elected leader.
 	// - https://docs.k8s.io/concepts/services-networking/service/#services-without-selectors
 	service.Spec.Selector = nil
+
+	// Patroni manages these Endpoints only when it uses Endpoints for DCS.
+	// Otherwise, select the Pod labeled as the leader.
+	if patroni.DCSType(cluster) != "Endpoints" {
+		service.Spec.Selector = map[string]string{
+			naming.LabelCluster: cluster.Name,
+			naming.LabelPatroni: naming.PatroniScope(cluster),
+			naming.LabelRole:    naming.RolePatroniLeader,
+		}
+	}
 	if cluster.Spec.Service != nil {
 		service.Spec.Type = corev1.ServiceType(cluster.Spec.Service.Type)
 	} else {
@@ -289,7 +469,108 @@ func (r *Reconciler) reconcilePatroniLeaderLease(
 	return service, err
 }
 
-// +kubebuilder:rbac:groups="",resources=endpoints,verbs=get
+// +kubebuilder:rbac:groups="",resources=configmaps;endpoints,verbs=delete
+
+// reconcilePatroniDCS records in cluster.Status.Patroni the distributed
+// configuration store that Patroni uses. Patroni cannot change stores while any
+// member is running, so a different store in the spec takes effect only while
+// the cluster is shutdown. Objects Patroni kept in Kubernetes for the previous
+// store are deleted then.
+func (r *Reconciler) reconcilePatroniDCS(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, instances *observedInstances,
+) error {
+	requested := "Endpoints"
+	if spec := cluster.Spec.Patroni; spec != nil && spec.DCS != nil && spec.DCS.Type != "" {
+		requested = spec.DCS.Type
+	}
+
+	current := cluster.Status.Patroni.DCS
+	if current == "" {
+		// Clusters bootstrapped before the store was recorded use Endpoints.
+		current = requested
+		if patroni.ClusterBootstrapped(cluster) {
+			current = "Endpoints"
+		}
+		cluster.Status.Patroni.DCS = current
+	}
+	if current == requested {
+		return nil
+	}
+
+	var running bool
+	for _, instance := range instances.forCluster {
+		running = running || len(instance.Pods) > 0
+	}
+	if cluster.Spec.Shutdown == nil || !*cluster.Spec.Shutdown || running {
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "DCSChangePending",
+			"Patroni is using %s; shutdown the cluster to change to %s", current, requested)
+		return nil
+	}
+
+	// Every member is stopped. Remove what Patroni stored so that it cannot
+	// be mistaken for the current state should the cluster change back later.
+	// Patroni writes the system identifier of the existing data directory to
+	// the new store when it starts.
+	stale := patroniDCSObjects(cluster, current)
+
+	var err error
+	for i := 0; err == nil && i < len(stale); i++ {
+		err = errors.WithStack(client.IgnoreNotFound(r.Client.Delete(ctx, stale[i])))
+	}
+
+	if err == nil {
+		cluster.Status.Patroni.DCS = requested
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "DCSChanged",
+			"Patroni changed from %s to %s", current, requested)
+	}
+
+	return err
+}
+
+// patroniDCSObjects returns the objects in which Patroni stores the state of
+// cluster when it uses dcs. It returns nothing when that state is outside of
+// Kubernetes.
+func patroniDCSObjects(cluster *v1beta1.PostgresCluster, dcs string) []client.Object {
+	switch dcs {
+	case "ConfigMaps":
+		return []client.Object{
+			&corev1.ConfigMap{ObjectMeta: naming.PatroniLeaderConfigMap(cluster)},
+			&corev1.ConfigMap{ObjectMeta: naming.PatroniDistributedConfiguration(cluster)},
+			&corev1.ConfigMap{ObjectMeta: naming.PatroniSync(cluster)},
+			&corev1.ConfigMap{ObjectMeta: naming.PatroniTrigger(cluster)},
+		}
+	case "Endpoints":
+		return []client.Object{
+			&corev1.Endpoints{ObjectMeta: naming.PatroniLeaderEndpoints(cluster)},
+			&corev1.Endpoints{ObjectMeta: naming.PatroniDistributedConfiguration(cluster)},
+			&corev1.Endpoints{ObjectMeta: naming.PatroniSync(cluster)},
+			&corev1.Endpoints{ObjectMeta: naming.PatroniTrigger(cluster)},
+		}
+	}
+	return nil
+}
+
+// +kubebuilder:rbac:groups="",resources=configmaps;endpoints,verbs=get
+
+// observePatroniDCS returns the existing objects in which Patroni stores the
+// state of cluster.
+func (r *Reconciler) observePatroniDCS(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+) ([]client.Object, error) {
+	var existing []client.Object
+	for _, object := range patroniDCSObjects(cluster, patroni.DCSType(cluster)) {
+		err := r.Client.Get(ctx, client.ObjectKeyFromObject(object), object)
+		if err == nil {
+			existing = append(existing, object)
+		} else if !apierrors.IsNotFound(err) {
+			return nil, errors.WithStack(err)
+		}
+	}
+	return existing, nil
+}
+
+// +kubebuilder:rbac:groups="",resources=configmaps;endpoints,verbs=get
+// +kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
 
 // reconcilePatroniStatus populates cluster.Status.Patroni with observations.
 func (r *Reconciler) reconcilePatroniStatus(
@@ -299,21 +580,53 @@ func (r *Reconciler) reconcilePatroniStatus(
 	result := reconcile.Result{}
 	log := logging.FromContext(ctx)
 
+	var readyPod *corev1.Pod
 	var readyInstance bool
 	for _, instance := range observedInstances.forCluster {
 		if r, _ := instance.IsReady(); r {
 			readyInstance = true
+			if len(instance.Pods) > 0 {
+				readyPod = instance.Pods[0]
+			}
 		}
 	}
 
-	dcs := &corev1.Endpoints{ObjectMeta: naming.PatroniDistributedConfiguration(cluster)}
-	err := errors.WithStack(client.IgnoreNotFound(
-		r.Client.Get(ctx, client.ObjectKeyFromObject(dcs), dcs)))
+	// After bootstrap, Patroni writes the cluster system identifier to DCS.
+	var err error
+	var initialize string
+	switch patroni.DCSType(cluster) {
+	case "ConfigMaps":
+		dcs := &corev1.ConfigMap{ObjectMeta: naming.PatroniDistributedConfiguration(cluster)}
+		err = errors.WithStack(client.IgnoreNotFound(
+			r.Client.Get(ctx, client.ObjectKeyFromObject(dcs), dcs)))
+		initialize = dcs.Annotations["initialize"]
+
+	case "Etcd":
+		// The DCS is outside of Kubernetes. Every member of the cluster has the
+		// same identifier, so ask PostgreSQL once any instance is ready.
+		if readyPod != nil {
+			initialize, err = postgres.SystemIdentifier(ctx, func(
+				_ context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+			) error {
+				return r.PodExec(readyPod.Namespace, readyPod.Name,
+					naming.ContainerDatabase, stdin, stdout, stderr, command...)
+			})
+			if err != nil {
+				log.V(1).Info("unable to read system identifier", "pod", readyPod.Name, "error", err.Error())
+				initialize, err = "", nil
+			}
+		}
+
+	default:
+		dcs := &corev1.Endpoints{ObjectMeta: naming.PatroniDistributedConfiguration(cluster)}
+		err = errors.WithStack(client.IgnoreNotFound(
+			r.Client.Get(ctx, client.ObjectKeyFromObject(dcs), dcs)))
+		initialize = dcs.Annotations["initialize"]
+	}
 
 	if err == nil {
-		if dcs.Annotations["initialize"] != "" {
-			// After bootstrap, Patroni writes the cluster system identifier to DCS.
-			cluster.Status.Patroni.SystemIdentifier = dcs.Annotations["initialize"]
+		if initialize != "" {
+			cluster.Status.Patroni.SystemIdentifier = initialize
 
 			// Once the cluster is bootstrapped, note the current postgres version
 			cluster.Status.PostgresVersion = cluster.Spec.PostgresVersion
@@ -334,6 +647,469 @@ func (r *Reconciler) reconcilePatroniStatus(
 	return result, err
 }
 
+// ConditionSynchronousReplication is the type used in a condition to indicate
+// whether or not the synchronous replication settings of the spec are applied
+// to Patroni.
+const ConditionSynchronousReplication = "PatroniSynchronousReplication"
+
+// patroniMembersInterval is how often the members of a Patroni cluster are
+// observed while the cluster is otherwise unchanged.
+const patroniMembersInterval = 30 * time.Second
+
+// defaultReplicationLagThreshold is the size of one WAL segment.
+var defaultReplicationLagThreshold = resource.MustParse("16Mi")
+
+// replicationLagThreshold returns the number of bytes a replica of cluster can
+// be behind its primary and still be considered caught up.
+func replicationLagThreshold(cluster *v1beta1.PostgresCluster) int64 {
+	if cluster.Spec.Patroni != nil && cluster.Spec.Patroni.ReplicationLagThreshold != nil {
+		return cluster.Spec.Patroni.ReplicationLagThreshold.Value()
+	}
+	return defaultReplicationLagThreshold.Value()
+}
+
+// databaseCrashLooping returns whether or not the database container of pod is
+// waiting to restart after crashing repeatedly. Patroni stops reporting such
+// a member once its lease expires.
+func databaseCrashLooping(pod *corev1.Pod) bool {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == naming.ContainerDatabase && status.State.Waiting != nil {
+			return status.State.Waiting.Reason == "CrashLoopBackOff"
+		}
+	}
+	return false
+}
+
+// databaseRestartedSince returns whether or not the database container of pod
+// has restarted after since.
+func databaseRestartedSince(pod *corev1.Pod, since *metav1.Time) bool {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == naming.ContainerDatabase && status.State.Running != nil {
+			return since != nil && status.RestartCount > 0 &&
+				since.Before(&status.State.Running.StartedAt)
+		}
+	}
+	return false
+}
+
+// reconcilePatroniMembers populates cluster.Status.Patroni.Members with the
+// members Patroni reports and sets the ReplicationLagHigh condition. The exact
+// location of each replica is read from the primary when it is running.
+func (r *Reconciler) reconcilePatroniMembers(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, instances *observedInstances,
+) reconcile.Result {
+	const container = naming.ContainerDatabase
+	log := logging.FromContext(ctx)
+
+	// Every change to the cluster or its status triggers a reconcile. Observe
+	// the members only when the interval has passed since the last time.
+	if observed := cluster.Status.Patroni.MembersObservedTime; observed != nil {
+		if elapsed := time.Since(observed.Time); elapsed < patroniMembersInterval {
+			return reconcile.Result{RequeueAfter: patroniMembersInterval - elapsed}
+		}
+	}
+
+	// Prefer the primary; any running Pod can report the members.
+	pod, _ := instances.writablePod(container)
+	primary := pod != nil
+	for _, instance := range instances.forCluster {
+		if pod != nil {
+			break
+		}
+		if running, known := instance.IsRunning(container); running &&
+			known && len(instance.Pods) == 1 {
+			pod = instance.Pods[0]
+		}
+	}
+	if pod == nil {
+		return reconcile.Result{}
+	}
+
+	// NOTE: Calling Patroni or PostgreSQL may fail while they are starting or
+	// stopping. That is not an error for the cluster; try again later.
+	result := reconcile.Result{RequeueAfter: patroniMembersInterval}
+
+	api, err := r.patroniAPI(ctx, cluster, pod)
+	var members patroni.ClusterStatus
+	if err == nil {
+		members, err = api.GetCluster(ctx, naming.PatroniScope(cluster))
+	}
+	if err != nil {
+		log.V(1).Info("unable to observe Patroni members", "pod", pod.Name, "error", err.Error())
+		return result
+	}
+
+	var replication postgres.ReplicationStatus
+	if primary {
+		replication, err = postgres.GetReplicationStatus(ctx, func(
+			_ context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			return r.PodExec(pod.Namespace, pod.Name, container, stdin, stdout, stderr, command...)
+		})
+		if err != nil {
+			log.V(1).Info("unable to observe replication", "pod", pod.Name, "error", err.Error())
+		}
+	}
+
+	// Only the primary reports its replication slots.
+	if primary && err == nil {
+		slots := make([]v1beta1.PatroniSlotStatus, 0, len(replication.Slots))
+		for name, slot := range replication.Slots {
+			slots = append(slots, v1beta1.PatroniSlotStatus{
+				Name:          name,
+				Type:          slot.Type,
+				Active:        slot.Active,
+				Database:      slot.Database,
+				Plugin:        slot.Plugin,
+				RetainedBytes: slot.RetainedBytes,
+				WALStatus:     slot.WALStatus,
+			})
+		}
+		sort.Slice(slots, func(i, j int) bool { return slots[i].Name < slots[j].Name })
+		cluster.Status.Patroni.ReplicationSlots = slots
+	}
+
+	// Replicas that are delayed on purpose are expected to lag.
+	delayed := make(map[string]bool)
+	for _, instance := range instances.forCluster {
+		if instance.Spec != nil && instance.Spec.RecoveryMinApplyDelay != nil {
+			for _, p := range instance.Pods {
+				delayed[p.Name] = true
+			}
+		}
+	}
+
+	// Remember when each member started failing.
+	failing := make(map[string]*metav1.Time)
+	for _, member := range cluster.Status.Patroni.Members {
+		failing[member.Name] = member.FailedTime
+	}
+
+	pods := make(map[string]*corev1.Pod)
+	for _, instance := range instances.forCluster {
+		for _, p := range instance.Pods {
+			pods[p.Name] = p
+		}
+	}
+
+	now := metav1.Now()
+	threshold := replicationLagThreshold(cluster)
+
+	var lagging, synchronous []string
+	status := make([]v1beta1.PatroniMemberStatus, 0, len(members.Members))
+	for _, member := range members.Members {
+		observed := v1beta1.PatroniMemberStatus{
+			Name:           member.Name,
+			Role:           member.Role,
+			State:          member.State,
+			Timeline:       member.Timeline,
+			PendingRestart: member.PendingRestart,
+		}
+		if len(member.Tags) > 0 {
+			observed.Tags = make(map[string]string, len(member.Tags))
+			for k, v := range member.Tags {
+				observed.Tags[k] = fmt.Sprintf("%v", v)
+			}
+		}
+
+		if standby, ok := replication.Standbys[member.Name]; ok {
+			observed.LSN = standby.LSN
+			observed.ReplicationLagBytes = initialize.Int64(standby.LagBytes)
+		} else if member.IsLeader() && primary && member.Name == pod.Name {
+			observed.LSN = replication.LSN
+		} else if lag, ok := member.LagBytes(); ok && !member.IsLeader() {
+			observed.ReplicationLagBytes = initialize.Int64(lag)
+		}
+
+		if member.Role == "sync_standby" {
+			synchronous = append(synchronous, member.Name)
+		}
+
+		// A member whose container keeps restarting is failing even when Patroni
+		// reports it between crashes.
+		failed := member.IsFailed()
+		if p := pods[member.Name]; p != nil && !failed {
+			failed = databaseCrashLooping(p) ||
+				(member.State != "running" && databaseRestartedSince(p, failing[member.Name]))
+		}
+		if failed {
+			observed.FailedTime = failing[member.Name]
+			if observed.FailedTime == nil {
+				observed.FailedTime = now.DeepCopy()
+			}
+		}
+
+		if observed.ReplicationLagBytes != nil &&
+			*observed.ReplicationLagBytes > threshold && !delayed[member.Name] {
+			lagging = append(lagging, member.Name)
+		}
+
+		status = append(status, observed)
+		delete(pods, member.Name)
+	}
+
+	// Patroni does not report members that are not running. Include those
+	// whose container is crash looping so their failure is remembered.
+	for name, p := range pods {
+		if databaseCrashLooping(p) {
+			observed := v1beta1.PatroniMemberStatus{Name: name, FailedTime: failing[name]}
+			if observed.FailedTime == nil {
+				observed.FailedTime = now.DeepCopy()
+			}
+			status = append(status, observed)
+		}
+	}
+
+	sort.Slice(status, func(i, j int) bool { return status[i].Name < status[j].Name })
+	sort.Strings(lagging)
+	sort.Strings(synchronous)
+
+	cluster.Status.Patroni.Members = status
+	cluster.Status.Patroni.MembersObservedTime = &now
+	cluster.Status.Patroni.SynchronousStandbys = synchronous
+
+	condition := metav1.Condition{
+		ObservedGeneration: cluster.GetGeneration(),
+		Type:               v1beta1.ReplicationLagHigh,
+		Status:             metav1.ConditionFalse,
+		Reason:             "ReplicationHealthy",
+		Message:            "Replicas are within the replication lag threshold",
+	}
+	if len(lagging) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "ReplicationLagging"
+		condition.Message = fmt.Sprintf(
+			"Replicas are more than %d bytes behind the primary: %s",
+			threshold, strings.Join(lagging, ", "))
+	}
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+
+	return result
+}
+
+// +kubebuilder:rbac:groups="",resources=pods,verbs=patch
+// +kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
+
+// reconcilePatroniReinitialize reinitializes replicas whose Pods are annotated
+// with naming.PatroniReinitialize. When the policy is Automatic, it also
+// reinitializes replicas that failed to start or crashed for longer than the
+// threshold. No more than the configured number of replicas are reinitializing
+// at one time.
+func (r *Reconciler) reconcilePatroniReinitialize(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, instances *observedInstances,
+) (reconcile.Result, error) {
+	log := logging.FromContext(ctx)
+
+	policy, failedFor, limit := "Manual", 300*time.Second, int32(1)
+	if spec := cluster.Spec.Patroni; spec != nil && spec.Reinitialization != nil {
+		if spec.Reinitialization.Policy != "" {
+			policy = spec.Reinitialization.Policy
+		}
+		if spec.Reinitialization.FailedSeconds != nil {
+			failedFor = time.Duration(*spec.Reinitialization.FailedSeconds) * time.Second
+		}
+		if spec.Reinitialization.MaxConcurrent != nil {
+			limit = *spec.Reinitialization.MaxConcurrent
+		}
+	}
+
+	members := make(map[string]*v1beta1.PatroniMemberStatus)
+	for i := range cluster.Status.Patroni.Members {
+		member := &cluster.Status.Patroni.Members[i]
+		members[member.Name] = member
+	}
+	isLeader := func(name string) bool {
+		member, ok := members[name]
+		return ok && patroni.ClusterMember{Role: member.Role}.IsLeader()
+	}
+
+	// Replicas requested by a person come first, then those that have been
+	// failing the longest. Nothing happens automatically while Patroni is
+	// paused.
+	var result reconcile.Result
+	var requested, failed []*corev1.Pod
+	for _, instance := range instances.forCluster {
+		for _, pod := range instance.Pods {
+			member := members[pod.Name]
+
+			if _, ok := pod.Annotations[naming.PatroniReinitialize]; ok {
+				requested = append(requested, pod)
+			} else if policy == "Automatic" && !patroni.ClusterPaused(cluster) &&
+				member != nil && member.FailedTime != nil && !isLeader(pod.Name) {
+
+				// Patroni can reinitialize a member only while it reports
+				// one, so wait for a crash looping member to start again.
+				if wait := time.Until(member.FailedTime.Add(failedFor)); wait > 0 {
+					result = updateReconcileResult(result, reconcile.Result{RequeueAfter: wait})
+				} else if member.State == "" {
+					result = updateReconcileResult(result,
+						reconcile.Result{RequeueAfter: patroniMembersInterval})
+				} else {
+					failed = append(failed, pod)
+				}
+			}
+		}
+	}
+	sort.Slice(failed, func(i, j int) bool {
+		return members[failed[i].Name].FailedTime.Before(members[failed[j].Name].FailedTime)
+	})
+
+	if len(requested)+len(failed) == 0 {
+		return result, nil
+	}
+
+	// Replicas copy their data from the primary or from backups taken of it,
+	// so wait for there to be one.
+	pod, _ := instances.writablePod(naming.ContainerDatabase)
+	if pod == nil {
+		log.Info("waiting for a primary before reinitializing replicas")
+		return updateReconcileResult(result,
+			reconcile.Result{RequeueAfter: patroniMembersInterval}), nil
+	}
+
+	api, err := r.patroniAPI(ctx, cluster, pod)
+	if err != nil {
+		return result, err
+	}
+
+	// Patroni reports "creating replica" while a member is reinitializing.
+	// Members in status can be from a while ago, so ask Patroni now.
+	var reinitializing int32
+	current, err := api.GetCluster(ctx, naming.PatroniScope(cluster))
+	if err != nil {
+		return result, errors.WithStack(err)
+	}
+	for _, member := range current.Members {
+		if member.State == "creating replica" {
+			reinitializing++
+		}
+	}
+
+	for _, pod := range append(requested, failed...) {
+		_, manual := pod.Annotations[naming.PatroniReinitialize]
+
+		// Patroni refuses to reinitialize the leader. Remove the annotation so
+		// it does not happen unexpectedly after a switchover.
+		if manual && isLeader(pod.Name) {
+			r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "ReinitializeRefused",
+				"Pod %s is the primary and cannot be reinitialized", pod.Name)
+
+			err = errors.WithStack(r.patch(ctx, pod.DeepCopy(),
+				kubeapi.NewMergePatch().Remove("metadata", "annotations", naming.PatroniReinitialize)))
+			if err != nil {
+				break
+			}
+			continue
+		}
+
+		if reinitializing >= limit {
+			result = updateReconcileResult(result,
+				reconcile.Result{RequeueAfter: patroniMembersInterval})
+			break
+		}
+
+		err = errors.WithStack(api.ReinitializeMember(ctx, naming.PatroniScope(cluster), pod.Name))
+		if err != nil {
+			r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "ReinitializeFailed",
+				"Unable to reinitialize %s: %v", pod.Name, err)
+			break
+		}
+		reinitializing++
+
+		// Forget when the member started failing so it is not reinitialized
+		// again before Patroni reports its new state.
+		if member := members[pod.Name]; member != nil {
+			member.FailedTime = nil
+		}
+
+		if manual {
+			r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "Reinitializing",
+				"Reinitializing %s as requested", pod.Name)
+
+			err = errors.WithStack(r.patch(ctx, pod.DeepCopy(),
+				kubeapi.NewMergePatch().Remove("metadata", "annotations", naming.PatroniReinitialize)))
+			if err != nil {
+				break
+			}
+		} else {
+			r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "Reinitializing",
+				"Reinitializing %s because it has been %q for more than %v",
+				pod.Name, members[pod.Name].State, failedFor)
+		}
+	}
+
+	return result, err
+}
+
+// reconcilePatroniPrimary notices when the primary changes from one member to
+// another. It reports the change as an Event and in status, and it optionally
+// requests a new backup.
+func (r *Reconciler) reconcilePatroniPrimary(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, instances *observedInstances,
+) error {
+	var primary *corev1.Pod
+	pods := make(map[string]*corev1.Pod)
+	for _, instance := range instances.forCluster {
+		if len(instance.Pods) != 1 {
+			continue
+		}
+		pods[instance.Pods[0].Name] = instance.Pods[0]
+		if writable, known := instance.IsWritable(); writable && known {
+			primary = instance.Pods[0]
+		}
+	}
+	if primary == nil {
+		// There might be an election in progress; wait for its result.
+		return nil
+	}
+
+	previous := cluster.Status.Patroni.Primary
+	cluster.Status.Patroni.Primary = primary.Name
+	if previous == "" || previous == primary.Name {
+		return nil
+	}
+
+	// The Patroni callback of a primary that is demoted gracefully runs before
+	// a new primary is promoted. A primary that stops or disappears instead is
+	// a failover.
+	reason, eventType := "Failover", corev1.EventTypeWarning
+	if pod, exists := pods[previous]; exists {
+		if callback, ok := patroni.PodCallback(pod); ok &&
+			callback.Action == "on_role_change" && !callback.IsPrimary() {
+			reason, eventType = "Switchover", corev1.EventTypeNormal
+		}
+	}
+
+	// Use the time of the promotion when it is known.
+	changed := metav1.Now()
+	if callback, ok := patroni.PodCallback(primary); ok &&
+		callback.Action == "on_role_change" && callback.IsPrimary() {
+		changed = callback.Time
+	}
+	if reason == "Switchover" {
+		cluster.Status.Patroni.LastSwitchoverTime = &changed
+	} else {
+		cluster.Status.Patroni.LastFailoverTime = &changed
+	}
+
+	// The timeline is unknown when Patroni does not use Kubernetes for DCS.
+	if timeline := patroni.PodTimeline(primary); timeline > 0 {
+		r.Recorder.Eventf(cluster, eventType, reason,
+			"Primary changed from %s to %s on timeline %d",
+			previous, primary.Name, timeline)
+	} else {
+		r.Recorder.Eventf(cluster, eventType, reason,
+			"Primary changed from %s to %s", previous, primary.Name)
+	}
+
+	if reason == "Failover" && cluster.Spec.Patroni != nil &&
+		cluster.Spec.Patroni.BackupAfterFailover {
+		return r.requestReplicaCreateBackup(ctx, cluster)
+	}
+
+	return nil
+}
+
 // reconcileReplicationSecret creates a secret containing the TLS
 // certificate, key and CA certificate for use with the replication and
 // pg_rewind accounts in Postgres.
@@ -437,31 +1213,54 @@ func replicationCertSecretProjection(certificate *corev1.Secret) *corev1.SecretP
 }
 
 func (r *Reconciler) reconcilePatroniSwitchover(ctx context.Context,
-	cluster *v1beta1.PostgresCluster, instances *observedInstances) error {
+	cluster *v1beta1.PostgresCluster, instances *observedInstances,
+) (reconcile.Result, error) {
 	log := logging.FromContext(ctx)
 
 	if cluster.Spec.Patroni == nil || cluster.Spec.Patroni.Switchover == nil ||
 		!cluster.Spec.Patroni.Switchover.Enabled {
 		cluster.Status.Patroni.Switchover = nil
-		return nil
+		cluster.Status.Patroni.SwitchoverScheduledAt = nil
+		return reconcile.Result{}, nil
 	}
 
 	switchoverAnnotation := cluster.GetAnnotations()[naming.PatroniSwitchover]
 	if switchoverAnnotation == "" {
-		return nil
+		cluster.Status.Patroni.SwitchoverScheduledAt = nil
+		return reconcile.Result{}, nil
 	}
 	var switchoverStatus string
 	if cluster.Status.Patroni.Switchover != nil {
 		switchoverStatus = *cluster.Status.Patroni.Switchover
 	}
 	if switchoverStatus == switchoverAnnotation {
-		return nil
+		cluster.Status.Patroni.SwitchoverScheduledAt = nil
+		return reconcile.Result{}, nil
+	}
+
+	// A switchover that was waiting for its scheduled time is cancelled when
+	// that time is removed. Record the request as handled.
+	scheduledAt := cluster.Spec.Patroni.Switchover.ScheduledAt
+	if scheduledAt == nil && cluster.Status.Patroni.SwitchoverScheduledAt != nil {
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "SwitchoverCancelled",
+			"Cancelled the switchover scheduled for %s",
+			cluster.Status.Patroni.SwitchoverScheduledAt.UTC().Format(time.RFC3339))
+
+		cluster.Status.Patroni.Switchover = initialize.String(switchoverAnnotation)
+		cluster.Status.Patroni.SwitchoverScheduledAt = nil
+		return reconcile.Result{}, nil
+	}
+
+	// Patroni does not switchover while paused. Try again when it resumes.
+	if patroni.ClusterPaused(cluster) {
+		log.Info("waiting for Patroni to resume before switchover")
+		return reconcile.Result{}, nil
 	}
 
 	if len(instances.forCluster) <= 1 {
 		// TODO: event
 		// TODO: Possible webhook validation
-		return errors.New("Need more than one instance to switchover")
+		return reconcile.Result{}, errors.New("Need more than one instance to switchover")
 	}
 
 	// 	 TODO: Add webhook validation that requires a targetInstance when requesting failover
@@ -469,7 +1268,7 @@ func (r *Reconciler) reconcilePatroniSwitchover(ctx context.Context,
 		if cluster.Spec.Patroni.Switchover.TargetInstance == nil ||
 			*cluster.Spec.Patroni.Switchover.TargetInstance == "" {
 			// TODO: event
-			return errors.New("TargetInstance required when running failover")
+			return reconcile.Result{}, errors.New("TargetInstance required when running failover")
 		}
 	}
 
@@ -486,17 +1285,30 @@ func (r *Reconciler) reconcilePatroniSwitchover(ctx context.Context,
 		}
 		if targetInstance == nil {
 			// TODO: event
-			return errors.New("TargetInstance was specified but not found in the cluster")
+			return reconcile.Result{}, errors.New("TargetInstance was specified but not found in the cluster")
 		}
 		if len(targetInstance.Pods) != 1 {
 			// We expect that a target instance should have one associated pod.
-			return errors.Errorf(
+			return reconcile.Result{}, errors.Errorf(
 				"TargetInstance should have one pod. Pods (%d)", len(targetInstance.Pods))
 		}
+		if patroni.NoFailover(targetInstance.Spec) {
+			// Patroni refuses to promote members tagged "nofailover".
+			return reconcile.Result{}, errors.New("TargetInstance is in an instance set that does not allow failover")
+		}
 	} else {
 		log.V(1).Info("TargetInstance not provided")
 	}
 
+	// Wait until the scheduled time, if any. The pending time is reported in
+	// status so it can be seen and cancelled.
+	if scheduledAt != nil {
+		if wait := time.Until(scheduledAt.Time); wait > 0 {
+			cluster.Status.Patroni.SwitchoverScheduledAt = scheduledAt.DeepCopy()
+			return reconcile.Result{RequeueAfter: wait}, nil
+		}
+	}
+
 	// Find a running Pod that can be used to define a PodExec function.
 	var runningPod *corev1.Pod
 	for _, instance := range instances.forCluster {
@@ -508,28 +1320,37 @@ func (r *Reconciler) reconcilePatroniSwitchover(ctx context.Context,
 		}
 	}
 	if runningPod == nil {
-		return errors.New("Could not find a running pod when attempting switchover.")
+		return reconcile.Result{}, errors.New("Could not find a running pod when attempting switchover.")
 	}
-	exec := func(_ context.Context, stdin io.Reader, stdout, stderr io.Writer,
-		command ...string) error {
-		return r.PodExec(runningPod.Namespace, runningPod.Name, naming.ContainerDatabase, stdin,
-			stdout, stderr, command...)
+
+	// A switchover should not promote a replica that is unhealthy or behind.
+	// A failover is the "last resort" and happens regardless.
+	if targetInstance != nil && cluster.Spec.Patroni.Switchover.Type != "failover" {
+		if ready, known := targetInstance.IsReady(); !ready || !known {
+			return reconcile.Result{}, errors.New("TargetInstance is not ready")
+		}
+		for _, member := range cluster.Status.Patroni.Members {
+			if member.Name == targetInstance.Pods[0].Name && member.ReplicationLagBytes != nil &&
+				*member.ReplicationLagBytes > replicationLagThreshold(cluster) {
+				return reconcile.Result{}, errors.Errorf(
+					"TargetInstance is %d bytes behind the primary", *member.ReplicationLagBytes)
+			}
+		}
 	}
 
-	// We have the pod executor, now we need to figure out which API call to use
-	// In the default case we will be using SwitchoverAndWait. This API call uses
-	// a Patronictl switchover to move to the target instance.
-	action := func(ctx context.Context, exec patroni.Executor, next string) (bool, error) {
-		success, err := patroni.Executor(exec).SwitchoverAndWait(ctx, next)
-		return success, errors.WithStack(err)
+	api, err := r.patroniAPI(ctx, cluster, runningPod)
+	if err != nil {
+		return reconcile.Result{}, err
 	}
 
+	// We have the Patroni API, now we need to figure out which call to use.
+	// In the default case we will be using SwitchoverAndWait to move to the
+	// target instance.
+	action := api.SwitchoverAndWait
+
 	if cluster.Spec.Patroni.Switchover.Type == "failover" {
 		// When a failover has been requested we use FailoverAndWait to change the primary.
-		action = func(ctx context.Context, exec patroni.Executor, next string) (bool, error) {
-			success, err := patroni.Executor(exec).FailoverAndWait(ctx, next)
-			return success, errors.WithStack(err)
-		}
+		action = api.FailoverAndWait
 	}
 
 	// If target instance has not been provided, we will pass in an empty string to patronictl
@@ -538,13 +1359,14 @@ func (r *Reconciler) reconcilePatroniSwitchover(ctx context.Context,
 		nextPrimary = targetInstance.Pods[0].Name
 	}
 
-	success, err := action(ctx, exec, nextPrimary)
+	success, err := action(ctx, nextPrimary)
 	if err = errors.WithStack(err); err == nil && !success {
 		err = errors.New("unable to switchover")
 	}
 	if err == nil {
 		cluster.Status.Patroni.Switchover = initialize.String(switchoverAnnotation)
+		cluster.Status.Patroni.SwitchoverScheduledAt = nil
 	}
 
-	return err
+	return reconcile.Result{}, err
 }
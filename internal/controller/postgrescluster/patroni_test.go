@@ -22,6 +22,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"strconv"
 	"strings"
@@ -32,17 +33,25 @@ import (
 	"go.opentelemetry.io/otel"
 	"gotest.tools/v3/assert"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/crunchydata/postgres-operator/internal/initialize"
 	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/internal/patroni"
+	"github.com/crunchydata/postgres-operator/internal/pki"
+	"github.com/crunchydata/postgres-operator/internal/postgres"
 	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
 
@@ -126,6 +135,21 @@ ownerReferences:
 			"got %v", service.Spec.Selector)
 	})
 
+	t.Run("NotEndpoints", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Status.Patroni.DCS = "ConfigMaps"
+
+		service, err := reconciler.generatePatroniLeaderLeaseService(cluster)
+		assert.NilError(t, err)
+
+		// Selects the leader Pod rather than Endpoints maintained by Patroni.
+		assert.DeepEqual(t, service.Spec.Selector, map[string]string{
+			"postgres-operator.crunchydata.com/cluster": "pg2",
+			"postgres-operator.crunchydata.com/patroni": "pg2-ha",
+			"postgres-operator.crunchydata.com/role":    "master",
+		})
+	})
+
 	types := []struct {
 		Type   string
 		Expect func(testing.TB, *corev1.Service)
@@ -477,8 +501,10 @@ func TestReconcilePatroniSwitchover(t *testing.T) {
 	t.Cleanup(func() { teardownTestEnv(t, env) })
 
 	var called, failover, callError, callFails bool
+	recorder := record.NewFakeRecorder(10)
 	r := Reconciler{
-		Client: client,
+		Client:   client,
+		Recorder: recorder,
 		PodExec: func(namespace, pod, container string,
 			stdin io.Reader, stdout, stderr io.Writer, command ...string) error {
 			called = true
@@ -498,6 +524,13 @@ func TestReconcilePatroniSwitchover(t *testing.T) {
 
 	ctx := context.Background()
 
+	switchover := func(
+		ctx context.Context, cluster *v1beta1.PostgresCluster, instances *observedInstances,
+	) error {
+		_, err := r.reconcilePatroniSwitchover(ctx, cluster, instances)
+		return err
+	}
+
 	getObserved := func() *observedInstances {
 		instances := []*Instance{{
 			Name: "target",
@@ -506,6 +539,10 @@ func TestReconcilePatroniSwitchover(t *testing.T) {
 					Name: "pod",
 				},
 				Status: corev1.PodStatus{
+					Conditions: []corev1.PodCondition{{
+						Type:   corev1.PodReady,
+						Status: corev1.ConditionTrue,
+					}},
 					ContainerStatuses: []corev1.ContainerStatus{{
 						Name: naming.ContainerDatabase,
 						State: corev1.ContainerState{
@@ -538,7 +575,7 @@ func TestReconcilePatroniSwitchover(t *testing.T) {
 	t.Run("empty", func(t *testing.T) {
 		cluster := testCluster()
 		observed := newObservedInstances(cluster, nil, nil)
-		assert.NilError(t, r.reconcilePatroniSwitchover(ctx, cluster, observed))
+		assert.NilError(t, switchover(ctx, cluster, observed))
 	})
 
 	t.Run("early validation", func(t *testing.T) {
@@ -549,6 +586,7 @@ func TestReconcilePatroniSwitchover(t *testing.T) {
 			status  string
 			soType  string
 			target  string
+			paused  bool
 			check   func(*testing.T, error)
 		}{
 			{
@@ -572,6 +610,13 @@ func TestReconcilePatroniSwitchover(t *testing.T) {
 					assert.NilError(t, err)
 				},
 			},
+			{
+				desc:    "Patroni is paused",
+				enabled: true, trigger: "triggered", paused: true,
+				check: func(t *testing.T, err error) {
+					assert.NilError(t, err)
+				},
+			},
 			{
 				desc:    "failover requested without a target",
 				enabled: true, trigger: "triggered", soType: "failover",
@@ -616,10 +661,13 @@ func TestReconcilePatroniSwitchover(t *testing.T) {
 				if test.soType == "failover" {
 					cluster.Spec.Patroni.Switchover.Type = "failover"
 				}
+				if test.paused {
+					cluster.Spec.Patroni.Paused = true
+				}
 				if test.target != "" {
 					cluster.Spec.Patroni.Switchover.TargetInstance = initialize.String(test.target)
 				}
-				test.check(t, r.reconcilePatroniSwitchover(ctx, cluster, getObserved()))
+				test.check(t, switchover(ctx, cluster, getObserved()))
 			})
 		}
 	})
@@ -644,7 +692,7 @@ func TestReconcilePatroniSwitchover(t *testing.T) {
 			}}
 			observed := &observedInstances{forCluster: instances}
 
-			assert.Equal(t, r.reconcilePatroniSwitchover(ctx, cluster, observed).Error(),
+			assert.Equal(t, switchover(ctx, cluster, observed).Error(),
 				"TargetInstance should have one pod. Pods (0)")
 		})
 
@@ -668,7 +716,7 @@ func TestReconcilePatroniSwitchover(t *testing.T) {
 			}
 			observed := &observedInstances{forCluster: instances}
 
-			assert.Equal(t, r.reconcilePatroniSwitchover(ctx, cluster, observed).Error(),
+			assert.Equal(t, switchover(ctx, cluster, observed).Error(),
 				"Could not find a running pod when attempting switchover.")
 		})
 	})
@@ -688,7 +736,7 @@ func TestReconcilePatroniSwitchover(t *testing.T) {
 		observed := &observedInstances{forCluster: []*Instance{{
 			Name: "target",
 		}}}
-		assert.Equal(t, r.reconcilePatroniSwitchover(ctx, cluster, observed).Error(),
+		assert.Equal(t, switchover(ctx, cluster, observed).Error(),
 			"Need more than one instance to switchover")
 	})
 
@@ -708,7 +756,7 @@ func TestReconcilePatroniSwitchover(t *testing.T) {
 			DataVolumeClaimSpec: testVolumeClaimSpec(),
 		}}
 		called, failover, callError, callFails = false, false, false, true
-		err := r.reconcilePatroniSwitchover(ctx, cluster, getObserved())
+		err := switchover(ctx, cluster, getObserved())
 		assert.Equal(t, err.Error(), "unable to switchover")
 		assert.Assert(t, called)
 		assert.Assert(t, cluster.Status.Patroni.Switchover == nil)
@@ -730,7 +778,7 @@ func TestReconcilePatroniSwitchover(t *testing.T) {
 			DataVolumeClaimSpec: testVolumeClaimSpec(),
 		}}
 		called, failover, callError, callFails = false, false, true, false
-		err := r.reconcilePatroniSwitchover(ctx, cluster, getObserved())
+		err := switchover(ctx, cluster, getObserved())
 		assert.Equal(t, err.Error(), "boom")
 		assert.Assert(t, called)
 		assert.Assert(t, cluster.Status.Patroni.Switchover == nil)
@@ -752,7 +800,7 @@ func TestReconcilePatroniSwitchover(t *testing.T) {
 			DataVolumeClaimSpec: testVolumeClaimSpec(),
 		}}
 		called, failover, callError, callFails = false, false, false, false
-		assert.NilError(t, r.reconcilePatroniSwitchover(ctx, cluster, getObserved()))
+		assert.NilError(t, switchover(ctx, cluster, getObserved()))
 		assert.Assert(t, called)
 		assert.Equal(t, *cluster.Status.Patroni.Switchover, "trigger")
 	})
@@ -774,7 +822,7 @@ func TestReconcilePatroniSwitchover(t *testing.T) {
 			DataVolumeClaimSpec: testVolumeClaimSpec(),
 		}}
 		called, failover, callError, callFails = false, false, false, false
-		assert.NilError(t, r.reconcilePatroniSwitchover(ctx, cluster, getObserved()))
+		assert.NilError(t, switchover(ctx, cluster, getObserved()))
 		assert.Assert(t, called)
 		assert.Equal(t, *cluster.Status.Patroni.Switchover, "trigger")
 	})
@@ -797,8 +845,1059 @@ func TestReconcilePatroniSwitchover(t *testing.T) {
 			DataVolumeClaimSpec: testVolumeClaimSpec(),
 		}}
 		called, failover, callError, callFails = false, true, false, false
-		assert.NilError(t, r.reconcilePatroniSwitchover(ctx, cluster, getObserved()))
+		assert.NilError(t, switchover(ctx, cluster, getObserved()))
 		assert.Assert(t, called)
 		assert.Equal(t, *cluster.Status.Patroni.Switchover, "trigger")
 	})
+
+	t.Run("targeted switchover prechecks", func(t *testing.T) {
+		cluster := testCluster()
+		cluster.Annotations = map[string]string{
+			naming.PatroniSwitchover: "trigger",
+		}
+		cluster.Spec.Patroni = &v1beta1.PatroniSpec{
+			Switchover: &v1beta1.PatroniSwitchover{
+				Enabled:        true,
+				TargetInstance: initialize.String("target"),
+			},
+		}
+
+		t.Run("not ready", func(t *testing.T) {
+			observed := getObserved()
+			observed.forCluster[0].Pods[0].Status.Conditions = nil
+
+			called, failover, callError, callFails = false, false, false, false
+			assert.ErrorContains(t, switchover(ctx, cluster, observed), "not ready")
+			assert.Assert(t, !called)
+			assert.Assert(t, cluster.Status.Patroni.Switchover == nil)
+		})
+
+		t.Run("behind", func(t *testing.T) {
+			cluster := cluster.DeepCopy()
+			cluster.Status.Patroni.Members = []v1beta1.PatroniMemberStatus{{
+				Name: "pod", ReplicationLagBytes: initialize.Int64(100 << 20),
+			}}
+
+			called, failover, callError, callFails = false, false, false, false
+			assert.ErrorContains(t, switchover(ctx, cluster, getObserved()), "behind the primary")
+			assert.Assert(t, !called)
+			assert.Assert(t, cluster.Status.Patroni.Switchover == nil)
+		})
+	})
+
+	t.Run("scheduled switchover", func(t *testing.T) {
+		cluster := testCluster()
+		cluster.Annotations = map[string]string{
+			naming.PatroniSwitchover: "trigger",
+		}
+		cluster.Spec.Patroni = &v1beta1.PatroniSpec{
+			Switchover: &v1beta1.PatroniSwitchover{
+				Enabled:     true,
+				ScheduledAt: &metav1.Time{Time: time.Now().Add(time.Hour)},
+			},
+		}
+
+		called, failover, callError, callFails = false, false, false, false
+		result, err := r.reconcilePatroniSwitchover(ctx, cluster, getObserved())
+		assert.NilError(t, err)
+		assert.Assert(t, !called)
+		assert.Assert(t, result.RequeueAfter > 59*time.Minute, "got %v", result.RequeueAfter)
+		assert.Assert(t, cluster.Status.Patroni.Switchover == nil)
+		assert.DeepEqual(t, cluster.Status.Patroni.SwitchoverScheduledAt,
+			cluster.Spec.Patroni.Switchover.ScheduledAt)
+
+		t.Run("due", func(t *testing.T) {
+			cluster := cluster.DeepCopy()
+			cluster.Spec.Patroni.Switchover.ScheduledAt.Time = time.Now().Add(-time.Minute)
+
+			called = false
+			result, err := r.reconcilePatroniSwitchover(ctx, cluster, getObserved())
+			assert.NilError(t, err)
+			assert.Assert(t, called)
+			assert.Equal(t, result, reconcile.Result{})
+			assert.Equal(t, *cluster.Status.Patroni.Switchover, "trigger")
+			assert.Assert(t, cluster.Status.Patroni.SwitchoverScheduledAt == nil)
+		})
+
+		t.Run("cancelled", func(t *testing.T) {
+			cluster := cluster.DeepCopy()
+			cluster.Spec.Patroni.Switchover.ScheduledAt = nil
+
+			called = false
+			assert.NilError(t, switchover(ctx, cluster, getObserved()))
+			assert.Assert(t, !called)
+			assert.Equal(t, *cluster.Status.Patroni.Switchover, "trigger")
+			assert.Assert(t, cluster.Status.Patroni.SwitchoverScheduledAt == nil)
+
+			event := <-recorder.Events
+			assert.Assert(t, strings.Contains(event, "SwitchoverCancelled"), event)
+		})
+	})
+}
+
+func TestPatroniAPI(t *testing.T) {
+	ctx := context.Background()
+
+	cluster := testCluster()
+	cluster.Default()
+	cluster.Namespace = "ns1"
+	pod := &corev1.Pod{}
+	pod.Namespace = "ns1"
+	pod.Spec.Hostname = "some-instance-0"
+	pod.Spec.Subdomain = "hippo-pods"
+
+	t.Run("Exec", func(t *testing.T) {
+		var calls []string
+		r := &Reconciler{PodExec: func(
+			namespace, pod, container string, _ io.Reader, _, _ io.Writer, command ...string,
+		) error {
+			calls = append(calls, namespace+"/"+pod+"/"+container+" "+command[0])
+			return nil
+		}}
+		pod := pod.DeepCopy()
+		pod.Name = "some-pod"
+
+		api, err := r.patroniAPI(ctx, cluster, pod)
+		assert.NilError(t, err)
+
+		_, ok := api.(patroni.Executor)
+		assert.Assert(t, ok, "expected exec, got %T", api)
+
+		assert.NilError(t, api.ReplaceConfiguration(ctx, nil))
+		assert.DeepEqual(t, calls, []string{"ns1/some-pod/database patronictl"})
+	})
+
+	t.Run("REST", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Patroni.APIClient = "REST"
+
+		root := pki.NewRootCertificateAuthority()
+		assert.NilError(t, root.Generate())
+
+		secret := &corev1.Secret{}
+		secret.Namespace, secret.Name = "ns1", naming.RootCertSecret
+		secret.Data = map[string][]byte{}
+		secret.Data["root.crt"], _ = root.Certificate.MarshalText()
+		secret.Data["root.key"], _ = root.PrivateKey.MarshalText()
+
+		r := &Reconciler{}
+		r.Client = fake.NewClientBuilder().Build()
+
+		// The root certificate must already exist.
+		_, err := r.patroniAPI(ctx, cluster, pod)
+		assert.Assert(t, apierrors.IsNotFound(err), "got %#v", err)
+
+		assert.NilError(t, r.Client.Create(ctx, secret))
+
+		api, err := r.patroniAPI(ctx, cluster, pod)
+		assert.NilError(t, err)
+
+		rest, ok := api.(*patroni.Client)
+		assert.Assert(t, ok, "expected REST, got %T", api)
+		assert.Equal(t, rest.BaseURL, "https://some-instance-0.hippo-pods.ns1.svc:8008")
+
+		// The HTTP client and its connections are reused.
+		again, err := r.patroniAPI(ctx, cluster, pod)
+		assert.NilError(t, err)
+		assert.Assert(t, again.(*patroni.Client).HTTP == rest.HTTP)
+
+		// The HTTP client is replaced when the root certificate changes.
+		assert.NilError(t, root.Generate())
+		secret.Data["root.crt"], _ = root.Certificate.MarshalText()
+		secret.Data["root.key"], _ = root.PrivateKey.MarshalText()
+		assert.NilError(t, r.Client.Update(ctx, secret))
+
+		again, err = r.patroniAPI(ctx, cluster, pod)
+		assert.NilError(t, err)
+		assert.Assert(t, again.(*patroni.Client).HTTP != rest.HTTP)
+
+		// The HTTP client is discarded when the cluster is gone.
+		r.forgetPatroniHTTPClient(client.ObjectKeyFromObject(cluster))
+		assert.Equal(t, len(r.patroniClients.clients), 0)
+	})
+}
+
+func TestReconcilePatroniMembers(t *testing.T) {
+	ctx := context.Background()
+
+	running := func(name, role string) *corev1.Pod {
+		pod := &corev1.Pod{}
+		pod.Namespace, pod.Name = "ns1", name
+		pod.Annotations = map[string]string{"status": `{"role":"` + role + `"}`}
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name:  naming.ContainerDatabase,
+			State: corev1.ContainerState{Running: new(corev1.ContainerStateRunning)},
+		}}
+		return pod
+	}
+
+	instances := &observedInstances{forCluster: []*Instance{
+		{Name: "one", Pods: []*corev1.Pod{running("one-0", "master")},
+			Spec: &v1beta1.PostgresInstanceSetSpec{Name: "a"}},
+		{Name: "two", Pods: []*corev1.Pod{running("two-0", "replica")},
+			Spec: &v1beta1.PostgresInstanceSetSpec{Name: "a"}},
+		{Name: "three", Pods: []*corev1.Pod{running("three-0", "replica")},
+			Spec: &v1beta1.PostgresInstanceSetSpec{Name: "b",
+				RecoveryMinApplyDelay: &metav1.Duration{Duration: time.Hour}}},
+	}}
+
+	const members = `[
+		{"Member":"one-0","Role":"Leader","State":"running","TL":2,"Lag in MB":0},
+		{"Member":"two-0","Role":"Sync Standby","State":"running","TL":2,"Lag in MB":0,"Tags":{"nosync":true}},
+		{"Member":"three-0","Role":"Replica","State":"running","TL":2,"Lag in MB":64,"Pending restart":"*"}
+	]`
+
+	reconciler := func(replication string, calls *[]string) *Reconciler {
+		return &Reconciler{PodExec: func(
+			namespace, pod, container string, _ io.Reader, stdout, _ io.Writer, command ...string,
+		) error {
+			*calls = append(*calls, pod+" "+command[0])
+			if command[0] == "patronictl" {
+				_, err := stdout.Write([]byte(members))
+				return err
+			}
+			if replication == "" {
+				return errors.New("connection refused")
+			}
+			_, err := stdout.Write([]byte(replication))
+			return err
+		}}
+	}
+
+	t.Run("NoRunningPods", func(t *testing.T) {
+		cluster := testCluster()
+		var calls []string
+		r := reconciler("", &calls)
+
+		result := r.reconcilePatroniMembers(ctx, cluster, &observedInstances{})
+		assert.Equal(t, result, reconcile.Result{})
+		assert.Assert(t, calls == nil)
+		assert.Assert(t, cluster.Status.Patroni.Members == nil)
+		assert.Assert(t, meta.FindStatusCondition(cluster.Status.Conditions,
+			v1beta1.ReplicationLagHigh) == nil)
+	})
+
+	t.Run("Healthy", func(t *testing.T) {
+		cluster := testCluster()
+		cluster.Generation = 3
+		var calls []string
+		r := reconciler(`{"lsn":"0/5000000","standbys":{
+			"two-0":{"lsn":"0/4F00000","lag":1048576},
+			"three-0":{"lsn":"0/1000000","lag":67108864}},"slots":{
+			"two_0":{"type":"physical","active":true,"retained":1048576,"wal_status":"reserved"},
+			"cdc":{"type":"logical","active":false,"database":"app","plugin":"pgoutput","retained":null}}}`, &calls)
+
+		result := r.reconcilePatroniMembers(ctx, cluster, instances)
+		assert.Equal(t, result.RequeueAfter, patroniMembersInterval)
+		assert.DeepEqual(t, calls, []string{"one-0 patronictl", "one-0 psql"})
+
+		status := cluster.Status.Patroni
+		assert.Assert(t, status.MembersObservedTime != nil)
+		assert.DeepEqual(t, status.Members, []v1beta1.PatroniMemberStatus{
+			{Name: "one-0", Role: "leader", State: "running", Timeline: 2, LSN: "0/5000000"},
+			{Name: "three-0", Role: "replica", State: "running", Timeline: 2,
+				LSN: "0/1000000", ReplicationLagBytes: initialize.Int64(67108864),
+				PendingRestart: true},
+			{Name: "two-0", Role: "sync_standby", State: "running", Timeline: 2,
+				LSN: "0/4F00000", ReplicationLagBytes: initialize.Int64(1048576),
+				Tags: map[string]string{"nosync": "true"}},
+		})
+
+		assert.DeepEqual(t, status.SynchronousStandbys, []string{"two-0"})
+		assert.DeepEqual(t, status.ReplicationSlots, []v1beta1.PatroniSlotStatus{
+			{Name: "cdc", Type: "logical", Database: "app", Plugin: "pgoutput"},
+			{Name: "two_0", Type: "physical", Active: true,
+				RetainedBytes: initialize.Int64(1048576), WALStatus: "reserved"},
+		})
+
+		// The delayed replica does not count.
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, v1beta1.ReplicationLagHigh)
+		assert.Assert(t, condition != nil)
+		assert.Equal(t, condition.Status, metav1.ConditionFalse)
+		assert.Equal(t, condition.ObservedGeneration, int64(3))
+	})
+
+	t.Run("Lagging", func(t *testing.T) {
+		cluster := testCluster()
+		cluster.Spec.Patroni = &v1beta1.PatroniSpec{
+			ReplicationLagThreshold: resource.NewQuantity(1024, resource.BinarySI),
+		}
+		var calls []string
+		r := reconciler(`{"lsn":"0/5000000","standbys":{
+			"two-0":{"lsn":"0/4F00000","lag":1048576}}}`, &calls)
+
+		_ = r.reconcilePatroniMembers(ctx, cluster, instances)
+
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, v1beta1.ReplicationLagHigh)
+		assert.Assert(t, condition != nil)
+		assert.Equal(t, condition.Status, metav1.ConditionTrue)
+		assert.Equal(t, condition.Reason, "ReplicationLagging")
+		assert.Assert(t, strings.Contains(condition.Message, "two-0"), condition.Message)
+		assert.Assert(t, !strings.Contains(condition.Message, "three-0"), condition.Message)
+	})
+
+	t.Run("PatroniLag", func(t *testing.T) {
+		// When PostgreSQL cannot be reached, lag comes from Patroni.
+		cluster := testCluster()
+		var calls []string
+		r := reconciler("", &calls)
+
+		_ = r.reconcilePatroniMembers(ctx, cluster, instances)
+
+		status := cluster.Status.Patroni
+		assert.Equal(t, len(status.Members), 3)
+		assert.Equal(t, status.Members[0].LSN, "")
+		assert.Assert(t, status.Members[0].ReplicationLagBytes == nil)
+		assert.DeepEqual(t, status.Members[1].ReplicationLagBytes, initialize.Int64(64*1024*1024))
+		assert.DeepEqual(t, status.Members[2].ReplicationLagBytes, initialize.Int64(0))
+		assert.Assert(t, status.ReplicationSlots == nil)
+	})
+
+	t.Run("Interval", func(t *testing.T) {
+		cluster := testCluster()
+		var calls []string
+		r := reconciler("", &calls)
+
+		recent := metav1.NewTime(time.Now().Add(-10 * time.Second))
+		cluster.Status.Patroni.MembersObservedTime = &recent
+
+		// Members are not observed again until the interval has passed.
+		result := r.reconcilePatroniMembers(ctx, cluster, instances)
+		assert.Assert(t, calls == nil)
+		assert.Assert(t, cluster.Status.Patroni.Members == nil)
+		assert.Assert(t, result.RequeueAfter > 0)
+		assert.Assert(t, result.RequeueAfter <= patroniMembersInterval-10*time.Second)
+
+		earlier := metav1.NewTime(time.Now().Add(-patroniMembersInterval))
+		cluster.Status.Patroni.MembersObservedTime = &earlier
+
+		result = r.reconcilePatroniMembers(ctx, cluster, instances)
+		assert.Equal(t, result.RequeueAfter, patroniMembersInterval)
+		assert.Assert(t, calls != nil)
+		assert.Equal(t, len(cluster.Status.Patroni.Members), 3)
+		assert.Assert(t, earlier.Before(cluster.Status.Patroni.MembersObservedTime))
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		state := "start failed"
+		r := &Reconciler{PodExec: func(
+			_, _, _ string, _ io.Reader, stdout, _ io.Writer, command ...string,
+		) error {
+			if command[0] != "patronictl" {
+				return errors.New("connection refused")
+			}
+			_, err := stdout.Write([]byte(`[
+				{"Member":"one-0","Role":"Leader","State":"running","TL":2},
+				{"Member":"two-0","Role":"Replica","State":"` + state + `","TL":1}
+			]`))
+			return err
+		}}
+
+		cluster := testCluster()
+		_ = r.reconcilePatroniMembers(ctx, cluster, instances)
+
+		members := cluster.Status.Patroni.Members
+		assert.Assert(t, members[0].FailedTime == nil)
+		assert.Assert(t, members[1].FailedTime != nil)
+
+		// The first failure is remembered.
+		earlier := metav1.NewTime(time.Now().Add(-time.Hour))
+		members[1].FailedTime = &earlier
+		cluster.Status.Patroni.MembersObservedTime = nil
+		_ = r.reconcilePatroniMembers(ctx, cluster, instances)
+		assert.Assert(t, cluster.Status.Patroni.Members[1].FailedTime.Equal(&earlier))
+
+		// It is forgotten once the member is running again.
+		state = "running"
+		cluster.Status.Patroni.MembersObservedTime = nil
+		_ = r.reconcilePatroniMembers(ctx, cluster, instances)
+		assert.Assert(t, cluster.Status.Patroni.Members[1].FailedTime == nil)
+	})
+
+	t.Run("CrashLoop", func(t *testing.T) {
+		state := "starting"
+		r := &Reconciler{PodExec: func(
+			_, _, _ string, _ io.Reader, stdout, _ io.Writer, command ...string,
+		) error {
+			if command[0] != "patronictl" {
+				return errors.New("connection refused")
+			}
+			_, err := stdout.Write([]byte(`[
+				{"Member":"one-0","Role":"Leader","State":"running","TL":2},
+				{"Member":"two-0","Role":"Replica","State":"` + state + `","TL":2}
+			]`))
+			return err
+		}}
+
+		crashing := running("three-0", "replica")
+		crashing.Status.ContainerStatuses[0].State = corev1.ContainerState{
+			Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+		}
+		restarted := running("two-0", "replica")
+		restarted.Status.ContainerStatuses[0].RestartCount = 5
+		restarted.Status.ContainerStatuses[0].State.Running.StartedAt = metav1.Now()
+
+		instances := &observedInstances{forCluster: []*Instance{
+			{Name: "one", Pods: []*corev1.Pod{running("one-0", "master")}},
+			{Name: "two", Pods: []*corev1.Pod{restarted}},
+			{Name: "three", Pods: []*corev1.Pod{crashing}},
+		}}
+
+		// Patroni does not report the crash looping member.
+		cluster := testCluster()
+		_ = r.reconcilePatroniMembers(ctx, cluster, instances)
+
+		members := cluster.Status.Patroni.Members
+		assert.Equal(t, len(members), 3)
+		assert.Equal(t, members[1].Name, "three-0")
+		assert.Equal(t, members[1].State, "")
+		assert.Assert(t, members[1].FailedTime != nil)
+
+		// A container that restarted is not failing until a failure is seen.
+		assert.Equal(t, members[2].Name, "two-0")
+		assert.Assert(t, members[2].FailedTime == nil)
+
+		// A container that restarted after the member started failing is still
+		// failing, even when Patroni reports it between crashes.
+		earlier := metav1.NewTime(time.Now().Add(-time.Hour))
+		members[1].FailedTime = &earlier
+		members[2].FailedTime = &earlier
+		cluster.Status.Patroni.MembersObservedTime = nil
+		_ = r.reconcilePatroniMembers(ctx, cluster, instances)
+
+		members = cluster.Status.Patroni.Members
+		assert.Assert(t, members[1].FailedTime.Equal(&earlier))
+		assert.Assert(t, members[2].FailedTime.Equal(&earlier))
+
+		// It is forgotten once the member is running again.
+		state = "running"
+		cluster.Status.Patroni.MembersObservedTime = nil
+		_ = r.reconcilePatroniMembers(ctx, cluster, instances)
+		assert.Assert(t, cluster.Status.Patroni.Members[2].FailedTime == nil)
+	})
+}
+
+func TestReconcilePatroniReinitialize(t *testing.T) {
+	ctx := context.Background()
+	env, cc, _ := setupTestEnv(t, ControllerName)
+	t.Cleanup(func() { teardownTestEnv(t, env) })
+
+	ns := &corev1.Namespace{}
+	ns.GenerateName = "postgres-operator-test-"
+	assert.NilError(t, cc.Create(ctx, ns))
+	t.Cleanup(func() { assert.Check(t, cc.Delete(ctx, ns)) })
+
+	pod := func(name, role string) *corev1.Pod {
+		pod := &corev1.Pod{}
+		pod.Namespace, pod.Name = ns.Name, name
+		pod.Annotations = map[string]string{"status": `{"role":"` + role + `"}`}
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name:  naming.ContainerDatabase,
+			State: corev1.ContainerState{Running: new(corev1.ContainerStateRunning)},
+		}}
+		return pod
+	}
+	observe := func(pods ...*corev1.Pod) *observedInstances {
+		observed := &observedInstances{}
+		for _, p := range pods {
+			observed.forCluster = append(observed.forCluster,
+				&Instance{Name: p.Name, Pods: []*corev1.Pod{p}})
+		}
+		return observed
+	}
+	failedAgo := func(d time.Duration) *metav1.Time {
+		t := metav1.NewTime(time.Now().Add(-d))
+		return &t
+	}
+
+	// Patroni lists the members in list when asked.
+	reconciler := func(calls *[]string, list string) (*Reconciler, *record.FakeRecorder) {
+		recorder := record.NewFakeRecorder(10)
+		return &Reconciler{
+			Client:   cc,
+			Owner:    client.FieldOwner(t.Name()),
+			Recorder: recorder,
+			PodExec: func(
+				_, pod, _ string, _ io.Reader, stdout, _ io.Writer, command ...string,
+			) error {
+				if len(command) > 1 && command[1] == "list" {
+					_, err := stdout.Write([]byte(list))
+					return err
+				}
+				*calls = append(*calls, pod+" "+strings.Join(command, " "))
+				return nil
+			},
+		}, recorder
+	}
+
+	t.Run("Nothing", func(t *testing.T) {
+		var calls []string
+		r, recorder := reconciler(&calls, `[]`)
+
+		cluster := testCluster()
+		cluster.Spec.Patroni = &v1beta1.PatroniSpec{
+			Reinitialization: &v1beta1.PatroniReinitialization{Policy: "Automatic"},
+		}
+		cluster.Status.Patroni.Members = []v1beta1.PatroniMemberStatus{
+			{Name: "one-0", Role: "leader", State: "running"},
+			{Name: "two-0", Role: "replica", State: "start failed", FailedTime: failedAgo(time.Minute)},
+		}
+
+		// The replica has not been failing long enough.
+		result, err := r.reconcilePatroniReinitialize(ctx, cluster,
+			observe(pod("one-0", "master"), pod("two-0", "replica")))
+		assert.NilError(t, err)
+		assert.Assert(t, result.RequeueAfter > 3*time.Minute, "got %v", result.RequeueAfter)
+		assert.Assert(t, calls == nil)
+		assert.Equal(t, len(recorder.Events), 0)
+	})
+
+	t.Run("NotReported", func(t *testing.T) {
+		var calls []string
+		r, recorder := reconciler(&calls, `[]`)
+
+		cluster := testCluster()
+		cluster.Spec.Patroni = &v1beta1.PatroniSpec{
+			Reinitialization: &v1beta1.PatroniReinitialization{Policy: "Automatic"},
+		}
+		cluster.Status.Patroni.Members = []v1beta1.PatroniMemberStatus{
+			{Name: "one-0", Role: "leader", State: "running"},
+			{Name: "two-0", FailedTime: failedAgo(time.Hour)},
+		}
+
+		// Patroni cannot reinitialize a member it does not report. Wait for
+		// the crash looping member to start again.
+		result, err := r.reconcilePatroniReinitialize(ctx, cluster,
+			observe(pod("one-0", "master"), pod("two-0", "replica")))
+		assert.NilError(t, err)
+		assert.Equal(t, result.RequeueAfter, patroniMembersInterval)
+		assert.Assert(t, calls == nil)
+		assert.Equal(t, len(recorder.Events), 0)
+	})
+
+	t.Run("Manual", func(t *testing.T) {
+		var calls []string
+		r, recorder := reconciler(&calls, `[]`)
+
+		cluster := testCluster()
+		cluster.Status.Patroni.Members = []v1beta1.PatroniMemberStatus{
+			{Name: "one-0", Role: "leader", State: "running"},
+			{Name: "two-0", Role: "replica", State: "start failed", FailedTime: failedAgo(time.Hour)},
+			{Name: "three-0", Role: "replica", State: "running"},
+		}
+
+		leader, failed, requested := pod("one-0", "master"), pod("two-0", "replica"), pod("three-0", "replica")
+		leader.Annotations[naming.PatroniReinitialize] = "oops"
+		requested.Annotations[naming.PatroniReinitialize] = "please"
+		for _, p := range []*corev1.Pod{leader, requested} {
+			stored := p.DeepCopy()
+			stored.Spec.Containers = []corev1.Container{{Name: "c", Image: "i"}}
+			assert.NilError(t, cc.Create(ctx, stored))
+			p.ObjectMeta = *stored.ObjectMeta.DeepCopy()
+		}
+
+		// Only the requested replica is reinitialized; the policy is Manual.
+		_, err := r.reconcilePatroniReinitialize(ctx, cluster, observe(leader, failed, requested))
+		assert.NilError(t, err)
+		assert.DeepEqual(t, calls, []string{
+			"one-0 patronictl reload --force " + naming.PatroniScope(cluster) + " three-0",
+			"one-0 patronictl reinit --force " + naming.PatroniScope(cluster) + " three-0",
+		})
+
+		assert.Equal(t, len(recorder.Events), 2)
+		assert.Assert(t, strings.Contains(<-recorder.Events, "ReinitializeRefused"))
+		assert.Assert(t, strings.Contains(<-recorder.Events, "three-0 as requested"))
+
+		// Both annotations are removed.
+		for _, p := range []*corev1.Pod{leader, requested} {
+			stored := &corev1.Pod{}
+			assert.NilError(t, cc.Get(ctx, client.ObjectKeyFromObject(p), stored))
+			_, found := stored.Annotations[naming.PatroniReinitialize]
+			assert.Assert(t, !found, "expected no annotation on %s", p.Name)
+		}
+	})
+
+	t.Run("Automatic", func(t *testing.T) {
+		var calls []string
+		r, recorder := reconciler(&calls, `[
+			{"Member":"one-0","Role":"Leader","State":"running"},
+			{"Member":"two-0","Role":"Replica","State":"creating replica"},
+			{"Member":"three-0","Role":"Replica","State":"crashed"},
+			{"Member":"four-0","Role":"Replica","State":"start failed"}
+		]`)
+
+		cluster := testCluster()
+		cluster.Spec.Patroni = &v1beta1.PatroniSpec{
+			Reinitialization: &v1beta1.PatroniReinitialization{
+				Policy:        "Automatic",
+				FailedSeconds: initialize.Int32(60),
+				MaxConcurrent: initialize.Int32(2),
+			},
+		}
+		cluster.Status.Patroni.Members = []v1beta1.PatroniMemberStatus{
+			{Name: "one-0", Role: "leader", State: "running"},
+			{Name: "two-0", Role: "replica", State: "running"},
+			{Name: "three-0", Role: "replica", State: "crashed", FailedTime: failedAgo(time.Hour)},
+			{Name: "four-0", Role: "replica", State: "start failed", FailedTime: failedAgo(2 * time.Hour)},
+		}
+
+		// One replica is already reinitializing according to Patroni, though
+		// not yet in status, so only the one failing the longest starts.
+		result, err := r.reconcilePatroniReinitialize(ctx, cluster, observe(
+			pod("one-0", "master"), pod("two-0", "replica"),
+			pod("three-0", "replica"), pod("four-0", "replica")))
+		assert.NilError(t, err)
+		assert.Equal(t, result.RequeueAfter, patroniMembersInterval)
+		assert.DeepEqual(t, calls, []string{
+			"one-0 patronictl reload --force " + naming.PatroniScope(cluster) + " four-0",
+			"one-0 patronictl reinit --force " + naming.PatroniScope(cluster) + " four-0",
+		})
+		assert.Assert(t, cluster.Status.Patroni.Members[3].FailedTime == nil)
+
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Assert(t, strings.Contains(<-recorder.Events, `four-0 because it has been "start failed"`))
+	})
+
+	t.Run("NoPrimary", func(t *testing.T) {
+		var calls []string
+		r, _ := reconciler(&calls, `[]`)
+
+		cluster := testCluster()
+		cluster.Spec.Patroni = &v1beta1.PatroniSpec{
+			Reinitialization: &v1beta1.PatroniReinitialization{Policy: "Automatic"},
+		}
+		cluster.Status.Patroni.Members = []v1beta1.PatroniMemberStatus{
+			{Name: "two-0", Role: "replica", State: "start failed", FailedTime: failedAgo(time.Hour)},
+		}
+
+		result, err := r.reconcilePatroniReinitialize(ctx, cluster, observe(pod("two-0", "replica")))
+		assert.NilError(t, err)
+		assert.Equal(t, result.RequeueAfter, patroniMembersInterval)
+		assert.Assert(t, calls == nil)
+	})
+}
+
+func TestReconcilePatroniDynamicConfigurationSynchronous(t *testing.T) {
+	ctx := context.Background()
+	r := &Reconciler{}
+
+	cluster := testCluster()
+	cluster.Generation = 2
+	cluster.Spec.InstanceSets[0].Replicas = initialize.Int32(2)
+	cluster.Spec.Patroni = &v1beta1.PatroniSpec{
+		Synchronous: &v1beta1.PatroniSynchronous{StandbyCount: initialize.Int32(1)},
+	}
+
+	// One replica can be a synchronous standby.
+	assert.NilError(t, r.reconcilePatroniDynamicConfiguration(
+		ctx, cluster, &observedInstances{}, postgres.HBAs{}, postgres.Parameters{}))
+
+	condition := meta.FindStatusCondition(cluster.Status.Conditions, ConditionSynchronousReplication)
+	assert.Assert(t, condition != nil)
+	assert.Equal(t, condition.Status, metav1.ConditionTrue)
+	assert.Equal(t, condition.ObservedGeneration, int64(2))
+
+	cluster.Spec.Patroni.Synchronous.StandbyCount = initialize.Int32(2)
+	assert.NilError(t, r.reconcilePatroniDynamicConfiguration(
+		ctx, cluster, &observedInstances{}, postgres.HBAs{}, postgres.Parameters{}))
+
+	condition = meta.FindStatusCondition(cluster.Status.Conditions, ConditionSynchronousReplication)
+	assert.Assert(t, condition != nil)
+	assert.Equal(t, condition.Status, metav1.ConditionFalse)
+	assert.Equal(t, condition.Reason, "NotEnoughInstances")
+	assert.Assert(t, strings.Contains(condition.Message, "spec.patroni.synchronous.standbyCount"),
+		condition.Message)
+
+	// The condition goes away with synchronous replication.
+	cluster.Spec.Patroni.Synchronous.Mode = "Off"
+	assert.NilError(t, r.reconcilePatroniDynamicConfiguration(
+		ctx, cluster, &observedInstances{}, postgres.HBAs{}, postgres.Parameters{}))
+	assert.Assert(t, meta.FindStatusCondition(
+		cluster.Status.Conditions, ConditionSynchronousReplication) == nil)
+}
+
+func TestPatroniFailoverValidation(t *testing.T) {
+	ctx := context.Background()
+	env, cc, _ := setupTestEnv(t, ControllerName)
+	t.Cleanup(func() { teardownTestEnv(t, env) })
+
+	ns := &corev1.Namespace{}
+	ns.GenerateName = "postgres-operator-test-"
+	assert.NilError(t, cc.Create(ctx, ns))
+	t.Cleanup(func() { assert.Check(t, cc.Delete(ctx, ns)) })
+
+	base := testCluster()
+	base.Namespace = ns.Name
+
+	t.Run("Valid", func(t *testing.T) {
+		for _, lag := range []string{"0", "16Mi", "1G"} {
+			cluster := base.DeepCopy()
+			quantity := resource.MustParse(lag)
+			cluster.Spec.Patroni = &v1beta1.PatroniSpec{
+				Failover: &v1beta1.PatroniFailoverLimits{MaximumLag: &quantity},
+			}
+			assert.NilError(t, cc.Create(ctx, cluster, client.DryRunAll), "lag %q", lag)
+		}
+	})
+
+	t.Run("NegativeLag", func(t *testing.T) {
+		for _, lag := range []string{"-1", "-1Mi"} {
+			cluster := base.DeepCopy()
+			quantity := resource.MustParse(lag)
+			cluster.Spec.Patroni = &v1beta1.PatroniSpec{
+				Failover: &v1beta1.PatroniFailoverLimits{MaximumLag: &quantity},
+			}
+
+			err := cc.Create(ctx, cluster, client.DryRunAll)
+			assert.Assert(t, apierrors.IsInvalid(err), "expected Invalid, got\n%#v", err)
+			assert.ErrorContains(t, err, "spec.patroni.failover.maximumLag")
+		}
+	})
+}
+
+func TestPatroniPermanentSlotsValidation(t *testing.T) {
+	ctx := context.Background()
+	env, cc, _ := setupTestEnv(t, ControllerName)
+	t.Cleanup(func() { teardownTestEnv(t, env) })
+
+	ns := &corev1.Namespace{}
+	ns.GenerateName = "postgres-operator-test-"
+	assert.NilError(t, cc.Create(ctx, ns))
+	t.Cleanup(func() { assert.Check(t, cc.Delete(ctx, ns)) })
+
+	base := testCluster()
+	base.Namespace = ns.Name
+
+	t.Run("Valid", func(t *testing.T) {
+		cluster := base.DeepCopy()
+		cluster.Spec.Patroni = &v1beta1.PatroniSpec{
+			PermanentSlots: []v1beta1.PatroniPermanentSlot{
+				{Name: "physical"},
+				{Name: "logical", Type: "Logical", Database: "app"},
+			},
+		}
+		assert.NilError(t, cc.Create(ctx, cluster, client.DryRunAll))
+	})
+
+	t.Run("LogicalWithoutDatabase", func(t *testing.T) {
+		cluster := base.DeepCopy()
+		cluster.Spec.Patroni = &v1beta1.PatroniSpec{
+			PermanentSlots: []v1beta1.PatroniPermanentSlot{
+				{Name: "logical", Type: "Logical"},
+			},
+		}
+
+		err := cc.Create(ctx, cluster, client.DryRunAll)
+		assert.Assert(t, apierrors.IsInvalid(err), "expected Invalid, got\n%#v", err)
+		assert.ErrorContains(t, err, "spec.patroni.permanentSlots")
+	})
+}
+
+func TestReconcilePatroniDynamicConfigurationPaused(t *testing.T) {
+	ctx := context.Background()
+
+	var configurations []string
+	r := &Reconciler{PodExec: func(
+		_, _, _ string, stdin io.Reader, _, _ io.Writer, command ...string,
+	) error {
+		b, err := ioutil.ReadAll(stdin)
+		configurations = append(configurations, string(b))
+		return err
+	}}
+
+	pod := &corev1.Pod{}
+	pod.Namespace, pod.Name = "ns1", "some-pod"
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:  naming.ContainerDatabase,
+		State: corev1.ContainerState{Running: new(corev1.ContainerStateRunning)},
+	}}
+	instances := &observedInstances{forCluster: []*Instance{{
+		Name: "some-instance", Pods: []*corev1.Pod{pod},
+	}}}
+
+	cluster := testCluster()
+	cluster.Default()
+	cluster.Generation = 2
+	cluster.Status.Patroni.SystemIdentifier = "1234"
+	cluster.Spec.Patroni.Paused = true
+
+	assert.NilError(t, r.reconcilePatroniDynamicConfiguration(
+		ctx, cluster, instances, postgres.HBAs{}, postgres.Parameters{}))
+	assert.Equal(t, len(configurations), 1)
+	assert.Assert(t, strings.Contains(configurations[0], `"pause":true`), configurations[0])
+
+	condition := meta.FindStatusCondition(cluster.Status.Conditions, v1beta1.PatroniPaused)
+	assert.Assert(t, condition != nil)
+	assert.Equal(t, condition.Status, metav1.ConditionTrue)
+	assert.Equal(t, condition.ObservedGeneration, int64(2))
+
+	// Restarts wait until Patroni resumes.
+	pod.Annotations = map[string]string{"status": `{"pending_restart":true}`}
+	assert.NilError(t, r.handlePatroniRestarts(ctx, cluster, instances))
+	assert.Equal(t, len(configurations), 1)
+
+	cluster.Spec.Patroni.Paused = false
+	assert.NilError(t, r.reconcilePatroniDynamicConfiguration(
+		ctx, cluster, instances, postgres.HBAs{}, postgres.Parameters{}))
+	assert.Equal(t, len(configurations), 2)
+	assert.Assert(t, !strings.Contains(configurations[1], `pause`), configurations[1])
+
+	condition = meta.FindStatusCondition(cluster.Status.Conditions, v1beta1.PatroniPaused)
+	assert.Assert(t, condition != nil)
+	assert.Equal(t, condition.Status, metav1.ConditionFalse)
+}
+
+func TestReconcilePatroniPrimary(t *testing.T) {
+	ctx := context.Background()
+
+	member := func(name, role, callback string) *Instance {
+		pod := &corev1.Pod{}
+		pod.Namespace, pod.Name = "ns1", name+"-0"
+		pod.Annotations = map[string]string{
+			"status": `{"role":"` + role + `","timeline":3}`,
+		}
+		if callback != "" {
+			pod.Annotations[naming.PatroniCallback] = callback
+		}
+		return &Instance{Name: name, Pods: []*corev1.Pod{pod}}
+	}
+
+	t.Run("NoPrimary", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		r := &Reconciler{Recorder: recorder}
+
+		cluster := testCluster()
+		cluster.Status.Patroni.Primary = "one-0"
+		observed := &observedInstances{forCluster: []*Instance{
+			member("one", "replica", ""), member("two", "replica", ""),
+		}}
+
+		assert.NilError(t, r.reconcilePatroniPrimary(ctx, cluster, observed))
+		assert.Equal(t, cluster.Status.Patroni.Primary, "one-0")
+		assert.Equal(t, len(recorder.Events), 0)
+	})
+
+	t.Run("Initial", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		r := &Reconciler{Recorder: recorder}
+
+		cluster := testCluster()
+		observed := &observedInstances{forCluster: []*Instance{
+			member("one", "master", ""), member("two", "replica", ""),
+		}}
+
+		assert.NilError(t, r.reconcilePatroniPrimary(ctx, cluster, observed))
+		assert.Equal(t, cluster.Status.Patroni.Primary, "one-0")
+		assert.Assert(t, cluster.Status.Patroni.LastFailoverTime == nil)
+		assert.Assert(t, cluster.Status.Patroni.LastSwitchoverTime == nil)
+		assert.Equal(t, len(recorder.Events), 0)
+	})
+
+	t.Run("Switchover", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		r := &Reconciler{Recorder: recorder}
+
+		cluster := testCluster()
+		cluster.Status.Patroni.Primary = "one-0"
+		observed := &observedInstances{forCluster: []*Instance{
+			member("one", "replica",
+				`{"action":"on_role_change","role":"replica","time":"2021-10-31T12:34:50Z"}`),
+			member("two", "master",
+				`{"action":"on_role_change","role":"master","time":"2021-10-31T12:34:56Z"}`),
+		}}
+
+		assert.NilError(t, r.reconcilePatroniPrimary(ctx, cluster, observed))
+		assert.Equal(t, cluster.Status.Patroni.Primary, "two-0")
+		assert.Assert(t, cluster.Status.Patroni.LastFailoverTime == nil)
+		assert.Assert(t, cluster.Status.Patroni.LastSwitchoverTime != nil)
+		assert.Equal(t, cluster.Status.Patroni.LastSwitchoverTime.UTC(),
+			time.Date(2021, 10, 31, 12, 34, 56, 0, time.UTC))
+
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Equal(t, <-recorder.Events,
+			"Normal Switchover Primary changed from one-0 to two-0 on timeline 3")
+	})
+
+	t.Run("UnknownTimeline", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		r := &Reconciler{Recorder: recorder}
+
+		// Patroni does not report the timeline on pods when it uses etcd.
+		cluster := testCluster()
+		cluster.Status.Patroni.Primary = "one-0"
+		observed := &observedInstances{forCluster: []*Instance{
+			member("two", "replica",
+				`{"action":"on_role_change","role":"master","time":"2021-10-31T12:34:56Z"}`),
+		}}
+		observed.forCluster[0].Pods[0].Annotations["status"] = ""
+		observed.forCluster[0].Pods[0].Labels = map[string]string{
+			naming.LabelRole: naming.RolePatroniLeader,
+		}
+
+		assert.NilError(t, r.reconcilePatroniPrimary(ctx, cluster, observed))
+		assert.Equal(t, cluster.Status.Patroni.Primary, "two-0")
+
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Equal(t, <-recorder.Events,
+			"Warning Failover Primary changed from one-0 to two-0")
+	})
+
+	t.Run("Failover", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		r := &Reconciler{Recorder: recorder}
+		r.Client = fake.NewClientBuilder().Build()
+
+		job := &batchv1.Job{}
+		job.Namespace, job.Name = "ns1", "replica-create"
+		job.Labels = map[string]string{
+			naming.LabelCluster:          "hippo",
+			naming.LabelPGBackRestBackup: string(naming.BackupReplicaCreate),
+		}
+		assert.NilError(t, r.Client.Create(ctx, job))
+
+		cluster := testCluster()
+		cluster.Namespace, cluster.Name = "ns1", "hippo"
+		cluster.Spec.Patroni = &v1beta1.PatroniSpec{BackupAfterFailover: true}
+		cluster.Status.Patroni.Primary = "one-0"
+		cluster.Status.PGBackRest = &v1beta1.PGBackRestStatus{
+			Repos: []v1beta1.RepoStatus{{Name: "repo1", ReplicaCreateBackupComplete: true}},
+		}
+		observed := &observedInstances{forCluster: []*Instance{
+			member("two", "master", ""),
+		}}
+
+		assert.NilError(t, r.reconcilePatroniPrimary(ctx, cluster, observed))
+		assert.Equal(t, cluster.Status.Patroni.Primary, "two-0")
+		assert.Assert(t, cluster.Status.Patroni.LastFailoverTime != nil)
+		assert.Assert(t, cluster.Status.Patroni.LastSwitchoverTime == nil)
+
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Equal(t, <-recorder.Events,
+			"Warning Failover Primary changed from one-0 to two-0 on timeline 3")
+
+		// A new replica create backup is requested.
+		assert.Assert(t, !cluster.Status.PGBackRest.Repos[0].ReplicaCreateBackupComplete)
+		err := r.Client.Get(ctx, client.ObjectKeyFromObject(job), job)
+		assert.Assert(t, apierrors.IsNotFound(err), "got %#v", err)
+	})
+}
+
+func TestObservePatroniDCS(t *testing.T) {
+	ctx := context.Background()
+	r := &Reconciler{Client: fake.NewClientBuilder().Build()}
+
+	cluster := testCluster()
+	cluster.Namespace = "ns1"
+
+	for _, object := range []client.Object{
+		&corev1.ConfigMap{ObjectMeta: naming.PatroniLeaderConfigMap(cluster)},
+		&corev1.ConfigMap{ObjectMeta: naming.PatroniDistributedConfiguration(cluster)},
+		&corev1.Endpoints{ObjectMeta: naming.PatroniLeaderEndpoints(cluster)},
+	} {
+		assert.NilError(t, r.Client.Create(ctx, object))
+	}
+
+	for _, tt := range []struct {
+		dcs   string
+		names []string
+	}{
+		{dcs: "ConfigMaps", names: []string{"hippo-ha-leader", "hippo-ha-config"}},
+		{dcs: "Endpoints", names: []string{"hippo-ha"}},
+		{dcs: "Etcd", names: nil},
+	} {
+		cluster.Status.Patroni.DCS = tt.dcs
+		existing, err := r.observePatroniDCS(ctx, cluster)
+		assert.NilError(t, err)
+
+		var names []string
+		for _, object := range existing {
+			names = append(names, object.GetName())
+		}
+		assert.DeepEqual(t, names, tt.names)
+	}
+}
+
+func TestReconcilePatroniDCS(t *testing.T) {
+	ctx := context.Background()
+	env, cc, _ := setupTestEnv(t, ControllerName)
+	t.Cleanup(func() { teardownTestEnv(t, env) })
+
+	ns := &corev1.Namespace{}
+	ns.GenerateName = "postgres-operator-test-"
+	assert.NilError(t, cc.Create(ctx, ns))
+	t.Cleanup(func() { assert.Check(t, cc.Delete(ctx, ns)) })
+
+	newCluster := func(dcs string) *v1beta1.PostgresCluster {
+		cluster := testCluster()
+		cluster.Namespace = ns.Name
+		cluster.Spec.Patroni = &v1beta1.PatroniSpec{
+			DCS: &v1beta1.PatroniDCS{Type: dcs},
+		}
+		return cluster
+	}
+
+	t.Run("New", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		r := &Reconciler{Client: cc, Recorder: recorder}
+
+		cluster := newCluster("ConfigMaps")
+		assert.NilError(t, r.reconcilePatroniDCS(ctx, cluster, &observedInstances{}))
+		assert.Equal(t, cluster.Status.Patroni.DCS, "ConfigMaps")
+		assert.Equal(t, len(recorder.Events), 0)
+	})
+
+	t.Run("Bootstrapped", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		r := &Reconciler{Client: cc, Recorder: recorder}
+
+		// Clusters bootstrapped before the store was recorded use Endpoints.
+		cluster := newCluster("ConfigMaps")
+		cluster.Status.Patroni.SystemIdentifier = "6952526174828511264"
+
+		assert.NilError(t, r.reconcilePatroniDCS(ctx, cluster, &observedInstances{}))
+		assert.Equal(t, cluster.Status.Patroni.DCS, "Endpoints")
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Assert(t, strings.Contains(<-recorder.Events, "DCSChangePending"))
+	})
+
+	t.Run("Running", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		r := &Reconciler{Client: cc, Recorder: recorder}
+
+		cluster := newCluster("Etcd")
+		cluster.Spec.Shutdown = initialize.Bool(true)
+		cluster.Status.Patroni.DCS = "Endpoints"
+
+		// Pods are still stopping.
+		observed := &observedInstances{forCluster: []*Instance{{
+			Name: "one", Pods: []*corev1.Pod{{}},
+		}}}
+
+		assert.NilError(t, r.reconcilePatroniDCS(ctx, cluster, observed))
+		assert.Equal(t, cluster.Status.Patroni.DCS, "Endpoints")
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Assert(t, strings.Contains(<-recorder.Events, "DCSChangePending"))
+	})
+
+	t.Run("Shutdown", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		r := &Reconciler{Client: cc, Recorder: recorder}
+
+		cluster := newCluster("ConfigMaps")
+		cluster.Spec.Shutdown = initialize.Bool(true)
+		cluster.Status.Patroni.DCS = "Endpoints"
+
+		config := &corev1.Endpoints{ObjectMeta: naming.PatroniDistributedConfiguration(cluster)}
+		config.Annotations = map[string]string{"initialize": "6952526174828511264"}
+		assert.NilError(t, cc.Create(ctx, config))
+
+		observed := &observedInstances{forCluster: []*Instance{{Name: "one"}}}
+		assert.NilError(t, r.reconcilePatroniDCS(ctx, cluster, observed))
+		assert.Equal(t, cluster.Status.Patroni.DCS, "ConfigMaps")
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Assert(t, strings.Contains(<-recorder.Events, "DCSChanged"))
+
+		err := cc.Get(ctx, client.ObjectKeyFromObject(config), config)
+		assert.Assert(t, apierrors.IsNotFound(err), "expected NotFound, got %v", err)
+	})
 }
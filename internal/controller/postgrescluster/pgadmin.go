@@ -52,6 +52,9 @@ func (r *Reconciler) reconcilePGAdmin(
 	if err == nil {
 		err = r.reconcilePGAdminStatefulSet(ctx, cluster, dataVolume)
 	}
+	if err == nil {
+		err = r.reconcilePGAdminPodDisruptionBudget(ctx, cluster)
+	}
 	return err
 }
 
@@ -248,6 +251,30 @@ func (r *Reconciler) reconcilePGAdminStatefulSet(
 	return err
 }
 
+// reconcilePGAdminPodDisruptionBudget writes the PodDisruptionBudget that
+// limits voluntary evictions of the pgAdmin Pod. There is at most one pgAdmin
+// Pod, so the PodDisruptionBudget exists only when its minimum is specified.
+func (r *Reconciler) reconcilePGAdminPodDisruptionBudget(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+) error {
+	var metadata *v1beta1.Metadata
+	var minAvailable *intstr.IntOrString
+	var replicas int32
+
+	// pgAdmin may be disabled; then its PodDisruptionBudget is deleted.
+	if cluster.Spec.UserInterface != nil && cluster.Spec.UserInterface.PGAdmin != nil {
+		metadata = cluster.Spec.UserInterface.PGAdmin.Metadata
+		minAvailable = cluster.Spec.UserInterface.PGAdmin.MinAvailable
+		replicas = *cluster.Spec.UserInterface.PGAdmin.Replicas
+	}
+
+	return r.reconcileComponentPodDisruptionBudget(ctx, cluster,
+		naming.ClusterPGAdmin(cluster), map[string]string{
+			naming.LabelCluster: cluster.Name,
+			naming.LabelRole:    naming.RolePGAdmin,
+		}, metadata, replicas, minAvailable)
+}
+
 // +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=create;patch
 
 // reconcilePGAdminDataVolume writes the PersistentVolumeClaim for instance's
@@ -27,6 +27,7 @@ import (
 	"gotest.tools/v3/assert"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -784,3 +785,62 @@ func pgAdminTestCluster(ns corev1.Namespace) *v1beta1.PostgresCluster {
 		},
 	}
 }
+
+func TestReconcilePGAdminPodDisruptionBudget(t *testing.T) {
+	ctx := context.Background()
+	env, cc, _ := setupTestEnv(t, ControllerName)
+	t.Cleanup(func() { teardownTestEnv(t, env) })
+
+	ns := &corev1.Namespace{}
+	ns.GenerateName = "postgres-operator-test-"
+	assert.NilError(t, cc.Create(ctx, ns))
+	t.Cleanup(func() { assert.Check(t, cc.Delete(ctx, ns)) })
+
+	r := &Reconciler{Client: cc, Owner: client.FieldOwner(t.Name())}
+
+	cluster := fakePostgresCluster("hippo", ns.Name, "", false)
+	assert.NilError(t, cc.Create(ctx, cluster))
+	t.Cleanup(func() { assert.Check(t, client.IgnoreNotFound(cc.Delete(ctx, cluster))) })
+
+	exists := func(t *testing.T) (*policyv1beta1.PodDisruptionBudget, bool) {
+		pdb := &policyv1beta1.PodDisruptionBudget{ObjectMeta: naming.ClusterPGAdmin(cluster)}
+		err := cc.Get(ctx, client.ObjectKeyFromObject(pdb), pdb)
+		if apierrors.IsNotFound(err) {
+			return nil, false
+		}
+		assert.NilError(t, err)
+		return pdb, true
+	}
+
+	t.Run("Unspecified", func(t *testing.T) {
+		cluster.Spec.UserInterface = &v1beta1.UserInterfaceSpec{
+			PGAdmin: &v1beta1.PGAdminPodSpec{Replicas: initialize.Int32(1)},
+		}
+		assert.NilError(t, r.reconcilePGAdminPodDisruptionBudget(ctx, cluster))
+
+		_, found := exists(t)
+		assert.Assert(t, !found, "expected no PodDisruptionBudget")
+	})
+
+	t.Run("Specified", func(t *testing.T) {
+		cluster.Spec.UserInterface.PGAdmin.MinAvailable = initialize.IntOrStringInt32(1)
+		assert.NilError(t, r.reconcilePGAdminPodDisruptionBudget(ctx, cluster))
+
+		pdb, found := exists(t)
+		assert.Assert(t, found, "expected a PodDisruptionBudget")
+		assert.DeepEqual(t, pdb.Spec.MinAvailable, initialize.IntOrStringInt32(1))
+		assert.DeepEqual(t, pdb.Spec.Selector.MatchLabels, map[string]string{
+			naming.LabelCluster: "hippo",
+			naming.LabelRole:    naming.RolePGAdmin,
+		})
+		assert.Assert(t, metav1.IsControlledBy(pdb, cluster))
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		cluster.Spec.UserInterface = nil
+		assert.NilError(t, r.reconcilePGAdminPodDisruptionBudget(ctx, cluster))
+
+		_, found := exists(t)
+		assert.Assert(t, !found, "expected no PodDisruptionBudget")
+	})
+}
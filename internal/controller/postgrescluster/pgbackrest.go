@@ -38,6 +38,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -73,6 +74,14 @@ const (
 	// pgBackRest repository host PostgresCluster is ready
 	ConditionRepoHostReady = "PGBackRestRepoHostReady"
 
+	// ConditionBackupStandbyAvailable is the type used in a condition to indicate whether or not
+	// a replica is available to take backups from
+	ConditionBackupStandbyAvailable = "PGBackRestBackupStandbyAvailable"
+
+	// ConditionArchivingHealthy is the type used in a condition to indicate whether or not the
+	// primary is archiving WAL files to the pgBackRest repositories
+	ConditionArchivingHealthy = "ArchivingHealthy"
+
 	// ConditionPGBackRestRestoreProgressing is the type used in a condition to indicate that
 	// and in-place pgBackRest restore is in progress
 	ConditionPGBackRestRestoreProgressing = "PGBackRestoreProgressing"
@@ -97,6 +106,14 @@ const (
 	// CronJob fails to create successfully
 	EventUnableToCreatePGBackRestCronJob = "UnableToCreatePGBackRestCronJob"
 
+	// EventArchivingFailed is the event reason utilized when the primary starts failing to
+	// archive WAL files
+	EventArchivingFailed = "ArchivingFailed"
+
+	// EventArchivingRecovered is the event reason utilized when the primary archives WAL files
+	// again after failing
+	EventArchivingRecovered = "ArchivingRecovered"
+
 	// ReasonReadyForRestore is the reason utilized within ConditionPGBackRestRestoreProgressing
 	// to indicate that the restore Job can proceed because the cluster is now ready to be
 	// restored (i.e. it has been properly prepared for a restore).
@@ -123,6 +140,9 @@ type RepoResources struct {
 	pvcs                    []*corev1.PersistentVolumeClaim
 	sshConfig               *corev1.ConfigMap
 	sshSecret               *corev1.Secret
+	cipherSecret            *corev1.Secret
+	verificationJobs        []*batchv1.Job
+	verificationVolumes     []*corev1.PersistentVolumeClaim
 }
 
 // applyRepoHostIntent ensures the pgBackRest repository host StatefulSet is synchronized with the
@@ -309,6 +329,16 @@ func (r *Reconciler) cleanupRepoResources(ctx context.Context,
 					break
 				}
 			}
+		case hasLabel(naming.LabelPGBackRestVerify):
+			// Keep the CronJob, Jobs and volume that verify the backups of a repo only
+			// while that repo is defined with a verification schedule.
+			for _, repo := range postgresCluster.Spec.Backups.PGBackRest.Repos {
+				if repo.Name == owned.GetLabels()[naming.LabelPGBackRestVerify] &&
+					repo.Verification != nil {
+					ownedNoDelete = append(ownedNoDelete, owned)
+					delete = false
+				}
+			}
 		case hasLabel(naming.LabelPGBackRestRestore):
 			// When a cluster is prepared for restore, the system identifier is removed from status
 			// and the cluster is therefore no longer bootstrapped.  Only once the restore Job is
@@ -376,8 +406,14 @@ func unstructuredToRepoResources(postgresCluster *v1beta1.PostgresCluster, kind
 			FromUnstructured(uList.UnstructuredContent(), &jobList); err != nil {
 			return errors.WithStack(err)
 		}
-		// we care about replica create backup jobs and manual backup jobs
+		// we care about replica create backup jobs, manual backup jobs and the jobs that
+		// verify backups
 		for i, job := range jobList.Items {
+			if _, ok := job.GetLabels()[naming.LabelPGBackRestVerify]; ok {
+				repoResources.verificationJobs =
+					append(repoResources.verificationJobs, &jobList.Items[i])
+				continue
+			}
 			switch job.GetLabels()[naming.LabelPGBackRestBackup] {
 			case string(naming.BackupReplicaCreate):
 				repoResources.replicaCreateBackupJobs =
@@ -393,8 +429,13 @@ func unstructuredToRepoResources(postgresCluster *v1beta1.PostgresCluster, kind
 			FromUnstructured(uList.UnstructuredContent(), &pvcList); err != nil {
 			return errors.WithStack(err)
 		}
-		for i := range pvcList.Items {
-			repoResources.pvcs = append(repoResources.pvcs, &pvcList.Items[i])
+		for i, pvc := range pvcList.Items {
+			if _, ok := pvc.GetLabels()[naming.LabelPGBackRestVerify]; ok {
+				repoResources.verificationVolumes =
+					append(repoResources.verificationVolumes, &pvcList.Items[i])
+			} else {
+				repoResources.pvcs = append(repoResources.pvcs, &pvcList.Items[i])
+			}
 		}
 	case "SecretList":
 		var secretList corev1.SecretList
@@ -404,9 +445,11 @@ func unstructuredToRepoResources(postgresCluster *v1beta1.PostgresCluster, kind
 		}
 		// we only care about Secret with the proper names
 		for i, secret := range secretList.Items {
-			if secret.GetName() == naming.PGBackRestSSHSecret(postgresCluster).Name {
+			switch secret.GetName() {
+			case naming.PGBackRestSSHSecret(postgresCluster).Name:
 				repoResources.sshSecret = &secretList.Items[i]
-				break
+			case naming.PGBackRestCipherSecret(postgresCluster).Name:
+				repoResources.cipherSecret = &secretList.Items[i]
 			}
 		}
 	case "StatefulSetList":
@@ -582,6 +625,10 @@ func (r *Reconciler) generateRepoHostIntent(postgresCluster *v1beta1.PostgresClu
 		naming.PGBackRestRepoContainerName); err != nil {
 		return nil, errors.WithStack(err)
 	}
+	if err := pgbackrest.AddSharedRepoVolumesToPod(postgresCluster, &repo.Spec.Template,
+		naming.PGBackRestRepoContainerName); err != nil {
+		return nil, errors.WithStack(err)
+	}
 	// add configs to pod
 	if err := pgbackrest.AddConfigsToPod(postgresCluster, &repo.Spec.Template,
 		pgbackrest.CMRepoKey, naming.PGBackRestRepoContainerName); err != nil {
@@ -643,6 +690,13 @@ func (r *Reconciler) generateRepoVolumeIntent(postgresCluster *v1beta1.PostgresC
 		Spec:       *spec,
 	}
 
+	// keep any storage request that has grown automatically
+	for _, pvc := range repoResources.pvcs {
+		if pvc.GetName() == repoVol.GetName() {
+			keepVolumeRequest(repoVol, pvc)
+		}
+	}
+
 	// set ownership references
 	if err := controllerutil.SetControllerReference(postgresCluster, repoVol,
 		r.Client.Scheme()); err != nil {
@@ -662,6 +716,24 @@ func generateBackupJobSpecIntent(postgresCluster *v1beta1.PostgresCluster, selec
 		"--stanza=" + pgbackrest.DefaultStanzaName,
 		"--repo=" + repoIndex,
 	}
+
+	// Add options for the repository unless they are already among opts.
+	// pgBackRest does not allow an option more than once.
+	for _, repo := range postgresCluster.Spec.Backups.PGBackRest.Repos {
+		if repo.Name != repoName {
+			continue
+		}
+		for _, option := range pgbackrest.BackupCommandOptions(repo) {
+			name := strings.SplitN(option, "=", 2)[0]
+			found := false
+			for _, opt := range opts {
+				found = found || opt == name || strings.HasPrefix(opt, name+"=")
+			}
+			if !found {
+				cmdOpts = append(cmdOpts, option)
+			}
+		}
+	}
 	cmdOpts = append(cmdOpts, opts...)
 
 	container := corev1.Container{
@@ -699,11 +771,19 @@ func generateBackupJobSpecIntent(postgresCluster *v1beta1.PostgresCluster, selec
 		},
 	}
 
-	// set the priority class name, if it exists
-	if postgresCluster.Spec.Backups.PGBackRest.Jobs != nil &&
-		postgresCluster.Spec.Backups.PGBackRest.Jobs.PriorityClassName != nil {
-		jobSpec.Template.Spec.PriorityClassName =
-			*postgresCluster.Spec.Backups.PGBackRest.Jobs.PriorityClassName
+	// set the priority class name, scheduling constraints, and limits, if they exist
+	if jobs := postgresCluster.Spec.Backups.PGBackRest.Jobs; jobs != nil {
+		if jobs.PriorityClassName != nil {
+			jobSpec.Template.Spec.PriorityClassName = *jobs.PriorityClassName
+		}
+		jobSpec.Template.Spec.Affinity = jobs.Affinity
+		jobSpec.Template.Spec.NodeSelector = jobs.NodeSelector
+		jobSpec.Template.Spec.Tolerations = jobs.Tolerations
+		jobSpec.Template.Spec.TopologySpreadConstraints = jobs.TopologySpreadConstraints
+
+		jobSpec.ActiveDeadlineSeconds = jobs.ActiveDeadlineSeconds
+		jobSpec.BackoffLimit = jobs.BackoffLimit
+		jobSpec.TTLSecondsAfterFinished = jobs.TTLSecondsAfterFinished
 	}
 
 	// Set the image pull secrets, if any exist.
@@ -732,34 +812,14 @@ func generateBackupJobSpecIntent(postgresCluster *v1beta1.PostgresCluster, selec
 // created by Patroni (i.e. DCS, leader and failover Endpoints), while then also finding any existing
 // restore Jobs and then updating pgBackRest restore status accordingly.
 func (r *Reconciler) observeRestoreEnv(ctx context.Context,
-	cluster *v1beta1.PostgresCluster) ([]corev1.Endpoints, *batchv1.Job, error) {
+	cluster *v1beta1.PostgresCluster) ([]client.Object, *batchv1.Job, error) {
 
-	// lookup the various patroni endpoints
-	leaderEP, dcsEP, failoverEP := corev1.Endpoints{}, corev1.Endpoints{}, corev1.Endpoints{}
-	currentEndpoints := []corev1.Endpoints{}
-	if err := r.Client.Get(ctx, naming.AsObjectKey(naming.PatroniLeaderEndpoints(cluster)),
-		&leaderEP); err != nil {
-		if !apierrors.IsNotFound(err) {
-			return nil, nil, errors.WithStack(err)
-		}
-	} else {
-		currentEndpoints = append(currentEndpoints, leaderEP)
-	}
-	if err := r.Client.Get(ctx, naming.AsObjectKey(naming.PatroniDistributedConfiguration(cluster)),
-		&dcsEP); err != nil {
-		if !apierrors.IsNotFound(err) {
-			return nil, nil, errors.WithStack(err)
-		}
-	} else {
-		currentEndpoints = append(currentEndpoints, dcsEP)
-	}
-	if err := r.Client.Get(ctx, naming.AsObjectKey(naming.PatroniTrigger(cluster)),
-		&failoverEP); err != nil {
-		if !apierrors.IsNotFound(err) {
-			return nil, nil, errors.WithStack(err)
-		}
-	} else {
-		currentEndpoints = append(currentEndpoints, failoverEP)
+	// lookup the objects in which Patroni stores its state
+	// NOTE: In-place restores are rejected when Patroni keeps its state outside of
+	// Kubernetes; see reconcileDataSource.
+	currentDCS, err := r.observePatroniDCS(ctx, cluster)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	restoreJobs := &batchv1.JobList{}
@@ -844,7 +904,7 @@ func (r *Reconciler) observeRestoreEnv(ctx context.Context,
 		}
 	}
 
-	return currentEndpoints, restoreJob, nil
+	return currentDCS, restoreJob, nil
 }
 
 // +kubebuilder:rbac:groups="",resources=endpoints,verbs=delete
@@ -853,12 +913,12 @@ func (r *Reconciler) observeRestoreEnv(ctx context.Context,
 
 // prepareForRestore is responsible for reconciling an in place restore for the PostgresCluster.
 // This includes setting a "PreparingForRestore" condition, and then removing all existing
-// instance runners, as well as any Endpoints created by Patroni.  And once the cluster is no
+// instance runners, as well as any Endpoints or ConfigMaps created by Patroni.  And once the cluster is no
 // longer running, the "PostgresDataInitialized" condition is removed, which will cause the
 // cluster to re-bootstrap using a restored data directory.
 func (r *Reconciler) prepareForRestore(ctx context.Context,
 	cluster *v1beta1.PostgresCluster, observed *observedInstances,
-	currentEndpoints []corev1.Endpoints, restoreJob *batchv1.Job, restoreID string) error {
+	currentDCS []client.Object, restoreJob *batchv1.Job, restoreID string) error {
 
 	setPreparingClusterCondition := func(resource string) {
 		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
@@ -941,7 +1001,7 @@ func (r *Reconciler) prepareForRestore(ctx context.Context,
 	}
 
 	// if everything is gone, proceed with re-bootstrapping the cluster via an in-place restore
-	if len(currentEndpoints) == 0 {
+	if len(currentDCS) == 0 {
 		if len(cluster.Status.Conditions) > 0 {
 			// TODO: remove guard with move to controller-runtime 0.9.0 https://issue.k8s.io/99714
 			meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionPostgresDataInitialized)
@@ -963,9 +1023,9 @@ func (r *Reconciler) prepareForRestore(ctx context.Context,
 	}
 
 	setPreparingClusterCondition("removing DCS")
-	// delete any Endpoints
-	for i := range currentEndpoints {
-		if err := r.Client.Delete(ctx, &currentEndpoints[i]); client.IgnoreNotFound(err) != nil {
+	// delete any Endpoints or ConfigMaps
+	for i := range currentDCS {
+		if err := r.Client.Delete(ctx, currentDCS[i]); client.IgnoreNotFound(err) != nil {
 			return errors.WithStack(err)
 		}
 	}
@@ -980,6 +1040,7 @@ func (r *Reconciler) prepareForRestore(ctx context.Context,
 func (r *Reconciler) reconcileRestoreJob(ctx context.Context,
 	cluster, sourceCluster *v1beta1.PostgresCluster,
 	pgdataVolume, pgwalVolume *corev1.PersistentVolumeClaim,
+	tablespaceVolumes map[string]*corev1.PersistentVolumeClaim,
 	dataSource *v1beta1.PostgresClusterDataSource,
 	instanceName, instanceSetName, configHash string) error {
 
@@ -1092,31 +1153,58 @@ func (r *Reconciler) reconcileRestoreJob(ctx context.Context,
 		return errors.WithStack(err)
 	}
 
+	// mount tablespace volumes so pgBackRest can restore their contents
+	postgres.AddTablespaceVolumes(tablespaceVolumes, &restoreJob.Spec.Template.Spec)
+
+	if err := addPGBackRestToRestoreJob(cluster, sourceCluster, restoreJob,
+		dataSource.Resources); err != nil {
+		return err
+	}
+
+	return errors.WithStack(r.apply(ctx, restoreJob))
+}
+
+// addPGBackRestToRestoreJob adds everything pgBackRest needs to restore from the repositories of
+// sourceCluster to the restore container of job, e.g. SSH and pgBackRest configuration.
+func addPGBackRestToRestoreJob(cluster, sourceCluster *v1beta1.PostgresCluster,
+	job *batchv1.Job, resources corev1.ResourceRequirements) error {
+
 	if pgbackrest.DedicatedRepoHostEnabled(sourceCluster) {
 		// add ssh configs to template
-		if err := pgbackrest.AddSSHToPod(sourceCluster, &restoreJob.Spec.Template, false,
-			dataSource.Resources,
+		if err := pgbackrest.AddSSHToPod(sourceCluster, &job.Spec.Template, false,
+			resources,
 			naming.PGBackRestRestoreContainerName); err != nil {
 			return errors.WithStack(err)
 		}
 	}
 
 	// add pgBackRest configs to template
-	if err := pgbackrest.AddConfigsToPod(sourceCluster, &restoreJob.Spec.Template,
+	if err := pgbackrest.AddConfigsToPod(sourceCluster, &job.Spec.Template,
 		pgbackrest.CMInstanceKey, naming.PGBackRestRestoreContainerName); err != nil {
 		return errors.WithStack(err)
 	}
+	if err := pgbackrest.AddSharedRepoVolumesToPod(sourceCluster, &job.Spec.Template,
+		naming.PGBackRestRestoreContainerName); err != nil {
+		return errors.WithStack(err)
+	}
+
+	// PostgreSQL fetches WAL asynchronously during recovery when sourceCluster archives
+	// asynchronously, so give it a spool.
+	if err := pgbackrest.AddSpoolVolumeToPod(sourceCluster, &job.Spec.Template, nil,
+		naming.PGBackRestRestoreContainerName); err != nil {
+		return errors.WithStack(err)
+	}
 
 	// add nss_wrapper init container and add nss_wrapper env vars to the pgbackrest restore
 	// container
 	addNSSWrapper(
 		config.PGBackRestContainerImage(cluster),
 		cluster.Spec.ImagePullPolicy,
-		&restoreJob.Spec.Template)
+		&job.Spec.Template)
 
-	addTMPEmptyDir(&restoreJob.Spec.Template)
+	addTMPEmptyDir(&job.Spec.Template)
 
-	return errors.WithStack(r.apply(ctx, restoreJob))
+	return nil
 }
 
 func (r *Reconciler) generateRestoreJobIntent(cluster *v1beta1.PostgresCluster,
@@ -1241,6 +1329,11 @@ func (r *Reconciler) reconcilePGBackRest(ctx context.Context,
 		meta.RemoveStatusCondition(&postgresCluster.Status.Conditions, ConditionRepoHostReady)
 	}
 
+	if err := r.reconcileRepoHostPodDisruptionBudget(ctx, postgresCluster); err != nil {
+		log.Error(err, "unable to reconcile pgBackRest repo host PodDisruptionBudget")
+		result = updateReconcileResult(result, reconcile.Result{Requeue: true})
+	}
+
 	// calculate hashes for the external repository configurations in the spec (e.g. for Azure,
 	// GCS and/or S3 repositories) as needed to properly detect changes to external repository
 	// configuration (and then execute stanza create commands accordingly)
@@ -1259,6 +1352,15 @@ func (r *Reconciler) reconcilePGBackRest(ctx context.Context,
 		return result, nil
 	}
 
+	// reconcile the passphrases of encrypted repos before the configuration that
+	// expects them
+	if err := r.reconcilePGBackRestCipher(ctx, postgresCluster,
+		repoResources.cipherSecret); err != nil {
+		log.Error(err, "unable to reconcile pgBackRest encryption")
+		result = updateReconcileResult(result, reconcile.Result{Requeue: true})
+		return result, nil
+	}
+
 	// gather instance names and reconcile all pgbackrest configuration and secrets
 	instanceNames := []string{}
 	for _, instance := range instances.forCluster {
@@ -1266,6 +1368,10 @@ func (r *Reconciler) reconcilePGBackRest(ctx context.Context,
 	}
 	// sort to ensure consistent ordering of hosts when creating pgBackRest configs
 	sort.Strings(instanceNames)
+
+	// pgBackRest chooses the replica that backups are taken from; report whether there is one.
+	r.observeBackupStandby(postgresCluster, instances)
+
 	if err := r.reconcilePGBackRestConfig(ctx, postgresCluster, nil, repoHostName,
 		configHash, naming.ClusterPodService(postgresCluster).Name,
 		postgresCluster.GetNamespace(), instanceNames, repoResources.sshSecret); err != nil {
@@ -1333,6 +1439,19 @@ func (r *Reconciler) reconcilePGBackRest(ctx context.Context,
 		result = updateReconcileResult(result, reconcile.Result{Requeue: true})
 	}
 
+	// Verify the backups of repos that have a verification schedule
+	verifyResult, err := r.reconcileRestoreVerification(ctx, postgresCluster,
+		repoResources.verificationJobs, repoResources.verificationVolumes, configHash)
+	if err != nil {
+		log.Error(err, "unable to reconcile backup verification")
+		verifyResult = reconcile.Result{Requeue: true}
+	}
+	result = updateReconcileResult(result, verifyResult)
+
+	// Read the backups in each repository so they can be reported in status.
+	result = updateReconcileResult(result,
+		r.reconcilePGBackRestInfo(ctx, postgresCluster, instances))
+
 	return result, nil
 }
 
@@ -1486,10 +1605,14 @@ func (r *Reconciler) reconcilePostgresClusterDataSource(ctx context.Context,
 	if err != nil {
 		return errors.WithStack(err)
 	}
+	tablespaces, err := r.reconcilePostgresTablespaceVolumes(ctx, cluster, instanceSet, fakeSTS)
+	if err != nil {
+		return errors.WithStack(err)
+	}
 
 	// reconcile the pgBackRest restore Job to populate the cluster's data directory
-	if err := r.reconcileRestoreJob(ctx, cluster, sourceCluster, pgdata, pgwal, dataSource,
-		instanceName, instanceSetName, configHash); err != nil {
+	if err := r.reconcileRestoreJob(ctx, cluster, sourceCluster, pgdata, pgwal, tablespaces,
+		dataSource, instanceName, instanceSetName, configHash); err != nil {
 		return errors.WithStack(err)
 	}
 
@@ -1557,6 +1680,56 @@ func (r *Reconciler) copyRestoreConfiguration(ctx context.Context,
 		Labels:          metadata.GetLabels(),
 		OwnerReferences: restoreSSHConfig.OwnerReferences,
 	}
+
+	// The restore needs the passphrases of the source cluster to read its encrypted repos.
+	if pgbackrest.CipherEnabled(origSourceCluster) {
+		sourceCipher := &corev1.Secret{}
+		if err := r.Client.Get(ctx,
+			naming.AsObjectKey(naming.PGBackRestCipherSecret(origSourceCluster)),
+			sourceCipher); err != nil {
+			return errors.WithStack(err)
+		}
+		restoreCipher := &corev1.Secret{
+			ObjectMeta: naming.PGBackRestCipherSecret(sourceCluster),
+			Data:       sourceCipher.Data,
+		}
+		restoreCipher.Annotations = overrideMetadata.Annotations
+		restoreCipher.Labels = overrideMetadata.Labels
+		restoreCipher.OwnerReferences = overrideMetadata.OwnerReferences
+		restoreCipher.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+		if err := r.apply(ctx, restoreCipher); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	// The restore needs the private keys of the source cluster to log into its
+	// SFTP repos. Copy each one and use the copy in the local namespace.
+	for i := range sourceCluster.Spec.Backups.PGBackRest.Repos {
+		repo := &sourceCluster.Spec.Backups.PGBackRest.Repos[i]
+		if repo.SFTP == nil {
+			continue
+		}
+		selector := &repo.SFTP.PrivateKeySecret
+		sourceKey := &corev1.Secret{}
+		if err := r.Client.Get(ctx, client.ObjectKey{
+			Namespace: origSourceCluster.GetNamespace(), Name: selector.Name,
+		}, sourceKey); err != nil {
+			return errors.WithStack(err)
+		}
+		restoreKey := &corev1.Secret{
+			ObjectMeta: naming.PGBackRestSFTPSecret(sourceCluster, repo.Name),
+			Data:       map[string][]byte{selector.Key: sourceKey.Data[selector.Key]},
+		}
+		restoreKey.Annotations = overrideMetadata.Annotations
+		restoreKey.Labels = overrideMetadata.Labels
+		restoreKey.OwnerReferences = overrideMetadata.OwnerReferences
+		restoreKey.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+		if err := r.apply(ctx, restoreKey); err != nil {
+			return errors.WithStack(err)
+		}
+		selector.Name = restoreKey.Name
+	}
+
 	if err := r.reconcilePGBackRestConfig(ctx, sourceCluster, overrideMetadata, repoHostName, "",
 		naming.ClusterPodService(origSourceCluster).Name, origSourceCluster.GetNamespace(),
 		[]string{sourceClusterInstance}, restoreSSHConfig); err != nil {
@@ -1647,6 +1820,98 @@ func (r *Reconciler) reconcilePGBackRestConfig(ctx context.Context,
 	return nil
 }
 
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=create;patch
+
+// reconcilePGBackRestCipher reconciles the Secret that holds the passphrase of
+// each encrypted pgBackRest repository. A passphrase is either generated or
+// copied from the Secret specified by the user. Once the stanza of a repository
+// is created its passphrase no longer changes, because pgBackRest would be
+// unable to read anything already in the repository.
+func (r *Reconciler) reconcilePGBackRestCipher(ctx context.Context,
+	postgresCluster *v1beta1.PostgresCluster, existing *corev1.Secret) error {
+
+	if existing == nil && !pgbackrest.CipherEnabled(postgresCluster) {
+		return nil
+	}
+
+	repoStatus := make(map[string]*v1beta1.RepoStatus)
+	for i := range postgresCluster.Status.PGBackRest.Repos {
+		repoStatus[postgresCluster.Status.PGBackRest.Repos[i].Name] =
+			&postgresCluster.Status.PGBackRest.Repos[i]
+	}
+
+	ciphers := make(map[string]string)
+	passphrases := make(map[string]string)
+	for _, repo := range postgresCluster.Spec.Backups.PGBackRest.Repos {
+		status := repoStatus[repo.Name]
+
+		var current, currentCipher string
+		if existing != nil {
+			current = string(existing.Data[repo.Name])
+		}
+		if status != nil {
+			currentCipher = status.Cipher
+		}
+
+		cipher, passphrase := pgbackrest.RepoCipher(repo), ""
+		switch {
+		case cipher == "":
+		case repo.Encryption.PassphraseSecret != nil:
+			selector := repo.Encryption.PassphraseSecret
+			source := &corev1.Secret{}
+			err := errors.WithStack(r.Client.Get(ctx, client.ObjectKey{
+				Namespace: postgresCluster.GetNamespace(), Name: selector.Name,
+			}, source))
+
+			if err == nil && len(source.Data[selector.Key]) == 0 {
+				err = errors.Errorf("Secret %q has no passphrase in key %q",
+					selector.Name, selector.Key)
+			}
+			if err != nil {
+				r.Recorder.Eventf(postgresCluster, corev1.EventTypeWarning,
+					"InvalidEncryption", "Unable to read the passphrase of %s: %v",
+					repo.Name, err)
+				return err
+			}
+			passphrase = string(source.Data[selector.Key])
+		case current != "":
+			passphrase = current
+		default:
+			generated, err := pgbackrest.GeneratePassphrase()
+			if err != nil {
+				return err
+			}
+			passphrase = generated
+		}
+
+		// Keep the passphrase of a repository that has a stanza. This also
+		// refuses to encrypt or decrypt it.
+		if status != nil && status.StanzaCreated &&
+			(passphrase != current || cipher != currentCipher) {
+			r.Recorder.Eventf(postgresCluster, corev1.EventTypeWarning,
+				"EncryptionChangeRefused", "Refusing to change the encryption of %s "+
+					"because its stanza is already created", repo.Name)
+
+			cipher, passphrase = currentCipher, current
+		}
+
+		if cipher != "" && passphrase != "" {
+			ciphers[repo.Name], passphrases[repo.Name] = cipher, passphrase
+		}
+		if status != nil {
+			status.Cipher = ciphers[repo.Name]
+		}
+	}
+
+	secret := pgbackrest.CreateCipherSecretIntent(postgresCluster, ciphers, passphrases)
+	err := errors.WithStack(r.setControllerReference(postgresCluster, secret))
+	if err == nil {
+		err = r.apply(ctx, secret)
+	}
+	return err
+}
+
 // +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=create;patch
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=create;patch
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=create;patch
@@ -1746,7 +2011,7 @@ func (r *Reconciler) reconcileDedicatedRepoHost(ctx context.Context,
 	}()
 	var isCreate bool
 	if len(repoResources.hosts) == 0 {
-		name := fmt.Sprintf("%s-%s", postgresCluster.GetName(), "repo-host")
+		name := naming.PGBackRestRepoHost(postgresCluster).Name
 		repoResources.hosts = append(repoResources.hosts, &appsv1.StatefulSet{
 			ObjectMeta: metav1.ObjectMeta{
 				Name: name,
@@ -1775,6 +2040,30 @@ func (r *Reconciler) reconcileDedicatedRepoHost(ctx context.Context,
 	return repoHost, nil
 }
 
+// reconcileRepoHostPodDisruptionBudget writes the PodDisruptionBudget that
+// limits voluntary evictions of the dedicated repository host Pod. There is at
+// most one repository host Pod, so the PodDisruptionBudget exists only when its
+// minimum is specified.
+func (r *Reconciler) reconcileRepoHostPodDisruptionBudget(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+) error {
+	var minAvailable *intstr.IntOrString
+	var replicas int32
+
+	// There may be no repository host or no minimum; then the
+	// PodDisruptionBudget is deleted.
+	repoHost := cluster.Spec.Backups.PGBackRest.RepoHost
+	if pgbackrest.DedicatedRepoHostEnabled(cluster) && repoHost != nil {
+		minAvailable = repoHost.MinAvailable
+		replicas = 1
+	}
+
+	return r.reconcileComponentPodDisruptionBudget(ctx, cluster,
+		naming.PGBackRestRepoHost(cluster),
+		naming.PGBackRestDedicatedLabels(cluster.Name),
+		cluster.Spec.Backups.PGBackRest.Metadata, replicas, minAvailable)
+}
+
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=create;patch;delete
 
 // reconcileManualBackup is responsible for reconciling pgBackRest backups that are initiated
@@ -2091,6 +2380,11 @@ func (r *Reconciler) reconcileReplicaCreateBackup(ctx context.Context,
 	if len(replicaCreateBackupJobs) > 0 {
 		job = replicaCreateBackupJobs[0]
 
+		// wait for a Job that is being deleted to be gone before creating another
+		if job.GetDeletionTimestamp() != nil {
+			return nil
+		}
+
 		failed := jobFailed(job)
 		completed := jobCompleted(job)
 
@@ -2155,8 +2449,15 @@ func (r *Reconciler) reconcileReplicaCreateBackup(ctx context.Context,
 	backupJob.ObjectMeta.Labels = labels
 	backupJob.ObjectMeta.Annotations = annotations
 
+	// The initial backup is always taken from the primary. There are no replicas yet.
+	var backupOpts []string
+	if pgbackrest.BackupFromStandbyEnabled(postgresCluster) {
+		backupOpts = append(backupOpts, "--no-backup-standby")
+	}
+
 	spec, err := generateBackupJobSpecIntent(postgresCluster, selector.String(), containerName,
-		replicaCreateRepoName, serviceAccount.GetName(), configName, labels, annotations)
+		replicaCreateRepoName, serviceAccount.GetName(), configName, labels, annotations,
+		backupOpts...)
 	if err != nil {
 		return errors.WithStack(err)
 	}
@@ -2176,6 +2477,41 @@ func (r *Reconciler) reconcileReplicaCreateBackup(ctx context.Context,
 	return nil
 }
 
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=list;delete
+
+// requestReplicaCreateBackup deletes the Job of the full backup used to create
+// replicas so that reconcileReplicaCreateBackup takes another one.
+func (r *Reconciler) requestReplicaCreateBackup(
+	ctx context.Context, postgresCluster *v1beta1.PostgresCluster,
+) error {
+	if postgresCluster.Status.PGBackRest == nil {
+		return nil
+	}
+
+	jobs := &batchv1.JobList{}
+	err := errors.WithStack(r.Client.List(ctx, jobs,
+		client.InNamespace(postgresCluster.Namespace),
+		client.MatchingLabels{
+			naming.LabelCluster:          postgresCluster.Name,
+			naming.LabelPGBackRestBackup: string(naming.BackupReplicaCreate),
+		}))
+
+	for i := range jobs.Items {
+		if err == nil {
+			err = errors.WithStack(client.IgnoreNotFound(r.Client.Delete(ctx, &jobs.Items[i],
+				client.PropagationPolicy(metav1.DeletePropagationBackground))))
+		}
+	}
+
+	if err == nil {
+		for i := range postgresCluster.Status.PGBackRest.Repos {
+			postgresCluster.Status.PGBackRest.Repos[i].ReplicaCreateBackupComplete = false
+		}
+	}
+
+	return err
+}
+
 // reconcileRepos is responsible for reconciling any pgBackRest repositories configured
 // for the cluster
 func (r *Reconciler) reconcileRepos(ctx context.Context,
@@ -2342,6 +2678,106 @@ func (r *Reconciler) reconcileStanzaCreate(ctx context.Context,
 	return false, nil
 }
 
+// pgBackRestInfoInterval is how often the backups in each pgBackRest repository
+// are read. They are read sooner when a backup Job finishes.
+const pgBackRestInfoInterval = 5 * time.Minute
+
+// pgBackRestStatusBackups is the number of the most recent backups in each
+// pgBackRest repository that are recorded in status.
+const pgBackRestStatusBackups = 20
+
+// +kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
+
+// reconcilePGBackRestInfo records the backups and WAL in each pgBackRest
+// repository by running "pgbackrest info" on the primary. It runs no more than
+// once per pgBackRestInfoInterval unless a backup finished since the last time.
+func (r *Reconciler) reconcilePGBackRestInfo(ctx context.Context,
+	postgresCluster *v1beta1.PostgresCluster, instances *observedInstances,
+) reconcile.Result {
+	log := logging.FromContext(ctx)
+	status := postgresCluster.Status.PGBackRest
+
+	stanzaCreated := false
+	for _, repo := range status.Repos {
+		stanzaCreated = stanzaCreated || repo.StanzaCreated
+	}
+	pod, _ := instances.writablePod(naming.ContainerDatabase)
+	if !stanzaCreated || pod == nil {
+		return reconcile.Result{}
+	}
+
+	observed := status.BackupsObservedTime
+	finished := func(completed *metav1.Time) bool {
+		return completed != nil && observed.Before(completed)
+	}
+	if observed != nil {
+		due := time.Since(observed.Time) >= pgBackRestInfoInterval
+		if status.ManualBackup != nil && finished(status.ManualBackup.CompletionTime) {
+			due = true
+		}
+		for _, backup := range status.ScheduledBackups {
+			due = due || finished(backup.CompletionTime)
+		}
+		if !due {
+			return reconcile.Result{
+				RequeueAfter: pgBackRestInfoInterval - time.Since(observed.Time),
+			}
+		}
+	}
+
+	// NOTE: pgBackRest may fail while a repository is unavailable. That is not
+	// an error for the cluster; try again later.
+	now := metav1.Now()
+	status.BackupsObservedTime = &now
+	result := reconcile.Result{RequeueAfter: pgBackRestInfoInterval}
+
+	repos, err := pgbackrest.Executor(func(ctx context.Context,
+		stdin io.Reader, stdout, stderr io.Writer, command ...string,
+	) error {
+		return r.PodExec(pod.Namespace, pod.Name, naming.ContainerDatabase,
+			stdin, stdout, stderr, command...)
+	}).Info(ctx)
+	if err != nil {
+		log.V(1).Info("unable to read pgBackRest backups", "pod", pod.Name, "error", err.Error())
+		return result
+	}
+
+	for i := range status.Repos {
+		repo, info := &status.Repos[i], repos[status.Repos[i].Name]
+
+		repo.ArchiveMin, repo.ArchiveMax = info.ArchiveMin, info.ArchiveMax
+
+		// Keep status small; a repository can have many backups.
+		backups := info.Backups
+		if len(backups) > pgBackRestStatusBackups {
+			backups = backups[len(backups)-pgBackRestStatusBackups:]
+		}
+
+		repo.Backups = nil
+		for _, backup := range backups {
+			start, stop := metav1.NewTime(backup.Start), metav1.NewTime(backup.Stop)
+			repo.Backups = append(repo.Backups, v1beta1.PGBackRestBackupInfo{
+				Label:               backup.Label,
+				Type:                backup.Type,
+				StartTime:           &start,
+				StopTime:            &stop,
+				DatabaseSizeBytes:   backup.DatabaseSize,
+				RepositorySizeBytes: backup.RepositorySize,
+				ArchiveStart:        backup.ArchiveStart,
+				ArchiveStop:         backup.ArchiveStop,
+			})
+		}
+
+		repo.EarliestRecoveryTime = nil
+		if earliest, ok := info.EarliestRecoveryTime(); ok {
+			t := metav1.NewTime(earliest)
+			repo.EarliestRecoveryTime = &t
+		}
+	}
+
+	return result
+}
+
 // getPGBackRestExecSelector returns a selector and container name that allows the proper
 // Pod (along with a specific container within it) to be found within the Kubernetes
 // cluster as needed to exec into the container and run a pgBackRest command.
@@ -2358,8 +2794,9 @@ func getPGBackRestExecSelector(postgresCluster *v1beta1.PostgresCluster,
 		return nil, "", fmt.Errorf("repo %q is not defined for this cluster", repoName)
 	}
 
+	// The dedicated repository host runs every backup when backups are taken from a standby.
 	var volumeRepo bool
-	if repo.Volume != nil {
+	if repo.Volume != nil || pgbackrest.BackupFromStandbyEnabled(postgresCluster) {
 		volumeRepo = true
 	}
 
@@ -2628,13 +3065,20 @@ func (r *Reconciler) reconcilePGBackRestCronJob(
 	// started will continue.
 	// - https://docs.k8s.io/reference/kubernetes-api/workload-resources/cron-job-v1beta1/#CronJobSpec
 	suspend := (cluster.Spec.Shutdown != nil && *cluster.Spec.Shutdown) ||
-		(cluster.Spec.Standby != nil && cluster.Spec.Standby.Enabled)
+		(cluster.Spec.Standby != nil && cluster.Spec.Standby.Enabled) ||
+		(repo.BackupSchedules.Suspend != nil && *repo.BackupSchedules.Suspend)
 
 	pgBackRestCronJob := &batchv1beta1.CronJob{
 		ObjectMeta: objectmeta,
 		Spec: batchv1beta1.CronJobSpec{
 			Schedule: *schedule,
 			Suspend:  &suspend,
+
+			// pgBackRest allows only one backup of a stanza at a time. Skip a
+			// scheduled backup while the previous one is still running rather
+			// than start a Job that fails. This is documented on the schedules.
+			ConcurrencyPolicy: batchv1beta1.ForbidConcurrent,
+
 			JobTemplate: batchv1beta1.JobTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: annotations,
@@ -2652,6 +3096,11 @@ func (r *Reconciler) reconcilePGBackRestCronJob(
 	pgBackRestCronJob.Spec.JobTemplate.Spec.Template.Spec.ImagePullSecrets =
 		cluster.Spec.ImagePullSecrets
 
+	if jobs := cluster.Spec.Backups.PGBackRest.Jobs; jobs != nil {
+		pgBackRestCronJob.Spec.SuccessfulJobsHistoryLimit = jobs.SuccessfulJobsHistoryLimit
+		pgBackRestCronJob.Spec.FailedJobsHistoryLimit = jobs.FailedJobsHistoryLimit
+	}
+
 	// set metadata
 	pgBackRestCronJob.SetGroupVersionKind(batchv1beta1.SchemeGroupVersion.WithKind("CronJob"))
 	err = errors.WithStack(r.setControllerReference(cluster, pgBackRestCronJob))
@@ -2667,3 +3116,588 @@ func (r *Reconciler) reconcilePGBackRestCronJob(
 	}
 	return err
 }
+
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=create;patch;delete
+// +kubebuilder:rbac:groups="",resources=pods,verbs=list
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=delete
+
+// reconcileRestoreVerification verifies the backups of each repo that has a verification
+// schedule. A CronJob starts a Job that restores the latest backup into a temporary volume. The
+// volume is created ahead of that Job so the Job can start right away. Once the Job finishes,
+// its result is recorded in status and the Job and its volume are deleted.
+func (r *Reconciler) reconcileRestoreVerification(ctx context.Context,
+	cluster *v1beta1.PostgresCluster, verificationJobs []*batchv1.Job,
+	verificationVolumes []*corev1.PersistentVolumeClaim, configHash string,
+) (reconcile.Result, error) {
+
+	result := reconcile.Result{}
+	verifications := []v1beta1.PGBackRestVerificationStatus{}
+
+	for _, repo := range cluster.Spec.Backups.PGBackRest.Repos {
+		if repo.Verification == nil {
+			continue
+		}
+
+		// keep the status of repos that are still verified
+		for _, status := range cluster.Status.PGBackRest.Verifications {
+			if status.RepoName == repo.Name {
+				verifications = append(verifications, status)
+			}
+		}
+
+		var volume *corev1.PersistentVolumeClaim
+		for _, pvc := range verificationVolumes {
+			if pvc.GetName() == naming.PGBackRestVerifyVolume(cluster, repo.Name).Name {
+				volume = pvc
+			}
+		}
+
+		var active *batchv1.Job
+		for _, job := range verificationJobs {
+			if job.GetLabels()[naming.LabelPGBackRestVerify] != repo.Name {
+				continue
+			}
+			if !jobCompleted(job) && !jobFailed(job) {
+				active = job
+				continue
+			}
+
+			// record the result of a finished Job and then delete it along with its Pod and
+			// volume
+			var observed bool
+			for _, status := range verifications {
+				observed = observed || status.JobName == job.GetName()
+			}
+			if !observed {
+				status, err := r.observeVerificationJob(ctx, cluster, repo.Name, job)
+				if err != nil {
+					return result, err
+				}
+				verifications = setVerificationStatus(verifications, status)
+			}
+
+			if err := r.Client.Delete(ctx, job,
+				client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil &&
+				!apierrors.IsNotFound(err) {
+				return result, errors.WithStack(err)
+			}
+		}
+
+		// Wait for the stanza to exist, just like scheduled backups.
+		var stanzaCreated bool
+		for _, repoStatus := range cluster.Status.PGBackRest.Repos {
+			if repoStatus.Name == repo.Name {
+				stanzaCreated = repoStatus.StanzaCreated
+			}
+		}
+		if !patroni.ClusterBootstrapped(cluster) || !stanzaCreated {
+			continue
+		}
+
+		// A fresh volume waits for the next Job. Once a Job uses the volume, the volume
+		// belongs to that Job.
+		var used bool
+		if volume != nil {
+			for _, ref := range volume.GetOwnerReferences() {
+				used = used || ref.Kind == "Job"
+			}
+		}
+
+		switch {
+		case volume != nil && volume.GetDeletionTimestamp() != nil:
+			// wait for the volume of a previous Job to go away, then replace it
+			result = updateReconcileResult(result,
+				reconcile.Result{RequeueAfter: 5 * time.Second})
+
+		case used && (active == nil || !isOwnedBy(volume, active)):
+			// the volume belongs to a Job that has finished
+			if err := r.Client.Delete(ctx, volume); err != nil &&
+				!apierrors.IsNotFound(err) {
+				return result, errors.WithStack(err)
+			}
+			result = updateReconcileResult(result,
+				reconcile.Result{RequeueAfter: 5 * time.Second})
+
+		case volume == nil || (active != nil && !used):
+			if err := r.applyVerificationVolume(ctx, cluster, repo, active); err != nil {
+				return result, err
+			}
+		}
+
+		if err := r.reconcileVerificationCronJob(ctx, cluster, repo, configHash); err != nil {
+			return result, err
+		}
+	}
+
+	cluster.Status.PGBackRest.Verifications = verifications
+	return result, nil
+}
+
+// observeVerificationJob returns the result of a verification Job that has finished and records
+// it in an Event.
+func (r *Reconciler) observeVerificationJob(ctx context.Context,
+	cluster *v1beta1.PostgresCluster, repoName string, job *batchv1.Job,
+) (v1beta1.PGBackRestVerificationStatus, error) {
+
+	status := v1beta1.PGBackRestVerificationStatus{
+		RepoName:  repoName,
+		JobName:   job.GetName(),
+		Succeeded: jobCompleted(job),
+		StartTime: job.Status.StartTime,
+	}
+
+	// The Job controller sets completion time only when a Job succeeds.
+	status.CompletionTime = job.Status.CompletionTime
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			status.CompletionTime = condition.LastTransitionTime.DeepCopy()
+		}
+	}
+	if status.StartTime != nil && status.CompletionTime != nil {
+		status.Duration = &metav1.Duration{
+			Duration: status.CompletionTime.Sub(status.StartTime.Time),
+		}
+	}
+
+	// The restore container reports the end of its output when it fails.
+	if !status.Succeeded {
+		pods := &corev1.PodList{}
+		if err := r.Client.List(ctx, pods, client.InNamespace(job.GetNamespace()),
+			client.MatchingLabels{"job-name": job.GetName()}); err != nil {
+			return status, errors.WithStack(err)
+		}
+		for _, pod := range pods.Items {
+			for _, container := range pod.Status.ContainerStatuses {
+				if container.Name == naming.PGBackRestRestoreContainerName &&
+					container.State.Terminated != nil {
+					status.Message = strings.TrimSpace(container.State.Terminated.Message)
+				}
+			}
+		}
+		if status.Message == "" {
+			for _, condition := range job.Status.Conditions {
+				if condition.Type == batchv1.JobFailed {
+					status.Message = condition.Message
+				}
+			}
+		}
+	}
+
+	var duration string
+	if status.Duration != nil {
+		duration = status.Duration.Duration.String()
+	}
+	if status.Succeeded {
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "RestoreVerified",
+			"Restored the latest backup in %q and passed all checks in %s",
+			repoName, duration)
+	} else {
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "RestoreVerificationFailed",
+			"Unable to verify the latest backup in %q: %s", repoName, status.Message)
+	}
+
+	return status, nil
+}
+
+// setVerificationStatus replaces the status of the repo in verifications, or adds it.
+func setVerificationStatus(verifications []v1beta1.PGBackRestVerificationStatus,
+	status v1beta1.PGBackRestVerificationStatus) []v1beta1.PGBackRestVerificationStatus {
+	for i := range verifications {
+		if verifications[i].RepoName == status.RepoName {
+			verifications[i] = status
+			return verifications
+		}
+	}
+	return append(verifications, status)
+}
+
+// isOwnedBy returns whether or not owner is one of the owners of object.
+func isOwnedBy(object metav1.Object, owner metav1.Object) bool {
+	for _, ref := range object.GetOwnerReferences() {
+		if ref.UID == owner.GetUID() {
+			return true
+		}
+	}
+	return false
+}
+
+// applyVerificationVolume creates the temporary volume that a Job restores the backups of repo
+// into. The volume belongs to cluster until job is running, and then to job so that it goes away
+// with the Job.
+func (r *Reconciler) applyVerificationVolume(ctx context.Context,
+	cluster *v1beta1.PostgresCluster, repo v1beta1.PGBackRestRepo, job *batchv1.Job,
+) error {
+	volume := &corev1.PersistentVolumeClaim{
+		ObjectMeta: naming.PGBackRestVerifyVolume(cluster, repo.Name),
+		Spec:       repo.Verification.VolumeClaimSpec,
+	}
+	volume.Annotations = naming.Merge(
+		cluster.Spec.Metadata.GetAnnotationsOrNil(),
+		cluster.Spec.Backups.PGBackRest.Metadata.GetAnnotationsOrNil())
+	volume.Labels = naming.Merge(
+		cluster.Spec.Metadata.GetLabelsOrNil(),
+		cluster.Spec.Backups.PGBackRest.Metadata.GetLabelsOrNil(),
+		naming.PGBackRestVerifyLabels(cluster.Name, repo.Name),
+	)
+	volume.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim"))
+
+	var err error
+	if job == nil {
+		err = errors.WithStack(r.setControllerReference(cluster, volume))
+	} else {
+		err = errors.WithStack(controllerutil.SetOwnerReference(job, volume, r.Client.Scheme()))
+	}
+	if err == nil {
+		err = r.apply(ctx, volume)
+	}
+	return err
+}
+
+// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=create;patch
+
+// reconcileVerificationCronJob creates the CronJob that verifies the backups of repo according
+// to its verification schedule.
+func (r *Reconciler) reconcileVerificationCronJob(ctx context.Context,
+	cluster *v1beta1.PostgresCluster, repo v1beta1.PGBackRestRepo, configHash string,
+) error {
+
+	verification := repo.Verification
+	annotations := naming.Merge(
+		cluster.Spec.Metadata.GetAnnotationsOrNil(),
+		cluster.Spec.Backups.PGBackRest.Metadata.GetAnnotationsOrNil())
+	labels := naming.Merge(
+		cluster.Spec.Metadata.GetLabelsOrNil(),
+		cluster.Spec.Backups.PGBackRest.Metadata.GetLabelsOrNil(),
+		naming.PGBackRestVerifyLabels(cluster.Name, repo.Name),
+	)
+
+	database := verification.Database
+	if database == "" {
+		database = "postgres"
+	}
+
+	// Restore the latest backup only until PostgreSQL is consistent. Tablespaces are restored
+	// to the temporary volume as well.
+	dataVolumeMount := postgres.DataVolumeMount()
+	pgdata := postgres.DataDirectory(cluster)
+	cmd := pgbackrest.VerifyCommand(pgdata, database, verification.Checks,
+		"--stanza="+pgbackrest.DefaultStanzaName, "--pg1-path="+pgdata,
+		"--repo="+regexRepoIndex.FindString(repo.Name),
+		"--type=immediate", "--target-action=promote",
+		"--tablespace-map-all="+dataVolumeMount.MountPath+"/tablespaces")
+
+	volumes := []corev1.Volume{{
+		Name: dataVolumeMount.Name,
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: naming.PGBackRestVerifyVolume(cluster, repo.Name).Name,
+			},
+		},
+	}}
+
+	// Verification Jobs are scheduled like backup Jobs unless the verification says otherwise.
+	scheduling := &v1beta1.PostgresClusterDataSource{
+		Resources:         verification.Resources,
+		Affinity:          verification.Affinity,
+		Tolerations:       verification.Tolerations,
+		PriorityClassName: verification.PriorityClassName,
+	}
+	jobs := cluster.Spec.Backups.PGBackRest.Jobs
+	if jobs != nil {
+		if scheduling.Affinity == nil {
+			scheduling.Affinity = jobs.Affinity
+		}
+		if scheduling.Tolerations == nil {
+			scheduling.Tolerations = jobs.Tolerations
+		}
+		if scheduling.PriorityClassName == nil {
+			scheduling.PriorityClassName = jobs.PriorityClassName
+		}
+	}
+
+	job := &batchv1.Job{}
+	if err := r.generateRestoreJobIntent(cluster, configHash, "", cmd,
+		[]corev1.VolumeMount{dataVolumeMount}, volumes, scheduling, job); err != nil {
+		return errors.WithStack(err)
+	}
+	if jobs != nil {
+		job.Spec.Template.Spec.NodeSelector = jobs.NodeSelector
+		job.Spec.Template.Spec.TopologySpreadConstraints = jobs.TopologySpreadConstraints
+	}
+	if err := addPGBackRestToRestoreJob(cluster, cluster, job,
+		verification.Resources); err != nil {
+		return err
+	}
+
+	// A verification Job is not a restore of this cluster; label it accordingly.
+	job.Spec.Template.ObjectMeta = metav1.ObjectMeta{
+		Annotations: naming.Merge(annotations,
+			map[string]string{naming.PGBackRestConfigHash: configHash}),
+		Labels: labels,
+	}
+
+	// A failed verification is a result; do not retry it. A retry would restore into a volume
+	// that holds the partial restore of the failed attempt. The next scheduled verification
+	// starts over with a fresh volume. Report the end of the output of a failed container so
+	// it can be recorded in status.
+	job.Spec.BackoffLimit = initialize.Int32(0)
+	job.Spec.ActiveDeadlineSeconds = verification.ActiveDeadlineSeconds
+	job.Spec.Template.Spec.Containers[0].TerminationMessagePolicy =
+		corev1.TerminationMessageFallbackToLogsOnError
+
+	// Suspend verification when shutdown. Any jobs that have already started will continue.
+	suspend := cluster.Spec.Shutdown != nil && *cluster.Spec.Shutdown
+
+	cronJob := &batchv1beta1.CronJob{
+		ObjectMeta: naming.PGBackRestVerifyCronJob(cluster, repo.Name),
+		Spec: batchv1beta1.CronJobSpec{
+			Schedule:          verification.Schedule,
+			Suspend:           &suspend,
+			ConcurrencyPolicy: batchv1beta1.ForbidConcurrent,
+			JobTemplate: batchv1beta1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: annotations,
+					Labels:      labels,
+				},
+				Spec: job.Spec,
+			},
+		},
+	}
+	cronJob.Annotations = annotations
+	cronJob.Labels = labels
+
+	cronJob.SetGroupVersionKind(batchv1beta1.SchemeGroupVersion.WithKind("CronJob"))
+	err := errors.WithStack(r.setControllerReference(cluster, cronJob))
+	if err == nil {
+		err = r.apply(ctx, cronJob)
+	}
+	if err != nil {
+		r.Recorder.Event(cluster, corev1.EventTypeWarning, EventUnableToCreatePGBackRestCronJob,
+			err.Error())
+	}
+	return err
+}
+
+// observeBackupStandby reports whether or not there are replicas that backups can be taken from
+// in a condition of cluster. pgBackRest chooses among them on its own.
+func (r *Reconciler) observeBackupStandby(cluster *v1beta1.PostgresCluster,
+	instances *observedInstances) {
+
+	standby := cluster.Spec.Backups.PGBackRest.BackupFromStandby
+	if standby == nil || !standby.Enabled {
+		// TODO: remove guard with move to controller-runtime 0.9.0 https://issue.k8s.io/99714
+		if len(cluster.Status.Conditions) > 0 {
+			meta.RemoveStatusCondition(&cluster.Status.Conditions,
+				ConditionBackupStandbyAvailable)
+		}
+		return
+	}
+
+	var standbys []string
+	if instances != nil {
+		for _, instance := range instances.forCluster {
+			available, _ := instance.IsAvailable()
+			primary, known := instance.IsPrimary()
+			if available && known && !primary {
+				standbys = append(standbys, instance.Name)
+			}
+		}
+	}
+	sort.Strings(standbys)
+
+	condition := metav1.Condition{
+		Type:               ConditionBackupStandbyAvailable,
+		ObservedGeneration: cluster.GetGeneration(),
+	}
+	switch {
+	case !pgbackrest.DedicatedRepoHostEnabled(cluster):
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "RepoHostRequired"
+		condition.Message = "Backups are taken from the primary. Taking them from a " +
+			"standby requires a volume repository."
+	case len(standbys) == 0 && standby.Fallback == "Fail":
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "NoReplicaAvailable"
+		condition.Message = "Backups will fail until a replica is available."
+	case len(standbys) == 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "NoReplicaAvailable"
+		condition.Message = "Backups are taken from the primary until a replica is available."
+	default:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "ReplicaAvailable"
+		condition.Message = fmt.Sprintf("Backups are taken from one of the available replicas: %s.",
+			strings.Join(standbys, ", "))
+	}
+
+	// Record an Event when backups can no longer be taken from a standby.
+	previous := meta.FindStatusCondition(cluster.Status.Conditions, condition.Type)
+	if condition.Status == metav1.ConditionFalse &&
+		(previous == nil || previous.Reason != condition.Reason) {
+		r.Recorder.Event(cluster, corev1.EventTypeWarning, "BackupStandbyUnavailable",
+			condition.Message)
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+}
+
+const (
+	// archivingInterval is how often WAL archiving is observed while the
+	// cluster is otherwise unchanged.
+	archivingInterval = time.Minute
+
+	// archivingCheckInterval is how often "pgbackrest check" runs while WAL
+	// archiving is failing. Each check switches to a new WAL file, so every
+	// check adds one more WAL segment (16MiB by default) to those waiting on
+	// the primary. That is at most twelve segments an hour.
+	archivingCheckInterval = 5 * time.Minute
+
+	// archivingCheckTimeout is how long "pgbackrest check" waits for its WAL
+	// file to be archived. The check runs in a reconcile worker, of which there
+	// are only a few, and archiving is already known to be failing. Waiting
+	// longer rarely says more about the failure.
+	archivingCheckTimeout = 5 * time.Second
+
+	// archivingFailureStale is how long after the most recent failure to
+	// archive a WAL file rollouts stop waiting for archiving to succeed.
+	// PostgreSQL retries a failing WAL file about every minute, so an older
+	// failure means the archiver has stopped or could not be observed, such
+	// as while the primary is down. Redeploying the primary might be what it
+	// takes to fix that.
+	archivingFailureStale = 15 * time.Minute
+)
+
+// +kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// reconcileArchiving reads the WAL archiver statistics of the primary into status and sets the
+// ArchivingHealthy condition. While archiving is failing, it runs "pgbackrest check" now and
+// then to learn why. Events are recorded when archiving starts failing and when it recovers.
+func (r *Reconciler) reconcileArchiving(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, instances *observedInstances,
+) reconcile.Result {
+	const container = naming.ContainerDatabase
+	log := logging.FromContext(ctx)
+
+	// A standby cluster does not archive WAL files of its own.
+	if cluster.Spec.Standby != nil && cluster.Spec.Standby.Enabled {
+		// TODO: remove guard with move to controller-runtime 0.9.0 https://issue.k8s.io/99714
+		if len(cluster.Status.Conditions) > 0 {
+			meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionArchivingHealthy)
+		}
+		if cluster.Status.PGBackRest != nil {
+			cluster.Status.PGBackRest.Archiving = nil
+		}
+		return reconcile.Result{}
+	}
+
+	pod, _ := instances.writablePod(container)
+	if pod == nil || cluster.Status.PGBackRest == nil {
+		return reconcile.Result{}
+	}
+
+	// Every change to the cluster or its status triggers a reconcile. Observe
+	// archiving only when the interval has passed since the last time.
+	previousStatus := cluster.Status.PGBackRest.Archiving
+	if previousStatus != nil && previousStatus.ObservedTime != nil {
+		if elapsed := time.Since(previousStatus.ObservedTime.Time); elapsed < archivingInterval {
+			return reconcile.Result{RequeueAfter: archivingInterval - elapsed}
+		}
+	}
+
+	// NOTE: Calling PostgreSQL or pgBackRest may fail while they are starting
+	// or stopping. That is not an error for the cluster; try again later.
+	now := metav1.Now()
+	result := reconcile.Result{RequeueAfter: archivingInterval}
+	exec := func(
+		_ context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+	) error {
+		return r.PodExec(pod.Namespace, pod.Name, container, stdin, stdout, stderr, command...)
+	}
+
+	archiver, err := postgres.GetArchiverStatus(ctx, exec)
+	if err != nil {
+		log.V(1).Info("unable to observe WAL archiving", "pod", pod.Name, "error", err.Error())
+		if previousStatus == nil {
+			cluster.Status.PGBackRest.Archiving = &v1beta1.PGBackRestArchivingStatus{}
+		}
+		cluster.Status.PGBackRest.Archiving.ObservedTime = &now
+		return result
+	}
+
+	status := &v1beta1.PGBackRestArchivingStatus{
+		ArchivedCount:   archiver.ArchivedCount,
+		LastArchivedWAL: archiver.LastArchivedWAL,
+		FailedCount:     archiver.FailedCount,
+		LastFailedWAL:   archiver.LastFailedWAL,
+		ReadyCount:      archiver.ReadyCount,
+		ObservedTime:    &now,
+	}
+	if archiver.LastArchivedTime != nil {
+		status.LastArchivedTime = &metav1.Time{Time: *archiver.LastArchivedTime}
+	}
+	if archiver.LastFailedTime != nil {
+		status.LastFailedTime = &metav1.Time{Time: *archiver.LastFailedTime}
+	}
+	if previousStatus != nil {
+		status.CheckTime = previousStatus.CheckTime
+	}
+
+	previous := meta.FindStatusCondition(cluster.Status.Conditions, ConditionArchivingHealthy)
+	condition := metav1.Condition{
+		ObservedGeneration: cluster.GetGeneration(),
+		Type:               ConditionArchivingHealthy,
+		Status:             metav1.ConditionTrue,
+		Reason:             "ArchivingSucceeded",
+		Message:            "PostgreSQL is archiving WAL files.",
+	}
+
+	if archiver.Failing() {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "ArchivingFailed"
+		// The failed WAL file and the number waiting are in status; keep them
+		// out of the message so it changes only when something else does.
+		condition.Message = "PostgreSQL is failing to archive WAL files."
+
+		if cluster.Spec.Backups.PGBackRest.PauseRolloutsOnArchiveFailure {
+			condition.Message += " The primary is not redeployed until archiving succeeds."
+		}
+
+		// Ask pgBackRest what is wrong, but not so often that the WAL files it
+		// switches to add up. Keep the answer from the last time in between.
+		if status.CheckTime == nil || previous == nil ||
+			previous.Status != metav1.ConditionFalse ||
+			now.Sub(status.CheckTime.Time) >= archivingCheckInterval {
+
+			status.CheckTime = &now
+			err := pgbackrest.Executor(exec).Check(ctx, archivingCheckTimeout)
+			if err != nil {
+				condition.Message += " pgbackrest check: " +
+					strings.TrimSpace(err.Error())
+			} else {
+				condition.Message += " pgbackrest check: succeeded."
+			}
+		} else if i := strings.Index(previous.Message, " pgbackrest check: "); i >= 0 {
+			condition.Message += previous.Message[i:]
+		}
+	}
+
+	// Messages of conditions are limited to 32Ki characters.
+	if len(condition.Message) > 32768 {
+		condition.Message = condition.Message[:32768]
+	}
+
+	switch {
+	case condition.Status == metav1.ConditionFalse &&
+		(previous == nil || previous.Status != metav1.ConditionFalse):
+		r.Recorder.Event(cluster, corev1.EventTypeWarning, EventArchivingFailed, condition.Message)
+	case condition.Status == metav1.ConditionTrue &&
+		previous != nil && previous.Status == metav1.ConditionFalse:
+		r.Recorder.Event(cluster, corev1.EventTypeNormal, EventArchivingRecovered, condition.Message)
+	}
+
+	cluster.Status.PGBackRest.Archiving = status
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+
+	return result
+}
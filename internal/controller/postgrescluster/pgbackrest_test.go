@@ -35,6 +35,7 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -47,6 +48,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -859,6 +861,413 @@ func TestReconcileStanzaCreate(t *testing.T) {
 	}
 }
 
+func TestPGBackRestRepoOptionsValidation(t *testing.T) {
+	ctx := context.Background()
+	env, cc, _ := setupTestEnv(t, ControllerName)
+	t.Cleanup(func() { teardownTestEnv(t, env) })
+
+	ns := &corev1.Namespace{}
+	ns.GenerateName = "postgres-operator-test-"
+	assert.NilError(t, cc.Create(ctx, ns))
+	t.Cleanup(func() { assert.Check(t, cc.Delete(ctx, ns)) })
+
+	base := testCluster()
+	base.Namespace = ns.Name
+
+	t.Run("Valid", func(t *testing.T) {
+		cluster := base.DeepCopy()
+		repo := &cluster.Spec.Backups.PGBackRest.Repos[0]
+		repo.Retention = &v1beta1.PGBackRestRetention{
+			Full: initialize.Int32(14), FullType: "time",
+			Archive: initialize.Int32(2), ArchiveType: "diff",
+		}
+		repo.Compression = &v1beta1.PGBackRestCompression{Type: "zst", Level: initialize.Int32(-7)}
+		repo.Bundle, repo.BlockIncremental = initialize.Bool(true), initialize.Bool(true)
+		assert.NilError(t, cc.Create(ctx, cluster, client.DryRunAll))
+
+		repo.Compression = &v1beta1.PGBackRestCompression{Type: "none"}
+		repo.Bundle, repo.BlockIncremental = nil, initialize.Bool(false)
+		assert.NilError(t, cc.Create(ctx, cluster, client.DryRunAll))
+	})
+
+	for _, tt := range []struct {
+		name   string
+		mutate func(*v1beta1.PGBackRestRepo)
+		field  string
+	}{
+		{
+			name: "FullTypeWithoutFull", field: "retention",
+			mutate: func(repo *v1beta1.PGBackRestRepo) {
+				repo.Retention = &v1beta1.PGBackRestRetention{FullType: "time"}
+			},
+		},
+		{
+			name: "ArchiveTypeWithoutArchive", field: "retention",
+			mutate: func(repo *v1beta1.PGBackRestRepo) {
+				repo.Retention = &v1beta1.PGBackRestRetention{
+					Full: initialize.Int32(2), ArchiveType: "incr",
+				}
+			},
+		},
+		{
+			name: "CompressionLevelOutOfRange", field: "compression",
+			mutate: func(repo *v1beta1.PGBackRestRepo) {
+				repo.Compression = &v1beta1.PGBackRestCompression{Type: "gz", Level: initialize.Int32(12)}
+			},
+		},
+		{
+			name: "CompressionLevelWithoutType", field: "compression",
+			mutate: func(repo *v1beta1.PGBackRestRepo) {
+				repo.Compression = &v1beta1.PGBackRestCompression{Type: "none", Level: initialize.Int32(1)}
+			},
+		},
+		{
+			name: "TwoStorageTypes", field: "repos",
+			mutate: func(repo *v1beta1.PGBackRestRepo) {
+				repo.S3 = &v1beta1.RepoS3{Bucket: "b", Endpoint: "e", Region: "r"}
+			},
+		},
+		{
+			name: "SharedVolumeClaimAndNFS", field: "sharedVolume",
+			mutate: func(repo *v1beta1.PGBackRestRepo) {
+				repo.Volume = nil
+				repo.SharedVolume = &v1beta1.RepoSharedVolume{
+					ClaimName: "some-claim",
+					NFS:       &corev1.NFSVolumeSource{Server: "nfs.example.com", Path: "/exports"},
+				}
+			},
+		},
+		{
+			name: "BlockIncrementalWithoutBundle", field: "repos",
+			mutate: func(repo *v1beta1.PGBackRestRepo) {
+				repo.Bundle, repo.BlockIncremental = initialize.Bool(false), initialize.Bool(true)
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := base.DeepCopy()
+			tt.mutate(&cluster.Spec.Backups.PGBackRest.Repos[0])
+
+			err := cc.Create(ctx, cluster, client.DryRunAll)
+			assert.Assert(t, apierrors.IsInvalid(err), "expected Invalid, got\n%#v", err)
+			assert.ErrorContains(t, err, tt.field)
+		})
+	}
+}
+
+func TestReconcilePGBackRestInfo(t *testing.T) {
+	ctx := context.Background()
+
+	primary := &corev1.Pod{}
+	primary.Namespace, primary.Name = "ns1", "primary-0"
+	primary.Annotations = map[string]string{"status": `{"role":"master"}`}
+	primary.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:  naming.ContainerDatabase,
+		State: corev1.ContainerState{Running: new(corev1.ContainerStateRunning)},
+	}}
+	instances := &observedInstances{forCluster: []*Instance{
+		{Name: "primary", Pods: []*corev1.Pod{primary}},
+	}}
+
+	const info = `[{"name":"db",
+		"archive":[{"database":{"id":1,"repo-key":1},"min":"000000010000000000000002","max":"000000010000000000000005"}],
+		"backup":[{"label":"20210601-120000F","type":"full",
+			"archive":{"start":"000000010000000000000002","stop":"000000010000000000000002"},
+			"database":{"id":1,"repo-key":1},
+			"info":{"size":31457280,"repository":{"delta":3932160}},
+			"timestamp":{"start":1622548800,"stop":1622548830}}]}]`
+
+	newCluster := func() *v1beta1.PostgresCluster {
+		cluster := &v1beta1.PostgresCluster{}
+		cluster.Status.PGBackRest = &v1beta1.PGBackRestStatus{
+			Repos: []v1beta1.RepoStatus{
+				{Name: "repo1", StanzaCreated: true},
+				{Name: "repo2", StanzaCreated: true},
+			},
+		}
+		return cluster
+	}
+
+	var calls []string
+	r := &Reconciler{PodExec: func(
+		namespace, pod, container string, _ io.Reader, stdout, _ io.Writer, command ...string,
+	) error {
+		calls = append(calls, pod+" "+container+" "+strings.Join(command, " "))
+		_, err := stdout.Write([]byte(info))
+		return err
+	}}
+
+	t.Run("NoStanza", func(t *testing.T) {
+		calls = nil
+		cluster := newCluster()
+		cluster.Status.PGBackRest.Repos[0].StanzaCreated = false
+		cluster.Status.PGBackRest.Repos[1].StanzaCreated = false
+
+		result := r.reconcilePGBackRestInfo(ctx, cluster, instances)
+		assert.Equal(t, result, reconcile.Result{})
+		assert.Assert(t, calls == nil)
+	})
+
+	t.Run("NoPrimary", func(t *testing.T) {
+		calls = nil
+		result := r.reconcilePGBackRestInfo(ctx, newCluster(), &observedInstances{})
+		assert.Equal(t, result, reconcile.Result{})
+		assert.Assert(t, calls == nil)
+	})
+
+	t.Run("Observed", func(t *testing.T) {
+		calls = nil
+		cluster := newCluster()
+
+		result := r.reconcilePGBackRestInfo(ctx, cluster, instances)
+		assert.Equal(t, result.RequeueAfter, pgBackRestInfoInterval)
+		assert.DeepEqual(t, calls, []string{
+			"primary-0 database pgbackrest info --output=json --stanza=db",
+		})
+
+		status := cluster.Status.PGBackRest
+		assert.Assert(t, status.BackupsObservedTime != nil)
+
+		start := metav1.NewTime(time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC))
+		stop := metav1.NewTime(time.Date(2021, 6, 1, 12, 0, 30, 0, time.UTC))
+		assert.DeepEqual(t, status.Repos[0], v1beta1.RepoStatus{
+			Name: "repo1", StanzaCreated: true,
+			ArchiveMin: "000000010000000000000002",
+			ArchiveMax: "000000010000000000000005",
+			Backups: []v1beta1.PGBackRestBackupInfo{{
+				Label: "20210601-120000F", Type: "full",
+				StartTime: &start, StopTime: &stop,
+				DatabaseSizeBytes: 31457280, RepositorySizeBytes: 3932160,
+				ArchiveStart: "000000010000000000000002",
+				ArchiveStop:  "000000010000000000000002",
+			}},
+			EarliestRecoveryTime: &stop,
+		})
+		assert.DeepEqual(t, status.Repos[1], v1beta1.RepoStatus{
+			Name: "repo2", StanzaCreated: true,
+		})
+
+		// Nothing happens until the interval passes.
+		calls = nil
+		result = r.reconcilePGBackRestInfo(ctx, cluster, instances)
+		assert.Assert(t, result.RequeueAfter > 0)
+		assert.Assert(t, result.RequeueAfter <= pgBackRestInfoInterval)
+		assert.Assert(t, calls == nil)
+
+		// A backup that finishes is read sooner.
+		finished := metav1.NewTime(status.BackupsObservedTime.Add(time.Second))
+		status.ScheduledBackups = []v1beta1.PGBackRestScheduledBackupStatus{
+			{CronJobName: "some-cron", CompletionTime: &finished},
+		}
+		_ = r.reconcilePGBackRestInfo(ctx, cluster, instances)
+		assert.Equal(t, len(calls), 1)
+	})
+
+	t.Run("ManyBackups", func(t *testing.T) {
+		var backups []string
+		for i := 0; i < pgBackRestStatusBackups+5; i++ {
+			backups = append(backups, fmt.Sprintf(`{"label":"backup-%02d","type":"full",
+				"archive":{"start":"0000000100000000000000%02X","stop":"0000000100000000000000%02X"},
+				"database":{"id":1,"repo-key":1},
+				"timestamp":{"start":%d,"stop":%d}}`, i, i+1, i+1, 1622548800+i*60, 1622548830+i*60))
+		}
+
+		cluster := newCluster()
+		r := &Reconciler{PodExec: func(
+			_, _, _ string, _ io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			_, err := stdout.Write([]byte(`[{"name":"db",
+				"archive":[{"database":{"id":1,"repo-key":1},"min":"000000010000000000000001"}],
+				"backup":[` + strings.Join(backups, ",") + `]}]`))
+			return err
+		}}
+
+		_ = r.reconcilePGBackRestInfo(ctx, cluster, instances)
+
+		// Only the most recent backups are kept, but the earliest recovery
+		// time comes from all of them.
+		repo := cluster.Status.PGBackRest.Repos[0]
+		assert.Equal(t, len(repo.Backups), pgBackRestStatusBackups)
+		assert.Equal(t, repo.Backups[0].Label, "backup-05")
+		assert.Equal(t, repo.Backups[pgBackRestStatusBackups-1].Label, "backup-24")
+		assert.Assert(t, repo.EarliestRecoveryTime != nil)
+		assert.Equal(t, repo.EarliestRecoveryTime.Unix(), int64(1622548830))
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		cluster := newCluster()
+		r := &Reconciler{PodExec: func(
+			_, _, _ string, _ io.Reader, _, _ io.Writer, _ ...string,
+		) error {
+			return errors.New("repository unavailable")
+		}}
+
+		// Errors are tried again later.
+		result := r.reconcilePGBackRestInfo(ctx, cluster, instances)
+		assert.Equal(t, result.RequeueAfter, pgBackRestInfoInterval)
+		assert.Assert(t, cluster.Status.PGBackRest.BackupsObservedTime != nil)
+		assert.Assert(t, cluster.Status.PGBackRest.Repos[0].Backups == nil)
+	})
+}
+
+func TestCopyRestoreConfigurationSFTP(t *testing.T) {
+	ctx := context.Background()
+	env, cc, _ := setupTestEnv(t, ControllerName)
+	t.Cleanup(func() { teardownTestEnv(t, env) })
+
+	namespace := func() *corev1.Namespace {
+		ns := &corev1.Namespace{}
+		ns.GenerateName = "postgres-operator-test-"
+		assert.NilError(t, cc.Create(ctx, ns))
+		t.Cleanup(func() { assert.Check(t, cc.Delete(ctx, ns)) })
+		return ns
+	}
+	sourceNamespace, targetNamespace := namespace(), namespace()
+
+	r := &Reconciler{Client: cc, Owner: client.FieldOwner(t.Name())}
+
+	key := &corev1.Secret{}
+	key.Namespace, key.Name = sourceNamespace.Name, "hippo-sftp"
+	key.Data = map[string][]byte{"id_ed25519": []byte("private"), "other": []byte("x")}
+	assert.NilError(t, cc.Create(ctx, key))
+
+	source := fakePostgresCluster("hippo", sourceNamespace.Name, "hippouid", false)
+	source.Spec.Backups.PGBackRest.Repos = []v1beta1.PGBackRestRepo{{
+		Name: "repo1",
+		SFTP: &v1beta1.RepoSFTP{
+			Host: "backups.example.com", User: "pgbackrest", HostKeyFingerprint: "F8:4E",
+			PrivateKeySecret: corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "hippo-sftp"},
+				Key:                  "id_ed25519",
+			},
+		},
+	}}
+	cluster := fakePostgresCluster("rhino", targetNamespace.Name, "rhinouid", false)
+
+	assert.NilError(t, r.copyRestoreConfiguration(ctx, cluster, source, "rhino-abcd"))
+
+	// The restore reads the key from a copy in the namespace of the cluster.
+	selector := source.Spec.Backups.PGBackRest.Repos[0].SFTP.PrivateKeySecret
+	assert.Equal(t, selector.Name, naming.PGBackRestSFTPSecret(source, "repo1").Name)
+	assert.Equal(t, selector.Key, "id_ed25519")
+
+	copied := &corev1.Secret{}
+	assert.NilError(t, cc.Get(ctx, client.ObjectKey{
+		Namespace: targetNamespace.Name, Name: selector.Name,
+	}, copied))
+	assert.DeepEqual(t, copied.Data, map[string][]byte{"id_ed25519": []byte("private")})
+	assert.Equal(t, len(copied.OwnerReferences), 1)
+	assert.Equal(t, copied.OwnerReferences[0].Name, "rhino")
+}
+
+func TestReconcilePGBackRestCipher(t *testing.T) {
+	ctx := context.Background()
+	env, cc, _ := setupTestEnv(t, ControllerName)
+	t.Cleanup(func() { teardownTestEnv(t, env) })
+
+	ns := &corev1.Namespace{}
+	ns.GenerateName = "postgres-operator-test-"
+	assert.NilError(t, cc.Create(ctx, ns))
+	t.Cleanup(func() { assert.Check(t, cc.Delete(ctx, ns)) })
+
+	recorder := record.NewFakeRecorder(10)
+	r := &Reconciler{Client: cc, Owner: client.FieldOwner(t.Name()), Recorder: recorder}
+
+	cluster := fakePostgresCluster("hippo", ns.Name, "hippouid", false)
+	cluster.Spec.Backups.PGBackRest.Repos = []v1beta1.PGBackRestRepo{
+		{Name: "repo1", S3: &v1beta1.RepoS3{Bucket: "b", Endpoint: "e", Region: "r"}},
+		{Name: "repo2", S3: &v1beta1.RepoS3{Bucket: "b", Endpoint: "e", Region: "r"}},
+	}
+	cluster.Status.PGBackRest = &v1beta1.PGBackRestStatus{
+		Repos: []v1beta1.RepoStatus{{Name: "repo1"}, {Name: "repo2"}},
+	}
+
+	current := func(t *testing.T) *corev1.Secret {
+		secret := &corev1.Secret{ObjectMeta: naming.PGBackRestCipherSecret(cluster)}
+		assert.NilError(t, cc.Get(ctx, client.ObjectKeyFromObject(secret), secret))
+		return secret
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		assert.NilError(t, r.reconcilePGBackRestCipher(ctx, cluster, nil))
+
+		secret := &corev1.Secret{ObjectMeta: naming.PGBackRestCipherSecret(cluster)}
+		err := cc.Get(ctx, client.ObjectKeyFromObject(secret), secret)
+		assert.Assert(t, apierrors.IsNotFound(err), "expected NotFound, got %v", err)
+	})
+
+	var generated string
+	t.Run("Generated", func(t *testing.T) {
+		cluster.Spec.Backups.PGBackRest.Repos[0].Encryption = &v1beta1.PGBackRestEncryption{}
+		assert.NilError(t, r.reconcilePGBackRestCipher(ctx, cluster, nil))
+
+		secret := current(t)
+		generated = string(secret.Data["repo1"])
+		assert.Equal(t, len(generated), 64)
+		assert.Assert(t, secret.Data["repo2"] == nil)
+		assert.Assert(t, strings.Contains(string(secret.Data["pgbackrest_cipher.conf"]),
+			"repo1-cipher-pass = "+generated+"\n"))
+		assert.Equal(t, cluster.Status.PGBackRest.Repos[0].Cipher, "aes-256-cbc")
+		assert.Equal(t, cluster.Status.PGBackRest.Repos[1].Cipher, "")
+
+		// The passphrase stays the same.
+		assert.NilError(t, r.reconcilePGBackRestCipher(ctx, cluster, secret))
+		assert.Equal(t, string(current(t).Data["repo1"]), generated)
+	})
+
+	userSecret := &corev1.Secret{}
+	userSecret.Namespace, userSecret.Name = ns.Name, "user-passphrase"
+	userSecret.Data = map[string][]byte{"pass": []byte("from-user")}
+	assert.NilError(t, cc.Create(ctx, userSecret))
+
+	t.Run("UserSecret", func(t *testing.T) {
+		cluster.Spec.Backups.PGBackRest.Repos[1].Encryption = &v1beta1.PGBackRestEncryption{
+			PassphraseSecret: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "user-passphrase"},
+				Key:                  "pass",
+			},
+		}
+		assert.NilError(t, r.reconcilePGBackRestCipher(ctx, cluster, current(t)))
+
+		secret := current(t)
+		assert.Equal(t, string(secret.Data["repo1"]), generated)
+		assert.Equal(t, string(secret.Data["repo2"]), "from-user")
+		assert.Equal(t, cluster.Status.PGBackRest.Repos[1].Cipher, "aes-256-cbc")
+		assert.Equal(t, len(recorder.Events), 0)
+	})
+
+	t.Run("MissingUserSecret", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Backups.PGBackRest.Repos[1].Encryption.PassphraseSecret.Key = "missing"
+
+		assert.ErrorContains(t, r.reconcilePGBackRestCipher(ctx, cluster, current(t)),
+			`no passphrase in key "missing"`)
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Assert(t, strings.Contains(<-recorder.Events, "InvalidEncryption"))
+	})
+
+	t.Run("StanzaCreated", func(t *testing.T) {
+		cluster.Status.PGBackRest.Repos[0].StanzaCreated = true
+		cluster.Status.PGBackRest.Repos[1].StanzaCreated = true
+
+		// Neither passphrase can change, and neither repo can be decrypted.
+		cluster.Spec.Backups.PGBackRest.Repos[0].Encryption = nil
+		userSecret.Data["pass"] = []byte("changed")
+		assert.NilError(t, cc.Update(ctx, userSecret))
+
+		assert.NilError(t, r.reconcilePGBackRestCipher(ctx, cluster, current(t)))
+		assert.Equal(t, len(recorder.Events), 2)
+		assert.Assert(t, strings.Contains(<-recorder.Events, "EncryptionChangeRefused"))
+		assert.Assert(t, strings.Contains(<-recorder.Events, "EncryptionChangeRefused"))
+
+		secret := current(t)
+		assert.Equal(t, string(secret.Data["repo1"]), generated)
+		assert.Equal(t, string(secret.Data["repo2"]), "from-user")
+		assert.Equal(t, cluster.Status.PGBackRest.Repos[0].Cipher, "aes-256-cbc")
+		assert.Equal(t, cluster.Status.PGBackRest.Repos[1].Cipher, "aes-256-cbc")
+	})
+}
+
 func TestGetPGBackRestExecSelector(t *testing.T) {
 
 	testCases := []struct {
@@ -907,6 +1316,30 @@ func TestGetPGBackRestExecSelector(t *testing.T) {
 			"postgres-operator.crunchydata.com/instance," +
 			"postgres-operator.crunchydata.com/role=master",
 		expectedContainer: "database",
+	}, {
+		desc: "cloud repo defined backups from standby",
+		cluster: &v1beta1.PostgresCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "hippo"},
+			Spec: v1beta1.PostgresClusterSpec{
+				Backups: v1beta1.Backups{
+					PGBackRest: v1beta1.PGBackRestArchive{
+						Repos: []v1beta1.PGBackRestRepo{{
+							Name:   "repo1",
+							Volume: &v1beta1.RepoPVC{},
+						}, {
+							Name: "repo2",
+							S3:   &v1beta1.RepoS3{},
+						}},
+						BackupFromStandby: &v1beta1.PGBackRestBackupFromStandby{Enabled: true},
+					},
+				},
+			},
+		},
+		repoName: "repo2",
+		expectedSelector: "postgres-operator.crunchydata.com/cluster=hippo," +
+			"postgres-operator.crunchydata.com/pgbackrest=," +
+			"postgres-operator.crunchydata.com/pgbackrest-dedicated=",
+		expectedContainer: "pgbackrest",
 	}}
 
 	for _, tc := range testCases {
@@ -2329,6 +2762,72 @@ func TestGenerateBackupJobIntent(t *testing.T) {
 		})
 	})
 
+	t.Run("RepoOptions", func(t *testing.T) {
+		cluster := &v1beta1.PostgresCluster{}
+		cluster.Spec.Backups.PGBackRest.Repos = []v1beta1.PGBackRestRepo{
+			{Name: "repo1", ProcessMax: initialize.Int32(8)},
+			{Name: "repo2", ProcessMax: initialize.Int32(2),
+				Compression: &v1beta1.PGBackRestCompression{Type: "lz4"}},
+		}
+
+		// Options from the spec of the repository are added unless they are
+		// among the options passed in.
+		job, err := generateBackupJobSpecIntent(
+			cluster,
+			"", "", "repo2", "", "",
+			nil, nil, "--type=full", "--process-max=6",
+		)
+		assert.NilError(t, err)
+
+		var opts string
+		for _, env := range job.Template.Spec.Containers[0].Env {
+			if env.Name == "COMMAND_OPTS" {
+				opts = env.Value
+			}
+		}
+		assert.Equal(t, opts,
+			"--stanza=db --repo=2 --compress-type=lz4 --type=full --process-max=6")
+	})
+
+	t.Run("Scheduling", func(t *testing.T) {
+		cluster := &v1beta1.PostgresCluster{}
+		cluster.Spec.Backups.PGBackRest.Jobs = &v1beta1.BackupJobs{
+			Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+						MatchExpressions: []corev1.NodeSelectorRequirement{{
+							Key: "key", Operator: "Exists",
+						}},
+					}},
+				},
+			}},
+			NodeSelector: map[string]string{"disk": "fast"},
+			Tolerations: []corev1.Toleration{{
+				Key: "spot", Operator: "Exists", Effect: "NoSchedule",
+			}},
+			TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{
+				MaxSkew: 1, TopologyKey: "zone", WhenUnsatisfiable: "ScheduleAnyway",
+			}},
+			ActiveDeadlineSeconds:   initialize.Int64(3600),
+			BackoffLimit:            initialize.Int32(2),
+			TTLSecondsAfterFinished: initialize.Int32(600),
+		}
+
+		job, err := generateBackupJobSpecIntent(
+			cluster,
+			"", "", "", "", "",
+			nil, nil,
+		)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, job.Template.Spec.Affinity, cluster.Spec.Backups.PGBackRest.Jobs.Affinity)
+		assert.DeepEqual(t, job.Template.Spec.NodeSelector, map[string]string{"disk": "fast"})
+		assert.DeepEqual(t, job.Template.Spec.Tolerations, cluster.Spec.Backups.PGBackRest.Jobs.Tolerations)
+		assert.DeepEqual(t, job.Template.Spec.TopologySpreadConstraints,
+			cluster.Spec.Backups.PGBackRest.Jobs.TopologySpreadConstraints)
+		assert.Equal(t, *job.ActiveDeadlineSeconds, int64(3600))
+		assert.Equal(t, *job.BackoffLimit, int32(2))
+		assert.Equal(t, *job.TTLSecondsAfterFinished, int32(600))
+	})
 }
 
 func TestGenerateRepoHostIntent(t *testing.T) {
@@ -2354,6 +2853,69 @@ func TestGenerateRepoHostIntent(t *testing.T) {
 	})
 }
 
+func TestReconcileRepoHostPodDisruptionBudget(t *testing.T) {
+	ctx := context.Background()
+	env, cc, _ := setupTestEnv(t, ControllerName)
+	t.Cleanup(func() { teardownTestEnv(t, env) })
+
+	ns := &corev1.Namespace{}
+	ns.GenerateName = "postgres-operator-test-"
+	assert.NilError(t, cc.Create(ctx, ns))
+	t.Cleanup(func() { assert.Check(t, cc.Delete(ctx, ns)) })
+
+	r := &Reconciler{Client: cc, Owner: client.FieldOwner(t.Name())}
+
+	cluster := fakePostgresCluster("hippo", ns.Name, "hippouid", true)
+	assert.NilError(t, cc.Create(ctx, cluster))
+	t.Cleanup(func() { assert.Check(t, client.IgnoreNotFound(cc.Delete(ctx, cluster))) })
+
+	exists := func(t *testing.T) (*policyv1beta1.PodDisruptionBudget, bool) {
+		pdb := &policyv1beta1.PodDisruptionBudget{ObjectMeta: naming.PGBackRestRepoHost(cluster)}
+		err := cc.Get(ctx, client.ObjectKeyFromObject(pdb), pdb)
+		if apierrors.IsNotFound(err) {
+			return nil, false
+		}
+		assert.NilError(t, err)
+		return pdb, true
+	}
+
+	t.Run("Unspecified", func(t *testing.T) {
+		assert.NilError(t, r.reconcileRepoHostPodDisruptionBudget(ctx, cluster))
+
+		_, found := exists(t)
+		assert.Assert(t, !found, "expected no PodDisruptionBudget")
+	})
+
+	t.Run("Specified", func(t *testing.T) {
+		cluster.Spec.Backups.PGBackRest.RepoHost = &v1beta1.PGBackRestRepoHost{
+			MinAvailable: initialize.IntOrStringInt32(1),
+		}
+		assert.NilError(t, r.reconcileRepoHostPodDisruptionBudget(ctx, cluster))
+
+		pdb, found := exists(t)
+		assert.Assert(t, found, "expected a PodDisruptionBudget")
+		assert.DeepEqual(t, pdb.Spec.MinAvailable, initialize.IntOrStringInt32(1))
+		assert.DeepEqual(t, pdb.Spec.Selector.MatchLabels,
+			map[string]string(naming.PGBackRestDedicatedLabels(cluster.Name)))
+		assert.Assert(t, metav1.IsControlledBy(pdb, cluster))
+	})
+
+	t.Run("NoRepoHost", func(t *testing.T) {
+		assert.NilError(t, r.reconcileRepoHostPodDisruptionBudget(ctx, cluster))
+		_, found := exists(t)
+		assert.Assert(t, found, "expected a PodDisruptionBudget")
+
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Backups.PGBackRest.Repos = []v1beta1.PGBackRestRepo{{
+			Name: "repo1", S3: &v1beta1.RepoS3{Bucket: "b", Endpoint: "e", Region: "r"},
+		}}
+		assert.NilError(t, r.reconcileRepoHostPodDisruptionBudget(ctx, cluster))
+
+		_, found = exists(t)
+		assert.Assert(t, !found, "expected no PodDisruptionBudget")
+	})
+}
+
 func TestGenerateRestoreJobIntent(t *testing.T) {
 	env, cc, _ := setupTestEnv(t, ControllerName)
 	t.Cleanup(func() { teardownTestEnv(t, env) })
@@ -2846,13 +3408,13 @@ func TestPrepareForRestore(t *testing.T) {
 	for _, dedicated := range []bool{true, false} {
 		testCases := []struct {
 			desc            string
-			createResources func(t *testing.T, cluster *v1beta1.PostgresCluster) (*batchv1.Job, []corev1.Endpoints)
+			createResources func(t *testing.T, cluster *v1beta1.PostgresCluster) (*batchv1.Job, []client.Object)
 			fakeObserved    *observedInstances
 			result          testResult
 		}{{
 			desc: "remove restore jobs",
 			createResources: func(t *testing.T,
-				cluster *v1beta1.PostgresCluster) (*batchv1.Job, []corev1.Endpoints) {
+				cluster *v1beta1.PostgresCluster) (*batchv1.Job, []client.Object) {
 				job := generateJob(cluster.Name)
 				assert.NilError(t, r.Client.Create(ctx, job))
 				return job, nil
@@ -2870,7 +3432,7 @@ func TestPrepareForRestore(t *testing.T) {
 		}, {
 			desc: "remove patroni endpoints",
 			createResources: func(t *testing.T,
-				cluster *v1beta1.PostgresCluster) (*batchv1.Job, []corev1.Endpoints) {
+				cluster *v1beta1.PostgresCluster) (*batchv1.Job, []client.Object) {
 				fakeLeaderEP := corev1.Endpoints{}
 				fakeLeaderEP.ObjectMeta = naming.PatroniLeaderEndpoints(cluster)
 				fakeLeaderEP.ObjectMeta.Namespace = namespace
@@ -2883,7 +3445,7 @@ func TestPrepareForRestore(t *testing.T) {
 				fakeFailoverEP.ObjectMeta = naming.PatroniTrigger(cluster)
 				fakeFailoverEP.ObjectMeta.Namespace = namespace
 				assert.NilError(t, r.Client.Create(ctx, &fakeFailoverEP))
-				return nil, []corev1.Endpoints{fakeLeaderEP, fakeDCSEP, fakeFailoverEP}
+				return nil, []client.Object{&fakeLeaderEP, &fakeDCSEP, &fakeFailoverEP}
 			},
 			result: testResult{
 				restoreJobExists: false,
@@ -2898,8 +3460,8 @@ func TestPrepareForRestore(t *testing.T) {
 		}, {
 			desc: "cluster fully prepared",
 			createResources: func(t *testing.T,
-				cluster *v1beta1.PostgresCluster) (*batchv1.Job, []corev1.Endpoints) {
-				return nil, []corev1.Endpoints{}
+				cluster *v1beta1.PostgresCluster) (*batchv1.Job, []client.Object) {
+				return nil, []client.Object{}
 			},
 			result: testResult{
 				restoreJobExists: false,
@@ -2923,8 +3485,8 @@ func TestPrepareForRestore(t *testing.T) {
 				}}},
 			}},
 			createResources: func(t *testing.T,
-				cluster *v1beta1.PostgresCluster) (*batchv1.Job, []corev1.Endpoints) {
-				return nil, []corev1.Endpoints{}
+				cluster *v1beta1.PostgresCluster) (*batchv1.Job, []client.Object) {
+				return nil, []client.Object{}
 			},
 			result: testResult{
 				restoreJobExists: false,
@@ -3263,6 +3825,59 @@ func TestReconcileScheduledBackups(t *testing.T) {
 	}
 }
 
+func TestReconcilePGBackRestCronJobOptions(t *testing.T) {
+	ctx := context.Background()
+	env, cc, _ := setupTestEnv(t, ControllerName)
+	t.Cleanup(func() { teardownTestEnv(t, env) })
+
+	ns := &corev1.Namespace{}
+	ns.GenerateName = "postgres-operator-test-"
+	assert.NilError(t, cc.Create(ctx, ns))
+	t.Cleanup(func() { assert.Check(t, cc.Delete(ctx, ns)) })
+
+	r := &Reconciler{
+		Client:   cc,
+		Owner:    client.FieldOwner(t.Name()),
+		Recorder: record.NewFakeRecorder(10),
+	}
+
+	cluster := fakePostgresCluster("hippo", ns.Name, "hippouid", false)
+	cluster.Status.Patroni.SystemIdentifier = "12345abcde"
+	cluster.Status.PGBackRest = &v1beta1.PGBackRestStatus{
+		Repos: []v1beta1.RepoStatus{{Name: "repo1", StanzaCreated: true}},
+	}
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type: ConditionReplicaCreate, Reason: "testing", Status: metav1.ConditionTrue,
+	})
+
+	repo := v1beta1.PGBackRestRepo{
+		Name: "repo1",
+		BackupSchedules: &v1beta1.PGBackRestBackupSchedules{
+			Full:    initialize.String("0 1 * * *"),
+			Suspend: initialize.Bool(true),
+		},
+	}
+	cluster.Spec.Backups.PGBackRest.Repos = []v1beta1.PGBackRestRepo{repo}
+	cluster.Spec.Backups.PGBackRest.Jobs = &v1beta1.BackupJobs{
+		SuccessfulJobsHistoryLimit: initialize.Int32(5),
+		FailedJobsHistoryLimit:     initialize.Int32(2),
+	}
+
+	assert.NilError(t, r.reconcilePGBackRestCronJob(ctx, cluster, repo,
+		full, repo.BackupSchedules.Full, &corev1.ServiceAccount{}))
+
+	cronjob := &batchv1beta1.CronJob{
+		ObjectMeta: naming.PGBackRestCronJob(cluster, full, repo.Name),
+	}
+	assert.NilError(t, cc.Get(ctx, client.ObjectKeyFromObject(cronjob), cronjob))
+
+	assert.Equal(t, cronjob.Spec.Schedule, "0 1 * * *")
+	assert.Equal(t, *cronjob.Spec.Suspend, true)
+	assert.Equal(t, cronjob.Spec.ConcurrencyPolicy, batchv1beta1.ForbidConcurrent)
+	assert.Equal(t, *cronjob.Spec.SuccessfulJobsHistoryLimit, int32(5))
+	assert.Equal(t, *cronjob.Spec.FailedJobsHistoryLimit, int32(2))
+}
+
 func TestSetScheduledJobStatus(t *testing.T) {
 
 	// setup the test environment and ensure a clean teardown
@@ -3361,3 +3976,446 @@ func TestSetScheduledJobStatus(t *testing.T) {
 		assert.Assert(t, len(postgresCluster.Status.PGBackRest.ScheduledBackups) == 0)
 	})
 }
+
+func TestReconcileRestoreVerification(t *testing.T) {
+	ctx := context.Background()
+	env, cc, _ := setupTestEnv(t, ControllerName)
+	t.Cleanup(func() { teardownTestEnv(t, env) })
+
+	ns := &corev1.Namespace{}
+	ns.GenerateName = "postgres-operator-test-"
+	assert.NilError(t, cc.Create(ctx, ns))
+	t.Cleanup(func() { assert.Check(t, cc.Delete(ctx, ns)) })
+
+	recorder := record.NewFakeRecorder(10)
+	r := &Reconciler{
+		Client:   cc,
+		Owner:    client.FieldOwner(t.Name()),
+		Recorder: recorder,
+	}
+
+	cluster := fakePostgresCluster("hippo", ns.Name, "hippouid", false)
+	cluster.Status.Patroni.SystemIdentifier = "12345abcde"
+	cluster.Status.PGBackRest = &v1beta1.PGBackRestStatus{
+		Repos: []v1beta1.RepoStatus{{Name: "repo1", StanzaCreated: true}},
+	}
+	cluster.Spec.Backups.PGBackRest.Repos = []v1beta1.PGBackRestRepo{{
+		Name: "repo1",
+		S3:   &v1beta1.RepoS3{Bucket: "bucket", Endpoint: "endpoint", Region: "region"},
+		Verification: &v1beta1.PGBackRestVerification{
+			Schedule: "0 3 * * 0",
+			VolumeClaimSpec: corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("1Gi"),
+					},
+				},
+			},
+			Database: "app",
+			Checks: []v1beta1.PGBackRestVerificationCheck{
+				{Name: "orders", SQL: "SELECT count(*) > 0 FROM orders"},
+			},
+			Tolerations: []corev1.Toleration{{Key: "verify", Operator: corev1.TolerationOpExists}},
+		},
+	}}
+	cluster.Spec.Backups.PGBackRest.Jobs = &v1beta1.BackupJobs{
+		PriorityClassName: initialize.String("backups"),
+		NodeSelector:      map[string]string{"disktype": "ssd"},
+		Tolerations:       []corev1.Toleration{{Key: "backup", Operator: corev1.TolerationOpExists}},
+	}
+
+	// the cluster must exist to own the CronJob
+	assert.NilError(t, cc.Create(ctx, cluster.DeepCopy()))
+	existing := &v1beta1.PostgresCluster{}
+	assert.NilError(t, cc.Get(ctx, client.ObjectKeyFromObject(cluster), existing))
+	cluster.UID = existing.UID
+
+	result, err := r.reconcileRestoreVerification(ctx, cluster, nil, nil, "hash")
+	assert.NilError(t, err)
+	assert.Equal(t, result, reconcile.Result{})
+
+	cronjob := &batchv1beta1.CronJob{
+		ObjectMeta: naming.PGBackRestVerifyCronJob(cluster, "repo1"),
+	}
+	assert.NilError(t, cc.Get(ctx, client.ObjectKeyFromObject(cronjob), cronjob))
+	assert.Equal(t, cronjob.Spec.Schedule, "0 3 * * 0")
+	assert.Equal(t, cronjob.Labels[naming.LabelPGBackRestVerify], "repo1")
+
+	template := cronjob.Spec.JobTemplate
+	assert.Equal(t, template.Labels[naming.LabelPGBackRestVerify], "repo1")
+	assert.Equal(t, template.Spec.Template.Labels[naming.LabelPGBackRestVerify], "repo1")
+	assert.Assert(t, template.Spec.Template.Labels[naming.LabelPGBackRestRestore] == "")
+	assert.Equal(t, *template.Spec.BackoffLimit, int32(0))
+
+	container := template.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, container.Name, naming.PGBackRestRestoreContainerName)
+	assert.Equal(t, container.TerminationMessagePolicy,
+		corev1.TerminationMessageFallbackToLogsOnError)
+	assert.DeepEqual(t, container.Command[5:], []string{
+		"/pgdata/pg13",
+		"--stanza=db --pg1-path=/pgdata/pg13 --repo=1 --type=immediate" +
+			" --target-action=promote --tablespace-map-all=/pgdata/tablespaces",
+		"app", "orders", "SELECT count(*) > 0 FROM orders",
+	})
+	assert.Equal(t,
+		template.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName,
+		naming.PGBackRestVerifyVolume(cluster, "repo1").Name)
+
+	// scheduling comes from the verification, then from backup Jobs
+	assert.Equal(t, template.Spec.Template.Spec.PriorityClassName, "backups")
+	assert.DeepEqual(t, template.Spec.Template.Spec.NodeSelector,
+		map[string]string{"disktype": "ssd"})
+	assert.DeepEqual(t, template.Spec.Template.Spec.Tolerations,
+		cluster.Spec.Backups.PGBackRest.Repos[0].Verification.Tolerations)
+
+	// the volume exists before any Job and belongs to the cluster
+	volume := &corev1.PersistentVolumeClaim{
+		ObjectMeta: naming.PGBackRestVerifyVolume(cluster, "repo1"),
+	}
+	assert.NilError(t, cc.Get(ctx, client.ObjectKeyFromObject(volume), volume))
+	assert.Equal(t, volume.Labels[naming.LabelPGBackRestVerify], "repo1")
+	assert.Assert(t, isOwnedBy(volume, cluster))
+
+	// create a Job as the CronJob would
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "hippo-pgbackrest-repo1-verify-1234", Namespace: ns.Name,
+			Labels: template.Labels,
+		},
+		Spec: template.Spec,
+	}
+	assert.NilError(t, cc.Create(ctx, job))
+
+	t.Run("Active", func(t *testing.T) {
+		_, err := r.reconcileRestoreVerification(ctx, cluster,
+			[]*batchv1.Job{job}, []*corev1.PersistentVolumeClaim{volume}, "hash")
+		assert.NilError(t, err)
+
+		// the volume now belongs to the Job
+		assert.NilError(t, cc.Get(ctx, client.ObjectKeyFromObject(volume), volume))
+		assert.Assert(t, isOwnedBy(volume, job))
+		assert.Assert(t, !isOwnedBy(volume, cluster))
+		assert.Assert(t, len(cluster.Status.PGBackRest.Verifications) == 0)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		volume := &corev1.PersistentVolumeClaim{
+			ObjectMeta: naming.PGBackRestVerifyVolume(cluster, "repo1"),
+		}
+		assert.NilError(t, cc.Get(ctx, client.ObjectKeyFromObject(volume), volume))
+
+		start := metav1.Now()
+		job.Status.StartTime = &start
+		job.Status.Conditions = []batchv1.JobCondition{{
+			Type: batchv1.JobFailed, Status: corev1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(start.Add(90 * time.Second)),
+			Message:            "Job has reached the specified backoff limit",
+		}}
+		assert.NilError(t, cc.Status().Update(ctx, job))
+
+		result, err := r.reconcileRestoreVerification(ctx, cluster,
+			[]*batchv1.Job{job}, []*corev1.PersistentVolumeClaim{volume}, "hash")
+		assert.NilError(t, err)
+		assert.Equal(t, result.RequeueAfter, 5*time.Second)
+
+		assert.Assert(t, len(cluster.Status.PGBackRest.Verifications) == 1)
+		status := cluster.Status.PGBackRest.Verifications[0]
+		assert.Equal(t, status.RepoName, "repo1")
+		assert.Equal(t, status.JobName, job.Name)
+		assert.Equal(t, status.Succeeded, false)
+		assert.Equal(t, status.Duration.Duration, 90*time.Second)
+		assert.Equal(t, status.Message, "Job has reached the specified backoff limit")
+
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Assert(t, strings.Contains(<-recorder.Events, "RestoreVerificationFailed"))
+
+		// the Job and its volume are deleted
+		err = cc.Get(ctx, client.ObjectKeyFromObject(job), &batchv1.Job{})
+		assert.Assert(t, apierrors.IsNotFound(err) || err == nil)
+		latest := &corev1.PersistentVolumeClaim{}
+		err = cc.Get(ctx, client.ObjectKeyFromObject(volume), latest)
+		assert.Assert(t, apierrors.IsNotFound(err) || latest.DeletionTimestamp != nil)
+
+		// a finished Job is recorded only once
+		_, err = r.reconcileRestoreVerification(ctx, cluster,
+			[]*batchv1.Job{job}, nil, "hash")
+		assert.NilError(t, err)
+		assert.Equal(t, len(recorder.Events), 0)
+	})
+
+	t.Run("Replaced", func(t *testing.T) {
+		// PVC protection keeps the volume until no Pod uses it.
+		latest := &corev1.PersistentVolumeClaim{}
+		if err := cc.Get(ctx, client.ObjectKeyFromObject(volume), latest); err == nil &&
+			latest.DeletionTimestamp != nil {
+			latest.Finalizers = nil
+			assert.NilError(t, cc.Update(ctx, latest))
+		}
+
+		_, err := r.reconcileRestoreVerification(ctx, cluster, nil, nil, "hash")
+		assert.NilError(t, err)
+
+		// a fresh volume waits for the next Job
+		latest = &corev1.PersistentVolumeClaim{}
+		assert.NilError(t, cc.Get(ctx, client.ObjectKeyFromObject(volume), latest))
+		assert.Assert(t, !isOwnedBy(latest, job))
+		assert.Assert(t, isOwnedBy(latest, cluster))
+	})
+
+	t.Run("Removed", func(t *testing.T) {
+		removed := cluster.DeepCopy()
+		removed.Spec.Backups.PGBackRest.Repos[0].Verification = nil
+
+		_, err := r.reconcileRestoreVerification(ctx, removed, nil, nil, "hash")
+		assert.NilError(t, err)
+		assert.Assert(t, len(removed.Status.PGBackRest.Verifications) == 0)
+	})
+}
+
+func TestObserveBackupStandby(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	r := &Reconciler{Recorder: recorder}
+
+	pod := func(role string, ready corev1.ConditionStatus) *corev1.Pod {
+		pod := &corev1.Pod{}
+		pod.Labels = map[string]string{naming.LabelRole: role}
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}}
+		return pod
+	}
+	instances := &observedInstances{forCluster: []*Instance{
+		{Name: "hippo-a", Pods: []*corev1.Pod{pod(naming.RolePatroniLeader, corev1.ConditionTrue)}},
+		{Name: "hippo-c", Pods: []*corev1.Pod{pod("replica", corev1.ConditionFalse)}},
+	}}
+
+	cluster := &v1beta1.PostgresCluster{}
+	cluster.Spec.Backups.PGBackRest.Repos = []v1beta1.PGBackRestRepo{
+		{Name: "repo1", Volume: &v1beta1.RepoPVC{}},
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type: ConditionBackupStandbyAvailable, Status: metav1.ConditionTrue, Reason: "x",
+		})
+
+		r.observeBackupStandby(cluster, instances)
+		assert.Assert(t, meta.FindStatusCondition(cluster.Status.Conditions,
+			ConditionBackupStandbyAvailable) == nil)
+	})
+
+	t.Run("RepoHostRequired", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Backups.PGBackRest.Repos[0].Volume = nil
+		cluster.Spec.Backups.PGBackRest.BackupFromStandby =
+			&v1beta1.PGBackRestBackupFromStandby{Enabled: true}
+
+		r.observeBackupStandby(cluster, instances)
+		condition := meta.FindStatusCondition(cluster.Status.Conditions,
+			ConditionBackupStandbyAvailable)
+		assert.Assert(t, condition != nil)
+		assert.Equal(t, condition.Status, metav1.ConditionFalse)
+		assert.Equal(t, condition.Reason, "RepoHostRequired")
+		assert.Assert(t, strings.Contains(<-recorder.Events, "BackupStandbyUnavailable"))
+	})
+
+	t.Run("NoReplicaAvailable", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Backups.PGBackRest.BackupFromStandby =
+			&v1beta1.PGBackRestBackupFromStandby{Enabled: true, Fallback: "Fail"}
+
+		r.observeBackupStandby(cluster, instances)
+		condition := meta.FindStatusCondition(cluster.Status.Conditions,
+			ConditionBackupStandbyAvailable)
+		assert.Assert(t, condition != nil)
+		assert.Equal(t, condition.Status, metav1.ConditionFalse)
+		assert.Equal(t, condition.Reason, "NoReplicaAvailable")
+		assert.Assert(t, strings.Contains(condition.Message, "fail"))
+		assert.Assert(t, strings.Contains(<-recorder.Events, "BackupStandbyUnavailable"))
+
+		// the Event is recorded once
+		r.observeBackupStandby(cluster, instances)
+		assert.Equal(t, len(recorder.Events), 0)
+	})
+
+	t.Run("ReplicaAvailable", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Backups.PGBackRest.BackupFromStandby =
+			&v1beta1.PGBackRestBackupFromStandby{Enabled: true}
+
+		instances := &observedInstances{forCluster: append(instances.forCluster,
+			&Instance{Name: "hippo-d", Pods: []*corev1.Pod{pod("replica", corev1.ConditionTrue)}},
+			&Instance{Name: "hippo-b", Pods: []*corev1.Pod{pod("replica", corev1.ConditionTrue)}},
+		)}
+
+		r.observeBackupStandby(cluster, instances)
+		condition := meta.FindStatusCondition(cluster.Status.Conditions,
+			ConditionBackupStandbyAvailable)
+		assert.Assert(t, condition != nil)
+		assert.Equal(t, condition.Status, metav1.ConditionTrue)
+		assert.Assert(t, strings.Contains(condition.Message, "replicas: hippo-b, hippo-d."),
+			"got %q", condition.Message)
+		assert.Equal(t, len(recorder.Events), 0)
+	})
+}
+
+func TestReconcileArchiving(t *testing.T) {
+	ctx := context.Background()
+	recorder := record.NewFakeRecorder(10)
+
+	var archiver string
+	var checks int
+	r := &Reconciler{
+		Recorder: recorder,
+		PodExec: func(
+			namespace, pod, container string,
+			_ io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			assert.Equal(t, namespace, "ns1")
+			assert.Equal(t, pod, "hippo-a-0")
+			assert.Equal(t, container, naming.ContainerDatabase)
+
+			if command[0] == "pgbackrest" {
+				checks++
+				assert.DeepEqual(t, command, strings.Fields(
+					"pgbackrest check --stanza=db --archive-timeout=5"))
+				_, _ = stderr.Write([]byte("ERROR: [039]: HTTP request failed with 403 (Forbidden)\n"))
+				return errors.New("exit status 39")
+			}
+			_, _ = stdout.Write([]byte(archiver))
+			return nil
+		},
+	}
+
+	primary := &corev1.Pod{}
+	primary.Namespace, primary.Name = "ns1", "hippo-a-0"
+	primary.Annotations = map[string]string{"status": `{"role":"master"}`}
+	primary.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name: naming.ContainerDatabase,
+	}}
+	primary.Status.ContainerStatuses[0].State.Running = new(corev1.ContainerStateRunning)
+	instances := &observedInstances{forCluster: []*Instance{
+		{Name: "hippo-a", Pods: []*corev1.Pod{primary}},
+	}}
+
+	cluster := &v1beta1.PostgresCluster{}
+	cluster.Status.PGBackRest = &v1beta1.PGBackRestStatus{}
+
+	condition := func() *metav1.Condition {
+		return meta.FindStatusCondition(cluster.Status.Conditions, ConditionArchivingHealthy)
+	}
+
+	// expire pretends the interval has passed since archiving was observed.
+	expire := func() {
+		past := metav1.NewTime(time.Now().Add(-archivingInterval))
+		cluster.Status.PGBackRest.Archiving.ObservedTime = &past
+	}
+
+	t.Run("NoPrimary", func(t *testing.T) {
+		result := r.reconcileArchiving(ctx, cluster, &observedInstances{})
+		assert.Equal(t, result, reconcile.Result{})
+		assert.Assert(t, condition() == nil)
+	})
+
+	t.Run("Healthy", func(t *testing.T) {
+		archiver = `{"archived_count" : 5, "last_archived_wal" : "000000010000000000000005", ` +
+			`"last_archived_time" : "2021-06-01T12:00:00+00:00", "failed_count" : 0, ` +
+			`"last_failed_wal" : null, "last_failed_time" : null, "ready_count" : 0}`
+
+		result := r.reconcileArchiving(ctx, cluster, instances)
+		assert.Equal(t, result.RequeueAfter, archivingInterval)
+		assert.Equal(t, condition().Status, metav1.ConditionTrue)
+		assert.Equal(t, cluster.Status.PGBackRest.Archiving.ArchivedCount, int64(5))
+		assert.Equal(t, cluster.Status.PGBackRest.Archiving.LastArchivedWAL, "000000010000000000000005")
+		assert.Assert(t, cluster.Status.PGBackRest.Archiving.ObservedTime != nil)
+		assert.Equal(t, checks, 0)
+		assert.Equal(t, len(recorder.Events), 0)
+	})
+
+	t.Run("Interval", func(t *testing.T) {
+		status := cluster.Status.PGBackRest.Archiving.DeepCopy()
+		archiver = "not json"
+
+		// Archiving is not observed again until the interval passes.
+		result := r.reconcileArchiving(ctx, cluster, instances)
+		assert.Assert(t, result.RequeueAfter > 0)
+		assert.Assert(t, result.RequeueAfter <= archivingInterval)
+		assert.DeepEqual(t, cluster.Status.PGBackRest.Archiving, status)
+
+		// A failed observation keeps the previous status and waits another interval.
+		expire()
+		result = r.reconcileArchiving(ctx, cluster, instances)
+		assert.Equal(t, result.RequeueAfter, archivingInterval)
+		assert.Equal(t, cluster.Status.PGBackRest.Archiving.ArchivedCount, int64(5))
+		assert.Assert(t, !cluster.Status.PGBackRest.Archiving.ObservedTime.Before(status.ObservedTime))
+		assert.Equal(t, condition().Status, metav1.ConditionTrue)
+	})
+
+	t.Run("Failing", func(t *testing.T) {
+		archiver = `{"archived_count" : 5, "last_archived_wal" : "000000010000000000000005", ` +
+			`"last_archived_time" : "2021-06-01T12:00:00+00:00", "failed_count" : 2, ` +
+			`"last_failed_wal" : "000000010000000000000006", ` +
+			`"last_failed_time" : "2021-06-01T12:01:00+00:00", "ready_count" : 3}`
+
+		expire()
+		r.reconcileArchiving(ctx, cluster, instances)
+		assert.Equal(t, condition().Status, metav1.ConditionFalse)
+		assert.Equal(t, condition().Reason, "ArchivingFailed")
+		assert.Assert(t, strings.Contains(condition().Message, "403 (Forbidden)"))
+		assert.Equal(t, cluster.Status.PGBackRest.Archiving.LastFailedWAL, "000000010000000000000006")
+		assert.Equal(t, cluster.Status.PGBackRest.Archiving.ReadyCount, int64(3))
+		assert.Assert(t, cluster.Status.PGBackRest.Archiving.CheckTime != nil)
+		assert.Equal(t, checks, 1)
+
+		event := <-recorder.Events
+		assert.Assert(t, strings.HasPrefix(event, "Warning ArchivingFailed"), "got %q", event)
+
+		// The check does not run again right away, but its answer is kept.
+		// No more Events are recorded while archiving continues to fail, and
+		// the message does not change as more WAL files wait.
+		message := condition().Message
+		archiver = strings.Replace(archiver, `"ready_count" : 3`, `"ready_count" : 4`, 1)
+		expire()
+		r.reconcileArchiving(ctx, cluster, instances)
+		assert.Equal(t, checks, 1)
+		assert.Equal(t, condition().Message, message)
+		assert.Equal(t, cluster.Status.PGBackRest.Archiving.ReadyCount, int64(4))
+		assert.Equal(t, len(recorder.Events), 0)
+
+		// The check runs again after a while.
+		past := metav1.NewTime(time.Now().Add(-archivingCheckInterval))
+		cluster.Status.PGBackRest.Archiving.CheckTime = &past
+		expire()
+		r.reconcileArchiving(ctx, cluster, instances)
+		assert.Equal(t, checks, 2)
+	})
+
+	t.Run("Recovered", func(t *testing.T) {
+		archiver = `{"archived_count" : 9, "last_archived_wal" : "000000010000000000000009", ` +
+			`"last_archived_time" : "2021-06-01T12:10:00+00:00", "failed_count" : 2, ` +
+			`"last_failed_wal" : "000000010000000000000006", ` +
+			`"last_failed_time" : "2021-06-01T12:01:00+00:00", "ready_count" : 0}`
+
+		expire()
+		r.reconcileArchiving(ctx, cluster, instances)
+		assert.Equal(t, condition().Status, metav1.ConditionTrue)
+
+		event := <-recorder.Events
+		assert.Assert(t, strings.HasPrefix(event, "Normal ArchivingRecovered"), "got %q", event)
+	})
+
+	t.Run("Standby", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Standby = &v1beta1.PostgresStandbySpec{Enabled: true}
+
+		r.reconcileArchiving(ctx, cluster, instances)
+		assert.Assert(t, meta.FindStatusCondition(cluster.Status.Conditions,
+			ConditionArchivingHealthy) == nil)
+		assert.Assert(t, cluster.Status.PGBackRest.Archiving == nil)
+
+		// Nothing to remove.
+		cluster.Status.Conditions = nil
+		r.reconcileArchiving(ctx, cluster, instances)
+		assert.Assert(t, cluster.Status.Conditions == nil)
+	})
+}
@@ -58,6 +58,9 @@ func (r *Reconciler) reconcilePGBouncer(
 	if err == nil {
 		err = r.reconcilePGBouncerDeployment(ctx, cluster, primaryCertificate, configmap, secret)
 	}
+	if err == nil {
+		err = r.reconcilePGBouncerPodDisruptionBudget(ctx, cluster)
+	}
 	if err == nil {
 		err = r.reconcilePGBouncerInPostgreSQL(ctx, cluster, instances, secret)
 	}
@@ -474,3 +477,26 @@ func (r *Reconciler) reconcilePGBouncerDeployment(
 
 	return err
 }
+
+// reconcilePGBouncerPodDisruptionBudget writes the PodDisruptionBudget that
+// limits voluntary evictions of PgBouncer Pods.
+func (r *Reconciler) reconcilePGBouncerPodDisruptionBudget(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+) error {
+	var metadata *v1beta1.Metadata
+	var minAvailable *intstr.IntOrString
+	var replicas int32
+
+	// PgBouncer may be disabled; then its PodDisruptionBudget is deleted.
+	if cluster.Spec.Proxy != nil && cluster.Spec.Proxy.PGBouncer != nil {
+		metadata = cluster.Spec.Proxy.PGBouncer.Metadata
+		minAvailable = cluster.Spec.Proxy.PGBouncer.MinAvailable
+		replicas = *cluster.Spec.Proxy.PGBouncer.Replicas
+	}
+
+	return r.reconcileComponentPodDisruptionBudget(ctx, cluster,
+		naming.ClusterPGBouncer(cluster), map[string]string{
+			naming.LabelCluster: cluster.Name,
+			naming.LabelRole:    naming.RolePGBouncer,
+		}, metadata, replicas, minAvailable)
+}
@@ -26,6 +26,7 @@ import (
 	"gotest.tools/v3/assert"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -33,6 +34,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crunchydata/postgres-operator/internal/initialize"
+	"github.com/crunchydata/postgres-operator/internal/naming"
 	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
 
@@ -365,3 +367,86 @@ func TestReconcilePGBouncerDeployment(t *testing.T) {
 	})
 
 }
+
+func TestReconcilePGBouncerPodDisruptionBudget(t *testing.T) {
+	ctx := context.Background()
+	env, cc, _ := setupTestEnv(t, ControllerName)
+	t.Cleanup(func() { teardownTestEnv(t, env) })
+
+	ns := &corev1.Namespace{}
+	ns.GenerateName = "postgres-operator-test-"
+	assert.NilError(t, cc.Create(ctx, ns))
+	t.Cleanup(func() { assert.Check(t, cc.Delete(ctx, ns)) })
+
+	r := &Reconciler{Client: cc, Owner: client.FieldOwner(t.Name())}
+
+	cluster := fakePostgresCluster("hippo", ns.Name, "", false)
+	assert.NilError(t, cc.Create(ctx, cluster))
+	t.Cleanup(func() { assert.Check(t, client.IgnoreNotFound(cc.Delete(ctx, cluster))) })
+
+	exists := func(t *testing.T) (*policyv1beta1.PodDisruptionBudget, bool) {
+		pdb := &policyv1beta1.PodDisruptionBudget{ObjectMeta: naming.ClusterPGBouncer(cluster)}
+		err := cc.Get(ctx, client.ObjectKeyFromObject(pdb), pdb)
+		if apierrors.IsNotFound(err) {
+			return nil, false
+		}
+		assert.NilError(t, err)
+		return pdb, true
+	}
+
+	t.Run("Unspecified", func(t *testing.T) {
+		assert.NilError(t, r.reconcilePGBouncerPodDisruptionBudget(ctx, cluster))
+
+		_, found := exists(t)
+		assert.Assert(t, !found, "expected no PodDisruptionBudget")
+	})
+
+	t.Run("OneReplica", func(t *testing.T) {
+		cluster.Spec.Proxy = &v1beta1.PostgresProxySpec{
+			PGBouncer: &v1beta1.PGBouncerPodSpec{Replicas: initialize.Int32(1)},
+		}
+		assert.NilError(t, r.reconcilePGBouncerPodDisruptionBudget(ctx, cluster))
+
+		_, found := exists(t)
+		assert.Assert(t, !found, "expected no PodDisruptionBudget")
+	})
+
+	t.Run("Replicas", func(t *testing.T) {
+		cluster.Spec.Proxy.PGBouncer.Replicas = initialize.Int32(2)
+		cluster.Spec.Proxy.PGBouncer.Metadata = &v1beta1.Metadata{
+			Labels: map[string]string{"some": "label"},
+		}
+		assert.NilError(t, r.reconcilePGBouncerPodDisruptionBudget(ctx, cluster))
+
+		pdb, found := exists(t)
+		assert.Assert(t, found, "expected a PodDisruptionBudget")
+		assert.DeepEqual(t, pdb.Spec.MinAvailable, initialize.IntOrStringInt32(1))
+		assert.DeepEqual(t, pdb.Spec.Selector.MatchLabels, map[string]string{
+			naming.LabelCluster: "hippo",
+			naming.LabelRole:    naming.RolePGBouncer,
+		})
+		assert.Equal(t, pdb.Labels["some"], "label")
+		assert.Assert(t, metav1.IsControlledBy(pdb, cluster))
+	})
+
+	t.Run("Shutdown", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Shutdown = initialize.Bool(true)
+		assert.NilError(t, r.reconcilePGBouncerPodDisruptionBudget(ctx, cluster))
+
+		_, found := exists(t)
+		assert.Assert(t, !found, "expected no PodDisruptionBudget")
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		assert.NilError(t, r.reconcilePGBouncerPodDisruptionBudget(ctx, cluster))
+		_, found := exists(t)
+		assert.Assert(t, found, "expected a PodDisruptionBudget")
+
+		cluster.Spec.Proxy = nil
+		assert.NilError(t, r.reconcilePGBouncerPodDisruptionBudget(ctx, cluster))
+
+		_, found = exists(t)
+		assert.Assert(t, !found, "expected no PodDisruptionBudget")
+	})
+}
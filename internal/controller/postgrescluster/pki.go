@@ -33,8 +33,39 @@ const (
 	clusterCertFile = "tls.crt"
 	clusterKeyFile  = "tls.key"
 	rootCertFile    = "ca.crt"
+
+	// Keys of the Secret that stores the root certificate authority.
+	rootSecretCertificateKey = "root.crt"
+	rootSecretPrivateKeyKey  = "root.key"
 )
 
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get
+
+// getRootCertificate reads the root certificate authority that is stored in
+// the namespace of cluster. Unlike reconcileRootCertificate, it never
+// generates or writes one.
+func (r *Reconciler) getRootCertificate(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+) (*pki.RootCertificateAuthority, error) {
+	existing := &corev1.Secret{}
+	existing.Namespace, existing.Name = cluster.Namespace, naming.RootCertSecret
+	err := errors.WithStack(
+		r.Client.Get(ctx, client.ObjectKeyFromObject(existing), existing))
+
+	root := pki.NewRootCertificateAuthority()
+
+	if err == nil {
+		root.Certificate, err = pki.ParseCertificate(existing.Data[rootSecretCertificateKey])
+		err = errors.WithStack(err)
+	}
+	if err == nil {
+		root.PrivateKey, err = pki.ParsePrivateKey(existing.Data[rootSecretPrivateKeyKey])
+		err = errors.WithStack(err)
+	}
+
+	return root, err
+}
+
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=create;patch
 
@@ -48,8 +79,6 @@ func (r *Reconciler) reconcileRootCertificate(
 ) (
 	*pki.RootCertificateAuthority, error,
 ) {
-	const keyCertificate, keyPrivateKey = "root.crt", "root.key"
-
 	existing := &corev1.Secret{}
 	existing.Namespace, existing.Name = cluster.Namespace, naming.RootCertSecret
 	err := errors.WithStack(client.IgnoreNotFound(
@@ -57,11 +86,11 @@ func (r *Reconciler) reconcileRootCertificate(
 
 	root := pki.NewRootCertificateAuthority()
 
-	if data, ok := existing.Data[keyCertificate]; err == nil && ok {
+	if data, ok := existing.Data[rootSecretCertificateKey]; err == nil && ok {
 		root.Certificate, err = pki.ParseCertificate(data)
 		err = errors.WithStack(err)
 	}
-	if data, ok := existing.Data[keyPrivateKey]; err == nil && ok {
+	if data, ok := existing.Data[rootSecretPrivateKeyKey]; err == nil && ok {
 		root.PrivateKey, err = pki.ParsePrivateKey(data)
 		err = errors.WithStack(err)
 	}
@@ -90,11 +119,11 @@ func (r *Reconciler) reconcileRootCertificate(
 		err = errors.WithStack(r.setOwnerReference(cluster, intent))
 	}
 	if err == nil {
-		intent.Data[keyCertificate], err = root.Certificate.MarshalText()
+		intent.Data[rootSecretCertificateKey], err = root.Certificate.MarshalText()
 		err = errors.WithStack(err)
 	}
 	if err == nil {
-		intent.Data[keyPrivateKey], err = root.PrivateKey.MarshalText()
+		intent.Data[rootSecretPrivateKeyKey], err = root.PrivateKey.MarshalText()
 		err = errors.WithStack(err)
 	}
 	if err == nil {
@@ -0,0 +1,126 @@
+/*
+ Copyright 2021 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package postgrescluster
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crunchydata/postgres-operator/internal/initialize"
+	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+// generatePodDisruptionBudget returns a PodDisruptionBudget that keeps
+// minAvailable of the Pods matching selector running. It is controlled by
+// cluster.
+func (r *Reconciler) generatePodDisruptionBudget(
+	cluster *v1beta1.PostgresCluster,
+	meta metav1.ObjectMeta,
+	minAvailable *intstr.IntOrString,
+	selector metav1.LabelSelector,
+) (*policyv1beta1.PodDisruptionBudget, error) {
+	pdb := &policyv1beta1.PodDisruptionBudget{ObjectMeta: meta}
+	pdb.SetGroupVersionKind(policyv1beta1.SchemeGroupVersion.WithKind("PodDisruptionBudget"))
+
+	pdb.Spec.MinAvailable = minAvailable
+	pdb.Spec.Selector = &selector
+
+	err := errors.WithStack(r.setControllerReference(cluster, pdb))
+
+	return pdb, err
+}
+
+// getMinAvailable returns minAvailable when it is set. Otherwise, it returns
+// one when there is more than one replica and zero when there is not. A lone
+// Pod should never be protected by default because that would prevent it
+// from ever being evicted.
+func getMinAvailable(minAvailable *intstr.IntOrString, replicas int32) *intstr.IntOrString {
+	if minAvailable != nil {
+		return minAvailable
+	}
+	if replicas > 1 {
+		return initialize.IntOrStringInt32(1)
+	}
+	return initialize.IntOrStringInt32(0)
+}
+
+// +kubebuilder:rbac:groups="policy",resources="poddisruptionbudgets",verbs={get}
+// +kubebuilder:rbac:groups="policy",resources="poddisruptionbudgets",verbs={create,delete,patch}
+
+// reconcilePodDisruptionBudget writes pdb when its minimum resolves to more
+// than zero of replicas. Otherwise, it deletes pdb if it exists.
+func (r *Reconciler) reconcilePodDisruptionBudget(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+	pdb *policyv1beta1.PodDisruptionBudget, replicas int32,
+) error {
+	scaled, err := intstr.GetScaledValueFromIntOrPercent(
+		pdb.Spec.MinAvailable, int(replicas), true)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if scaled <= 0 {
+		// Nothing should be protected; delete the PodDisruptionBudget if it
+		// exists. Check the client cache first using Get.
+		key := client.ObjectKeyFromObject(pdb)
+		err := errors.WithStack(r.Client.Get(ctx, key, pdb))
+		if err == nil {
+			err = errors.WithStack(r.deleteControlled(ctx, cluster, pdb))
+		}
+		return client.IgnoreNotFound(err)
+	}
+
+	return errors.WithStack(r.apply(ctx, pdb))
+}
+
+// reconcileComponentPodDisruptionBudget writes the PodDisruptionBudget named
+// by meta that limits voluntary evictions of the replicas Pods matching
+// labels. The PodDisruptionBudget gets those labels along with the metadata
+// of cluster and of the component. See getMinAvailable for how the minimum is
+// determined when it is not specified. A component that is disabled has no
+// replicas and no minimum, so its PodDisruptionBudget is deleted.
+func (r *Reconciler) reconcileComponentPodDisruptionBudget(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+	meta metav1.ObjectMeta, labels map[string]string, metadata *v1beta1.Metadata,
+	replicas int32, minAvailable *intstr.IntOrString,
+) error {
+	meta.Annotations = naming.Merge(
+		cluster.Spec.Metadata.GetAnnotationsOrNil(),
+		metadata.GetAnnotationsOrNil())
+	meta.Labels = naming.Merge(
+		cluster.Spec.Metadata.GetLabelsOrNil(),
+		metadata.GetLabelsOrNil(),
+		labels)
+
+	// There is nothing to protect while the cluster is shutdown.
+	if cluster.Spec.Shutdown != nil && *cluster.Spec.Shutdown {
+		replicas = 0
+	}
+
+	pdb, err := r.generatePodDisruptionBudget(cluster, meta,
+		getMinAvailable(minAvailable, replicas),
+		metav1.LabelSelector{MatchLabels: labels})
+	if err == nil {
+		err = r.reconcilePodDisruptionBudget(ctx, cluster, pdb, replicas)
+	}
+	return err
+}
@@ -0,0 +1,41 @@
+/*
+ Copyright 2021 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package postgrescluster
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/crunchydata/postgres-operator/internal/initialize"
+)
+
+func TestGetMinAvailable(t *testing.T) {
+	t.Run("Unset", func(t *testing.T) {
+		assert.DeepEqual(t, *getMinAvailable(nil, 0), intstr.FromInt(0))
+		assert.DeepEqual(t, *getMinAvailable(nil, 1), intstr.FromInt(0))
+		assert.DeepEqual(t, *getMinAvailable(nil, 2), intstr.FromInt(1))
+		assert.DeepEqual(t, *getMinAvailable(nil, 5), intstr.FromInt(1))
+	})
+
+	t.Run("Explicit", func(t *testing.T) {
+		percent := intstr.FromString("50%")
+		assert.DeepEqual(t, *getMinAvailable(&percent, 1), percent)
+		assert.DeepEqual(t, *getMinAvailable(initialize.IntOrStringInt32(3), 2),
+			intstr.FromInt(3))
+	})
+}
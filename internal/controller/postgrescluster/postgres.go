@@ -24,6 +24,7 @@ import (
 	"net/url"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
@@ -32,6 +33,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/crunchydata/postgres-operator/internal/initialize"
 	"github.com/crunchydata/postgres-operator/internal/logging"
@@ -248,6 +250,13 @@ func (r *Reconciler) reconcilePostgresDatabases(
 				"Unable to install PostGIS")
 		}
 
+		// Tablespaces are created only when every instance can store them.
+		if tablespaces := postgres.Tablespaces(cluster); len(tablespaces) > 0 {
+			if err := postgres.CreateTablespacesInPostgreSQL(ctx, exec, tablespaces); err != nil {
+				return err
+			}
+		}
+
 		return postgres.CreateDatabasesInPostgreSQL(ctx, exec, databases.List())
 	}
 
@@ -546,6 +555,7 @@ func (r *Reconciler) reconcilePostgresDataVolume(
 	)
 
 	pvc.Spec = instanceSpec.DataVolumeClaimSpec
+	keepVolumeRequest(pvc, findVolume(clusterVolumes, pvc.Name))
 
 	if err == nil {
 		err = r.handlePersistentVolumeClaimError(cluster,
@@ -649,6 +659,7 @@ func (r *Reconciler) reconcilePostgresWALVolume(
 	)
 
 	pvc.Spec = *instanceSpec.WALVolumeClaimSpec
+	keepVolumeRequest(pvc, findVolume(clusterVolumes, pvc.Name))
 
 	if err == nil {
 		err = r.handlePersistentVolumeClaimError(cluster,
@@ -658,6 +669,58 @@ func (r *Reconciler) reconcilePostgresWALVolume(
 	return pvc, err
 }
 
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=create;patch
+
+// reconcilePostgresTablespaceVolumes writes the PersistentVolumeClaims for
+// instance's PostgreSQL tablespaces. It returns them keyed by tablespace name.
+func (r *Reconciler) reconcilePostgresTablespaceVolumes(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+	instanceSpec *v1beta1.PostgresInstanceSetSpec, instance *appsv1.StatefulSet,
+) (map[string]*corev1.PersistentVolumeClaim, error) {
+	var err error
+	volumes := make(map[string]*corev1.PersistentVolumeClaim)
+
+	for i := range instanceSpec.TablespaceVolumes {
+		tablespace := &instanceSpec.TablespaceVolumes[i]
+
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: naming.InstanceTablespaceVolume(instance, tablespace.Name),
+		}
+		pvc.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim"))
+
+		if err == nil {
+			err = errors.WithStack(r.setControllerReference(cluster, pvc))
+		}
+
+		pvc.Annotations = naming.Merge(
+			cluster.Spec.Metadata.GetAnnotationsOrNil(),
+			instanceSpec.Metadata.GetAnnotationsOrNil())
+
+		pvc.Labels = naming.Merge(
+			cluster.Spec.Metadata.GetLabelsOrNil(),
+			instanceSpec.Metadata.GetLabelsOrNil(),
+			map[string]string{
+				naming.LabelCluster:     cluster.Name,
+				naming.LabelInstanceSet: instanceSpec.Name,
+				naming.LabelInstance:    instance.Name,
+				naming.LabelRole:        naming.RolePostgresTablespace,
+				naming.LabelData:        naming.DataPostgres,
+			},
+		)
+
+		pvc.Spec = tablespace.DataVolumeClaimSpec
+
+		if err == nil {
+			err = r.handlePersistentVolumeClaimError(cluster,
+				errors.WithStack(r.apply(ctx, pvc)))
+		}
+
+		volumes[tablespace.Name] = pvc
+	}
+
+	return volumes, err
+}
+
 // reconcileDatabaseInitSQL runs custom SQL files in the database. When
 // DatabaseInitSQL is defined, the function will find the primary pod and run
 // SQL from the defined ConfigMap
@@ -756,3 +819,85 @@ func (r *Reconciler) reconcileDatabaseInitSQL(ctx context.Context,
 
 	return err
 }
+
+// replayDelayInterval is how often the replay delay of instances is measured
+// when any instance set of a cluster is configured with a delay.
+const replayDelayInterval = time.Minute
+
+// +kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
+
+// reconcileReplayDelay measures the replay delay of instances in sets that
+// have recoveryMinApplyDelay and reports the greatest of each set in status.
+// It measures each set at most once every replayDelayInterval and requeues so
+// that the delay is measured again later.
+func (r *Reconciler) reconcileReplayDelay(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, instances *observedInstances,
+) reconcile.Result {
+	const container = naming.ContainerDatabase
+	var result reconcile.Result
+
+	for i := range cluster.Status.InstanceSets {
+		status := &cluster.Status.InstanceSets[i]
+
+		delayed := false
+		for _, instance := range instances.bySet[status.Name] {
+			delayed = delayed ||
+				(instance.Spec != nil && instance.Spec.RecoveryMinApplyDelay != nil)
+		}
+		if !delayed {
+			status.ReplayDelay, status.ReplayDelayObservedTime = nil, nil
+			continue
+		}
+
+		// Every change to the cluster or its status triggers a reconcile.
+		// Measure only when the interval has passed since the last time.
+		if observed := status.ReplayDelayObservedTime; observed != nil {
+			if elapsed := time.Since(observed.Time); elapsed < replayDelayInterval {
+				result = updateReconcileResult(result,
+					reconcile.Result{RequeueAfter: replayDelayInterval - elapsed})
+				continue
+			}
+		}
+
+		now := metav1.Now()
+		status.ReplayDelayObservedTime = &now
+		result = updateReconcileResult(result,
+			reconcile.Result{RequeueAfter: replayDelayInterval})
+
+		var greatest *metav1.Duration
+		for _, instance := range instances.bySet[status.Name] {
+			if instance.Spec == nil || instance.Spec.RecoveryMinApplyDelay == nil {
+				continue
+			}
+			if len(instance.Pods) != 1 {
+				continue
+			}
+			if running, known := instance.IsRunning(container); !running || !known {
+				continue
+			}
+
+			pod := instance.Pods[0]
+			delay, err := postgres.ReplayDelay(ctx, func(
+				_ context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+			) error {
+				return r.PodExec(pod.Namespace, pod.Name, container, stdin, stdout, stderr, command...)
+			})
+
+			// NOTE: Calling PodExec may fail while PostgreSQL is starting or
+			// stopping. That is not an error for the cluster; try again later.
+			if err != nil {
+				logging.FromContext(ctx).V(1).Info("unable to measure replay delay",
+					"pod", pod.Name, "error", err.Error())
+				greatest = status.ReplayDelay
+				break
+			}
+			if greatest == nil || delay > greatest.Duration {
+				greatest = &metav1.Duration{Duration: delay.Round(time.Second)}
+			}
+		}
+
+		status.ReplayDelay = greatest
+	}
+
+	return result
+}
@@ -22,6 +22,7 @@ import (
 	"context"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
@@ -398,6 +399,71 @@ volumeMode: Filesystem
 			})
 		})
 	})
+
+	t.Run("PGBackRestSpoolVolume", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+
+		t.Run("None", func(t *testing.T) {
+			pvc, err := reconciler.reconcilePGBackRestSpoolVolume(ctx, cluster, spec, instance)
+			assert.NilError(t, err)
+			assert.Assert(t, pvc == nil)
+
+			// An emptyDir spool does not need a PVC.
+			cluster.Spec.Backups.PGBackRest.ArchiveAsync = &v1beta1.PGBackRestArchiveAsync{}
+			pvc, err = reconciler.reconcilePGBackRestSpoolVolume(ctx, cluster, spec, instance)
+			assert.NilError(t, err)
+			assert.Assert(t, pvc == nil)
+		})
+
+		t.Run("Specified", func(t *testing.T) {
+			assert.NilError(t, yaml.Unmarshal([]byte(`{
+				volumeClaimSpec: {
+					accessModes: [ReadWriteOnce],
+					resources: { requests: { storage: 1Gi } },
+				},
+			}`), cluster.Spec.Backups.PGBackRest.ArchiveAsync))
+
+			pvc, err := reconciler.reconcilePGBackRestSpoolVolume(ctx, cluster, spec, instance)
+			assert.NilError(t, err)
+
+			assert.Assert(t, metav1.IsControlledBy(pvc, cluster))
+
+			assert.Equal(t, pvc.Labels[naming.LabelCluster], cluster.Name)
+			assert.Equal(t, pvc.Labels[naming.LabelInstance], instance.Name)
+			assert.Equal(t, pvc.Labels[naming.LabelInstanceSet], spec.Name)
+			assert.Equal(t, pvc.Labels[naming.LabelRole], "pgbackrest-spool")
+
+			assert.Assert(t, marshalMatches(pvc.Spec, `
+accessModes:
+- ReadWriteOnce
+resources:
+  requests:
+    storage: 1Gi
+volumeMode: Filesystem
+			`))
+
+			t.Run("Removed", func(t *testing.T) {
+				cluster := cluster.DeepCopy()
+				cluster.Spec.Backups.PGBackRest.ArchiveAsync = nil
+
+				returned, err := reconciler.reconcilePGBackRestSpoolVolume(ctx, cluster, spec, instance)
+				assert.NilError(t, err)
+				assert.Assert(t, returned == nil)
+
+				key, fetched := client.ObjectKeyFromObject(pvc), &corev1.PersistentVolumeClaim{}
+				if err := tClient.Get(ctx, key, fetched); err == nil {
+					assert.Assert(t, fetched.DeletionTimestamp != nil, "expected deleted")
+				} else {
+					assert.Assert(t, apierrors.IsNotFound(err), "expected deleted, got %v", err)
+				}
+
+				// Pods will redeploy while the PVC is scheduled for deletion.
+				returned, err = reconciler.reconcilePGBackRestSpoolVolume(ctx, cluster, spec, instance)
+				assert.NilError(t, err)
+				assert.Assert(t, returned == nil)
+			})
+		})
+	})
 }
 
 func TestReconcileDatabaseInitSQL(t *testing.T) {
@@ -644,3 +710,133 @@ func TestReconcileDatabaseInitSQLConfigMap(t *testing.T) {
 		assert.Assert(t, called)
 	})
 }
+
+func TestInstanceSetsFailoverValidation(t *testing.T) {
+	ctx := context.Background()
+	env, cc, _ := setupTestEnv(t, ControllerName)
+	t.Cleanup(func() { teardownTestEnv(t, env) })
+
+	ns := &corev1.Namespace{}
+	ns.GenerateName = "postgres-operator-test-"
+	assert.NilError(t, cc.Create(ctx, ns))
+	t.Cleanup(func() { assert.Check(t, cc.Delete(ctx, ns)) })
+
+	base := testCluster()
+	base.Namespace = ns.Name
+	delayed := base.Spec.InstanceSets[0].DeepCopy()
+	delayed.Name = "delayed"
+	delayed.RecoveryMinApplyDelay = &metav1.Duration{Duration: time.Hour}
+	noFailover := base.Spec.InstanceSets[0].DeepCopy()
+	noFailover.Name = "nofailover"
+	noFailover.PatroniTags = &v1beta1.PatroniTags{NoFailover: true}
+
+	t.Run("Candidate", func(t *testing.T) {
+		cluster := base.DeepCopy()
+		cluster.Spec.InstanceSets = append(cluster.Spec.InstanceSets, *delayed, *noFailover)
+		assert.NilError(t, cc.Create(ctx, cluster, client.DryRunAll))
+	})
+
+	t.Run("NoCandidate", func(t *testing.T) {
+		cluster := base.DeepCopy()
+		cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{*delayed, *noFailover}
+
+		err := cc.Create(ctx, cluster, client.DryRunAll)
+		assert.Assert(t, apierrors.IsInvalid(err), "expected Invalid, got\n%#v", err)
+		assert.ErrorContains(t, err, "spec.instances")
+	})
+}
+
+func TestReconcileReplayDelay(t *testing.T) {
+	ctx := context.Background()
+	reconciler := &Reconciler{}
+
+	running := corev1.ContainerStatus{
+		Name:  naming.ContainerDatabase,
+		State: corev1.ContainerState{Running: new(corev1.ContainerStateRunning)},
+	}
+	instance := func(name string, spec *v1beta1.PostgresInstanceSetSpec) *Instance {
+		return &Instance{
+			Name: name,
+			Pods: []*corev1.Pod{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: name + "-0"},
+				Status: corev1.PodStatus{
+					ContainerStatuses: []corev1.ContainerStatus{running},
+				},
+			}},
+			Spec: spec,
+		}
+	}
+	instances := &observedInstances{bySet: map[string][]*Instance{
+		"delayed": {instance("delayed-abc", &v1beta1.PostgresInstanceSetSpec{
+			Name:                  "delayed",
+			RecoveryMinApplyDelay: &metav1.Duration{Duration: time.Hour},
+		})},
+		"normal": {instance("normal-xyz", &v1beta1.PostgresInstanceSetSpec{
+			Name: "normal",
+		})},
+	}}
+
+	cluster := new(v1beta1.PostgresCluster)
+	cluster.Status.InstanceSets = []v1beta1.PostgresInstanceSetStatus{
+		{Name: "delayed"},
+		{Name: "normal", ReplayDelay: &metav1.Duration{Duration: time.Second}},
+	}
+
+	t.Run("Measured", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		reconciler.PodExec = func(
+			namespace, pod, container string, _ io.Reader, stdout, _ io.Writer, command ...string,
+		) error {
+			assert.Equal(t, pod, "delayed-abc-0", "expected only delayed instances")
+			assert.Equal(t, container, "database")
+			_, _ = stdout.Write([]byte("3599800\n"))
+			return nil
+		}
+
+		result := reconciler.reconcileReplayDelay(ctx, cluster, instances)
+		assert.Equal(t, result.RequeueAfter, time.Minute)
+
+		assert.DeepEqual(t, cluster.Status.InstanceSets[0].ReplayDelay,
+			&metav1.Duration{Duration: time.Hour})
+		assert.Assert(t, cluster.Status.InstanceSets[1].ReplayDelay == nil,
+			"expected undelayed sets to be cleared")
+		assert.Assert(t, cluster.Status.InstanceSets[0].ReplayDelayObservedTime != nil)
+	})
+
+	t.Run("Unmeasured", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Status.InstanceSets[0].ReplayDelay = &metav1.Duration{Duration: time.Minute}
+		reconciler.PodExec = func(
+			_, _, _ string, _ io.Reader, _, _ io.Writer, _ ...string,
+		) error {
+			return errors.New("boom")
+		}
+
+		result := reconciler.reconcileReplayDelay(ctx, cluster, instances)
+		assert.Equal(t, result.RequeueAfter, time.Minute)
+
+		assert.DeepEqual(t, cluster.Status.InstanceSets[0].ReplayDelay,
+			&metav1.Duration{Duration: time.Minute})
+	})
+
+	t.Run("Interval", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		recent := metav1.NewTime(time.Now().Add(-20 * time.Second))
+		cluster.Status.InstanceSets[0].ReplayDelay = &metav1.Duration{Duration: time.Minute}
+		cluster.Status.InstanceSets[0].ReplayDelayObservedTime = &recent
+		reconciler.PodExec = func(
+			_, _, _ string, _ io.Reader, _, _ io.Writer, _ ...string,
+		) error {
+			t.Fatal("expected no measurement before the interval")
+			return nil
+		}
+
+		result := reconciler.reconcileReplayDelay(ctx, cluster, instances)
+		assert.Assert(t, result.RequeueAfter > 0)
+		assert.Assert(t, result.RequeueAfter <= 40*time.Second)
+
+		assert.DeepEqual(t, cluster.Status.InstanceSets[0].ReplayDelay,
+			&metav1.Duration{Duration: time.Minute})
+		assert.Equal(t, cluster.Status.InstanceSets[0].ReplayDelayObservedTime, &recent)
+	})
+}
@@ -46,6 +46,29 @@ func (*Reconciler) watchPods() handler.Funcs {
 				return
 			}
 
+			// Queue an event when a Patroni callback reports a change of role or
+			// state. The primary may have changed.
+			if len(cluster) != 0 &&
+				e.ObjectOld.GetAnnotations()[naming.PatroniCallback] !=
+					e.ObjectNew.GetAnnotations()[naming.PatroniCallback] {
+				q.Add(reconcile.Request{NamespacedName: client.ObjectKey{
+					Namespace: e.ObjectNew.GetNamespace(),
+					Name:      cluster,
+				}})
+				return
+			}
+
+			// Queue an event when someone requests a replica be reinitialized.
+			if _, requested := e.ObjectNew.GetAnnotations()[naming.PatroniReinitialize]; len(cluster) != 0 &&
+				requested && e.ObjectOld.GetAnnotations()[naming.PatroniReinitialize] !=
+				e.ObjectNew.GetAnnotations()[naming.PatroniReinitialize] {
+				q.Add(reconcile.Request{NamespacedName: client.ObjectKey{
+					Namespace: e.ObjectNew.GetNamespace(),
+					Name:      cluster,
+				}})
+				return
+			}
+
 			// Queue an event when a Patroni pod indicates it needs to restart
 			// or finished restarting.
 			if len(cluster) != 0 &&
@@ -140,4 +140,74 @@ func TestWatchPodsUpdate(t *testing.T) {
 		assert.Equal(t, item, expected)
 		queue.Done(item)
 	})
+
+	t.Run("PatroniCallback", func(t *testing.T) {
+		expected := reconcile.Request{}
+		expected.Namespace = "some-ns"
+		expected.Name = "starfish"
+
+		base := &corev1.Pod{}
+		base.Namespace = "some-ns"
+		base.Labels = map[string]string{
+			"postgres-operator.crunchydata.com/cluster": "starfish",
+		}
+
+		promoted := base.DeepCopy()
+		promoted.Annotations = map[string]string{
+			"postgres-operator.crunchydata.com/patroni-callback": `{"action":"on_role_change","role":"master"}`,
+		}
+
+		// New callback; one reconcile by label.
+		update(event.UpdateEvent{
+			ObjectOld: base.DeepCopy(),
+			ObjectNew: promoted.DeepCopy(),
+		}, queue)
+		assert.Equal(t, queue.Len(), 1, "expected one reconcile")
+
+		item, _ := queue.Get()
+		assert.Equal(t, item, expected)
+		queue.Done(item)
+
+		// Same callback; no reconcile.
+		update(event.UpdateEvent{
+			ObjectOld: promoted.DeepCopy(),
+			ObjectNew: promoted.DeepCopy(),
+		}, queue)
+		assert.Equal(t, queue.Len(), 0, "expected no reconcile")
+	})
+
+	t.Run("PatroniReinitialize", func(t *testing.T) {
+		expected := reconcile.Request{}
+		expected.Namespace = "some-ns"
+		expected.Name = "starfish"
+
+		base := &corev1.Pod{}
+		base.Namespace = "some-ns"
+		base.Labels = map[string]string{
+			"postgres-operator.crunchydata.com/cluster": "starfish",
+		}
+
+		requested := base.DeepCopy()
+		requested.Annotations = map[string]string{
+			"postgres-operator.crunchydata.com/trigger-reinitialize": "now",
+		}
+
+		// New request; one reconcile by label.
+		update(event.UpdateEvent{
+			ObjectOld: base.DeepCopy(),
+			ObjectNew: requested.DeepCopy(),
+		}, queue)
+		assert.Equal(t, queue.Len(), 1, "expected one reconcile")
+
+		item, _ := queue.Get()
+		assert.Equal(t, item, expected)
+		queue.Done(item)
+
+		// Request removed; no reconcile.
+		update(event.UpdateEvent{
+			ObjectOld: requested.DeepCopy(),
+			ObjectNew: base.DeepCopy(),
+		}, queue)
+		assert.Equal(t, queue.Len(), 0, "expected no reconcile")
+	})
 }
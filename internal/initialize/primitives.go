@@ -15,6 +15,10 @@
 
 package initialize
 
+import (
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
 // Bool returns a pointer to v.
 func Bool(v bool) *bool { return &v }
 
@@ -31,6 +35,12 @@ func Int32(v int32) *int32 { return &v }
 // Int64 returns a pointer to v.
 func Int64(v int64) *int64 { return &v }
 
+// IntOrStringInt32 returns a pointer to an IntOrString containing v.
+func IntOrStringInt32(v int32) *intstr.IntOrString {
+	ios := intstr.FromInt(int(v))
+	return &ios
+}
+
 // String returns a pointer to v.
 func String(v string) *string { return &v }
 
@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/crunchydata/postgres-operator/internal/initialize"
 )
@@ -90,6 +91,18 @@ func TestInt64(t *testing.T) {
 	}
 }
 
+func TestIntOrStringInt32(t *testing.T) {
+	z := initialize.IntOrStringInt32(0)
+	if assert.Check(t, z != nil) {
+		assert.DeepEqual(t, *z, intstr.FromInt(0))
+	}
+
+	p := initialize.IntOrStringInt32(3)
+	if assert.Check(t, p != nil) {
+		assert.DeepEqual(t, *p, intstr.FromInt(3))
+	}
+}
+
 func TestString(t *testing.T) {
 	z := initialize.String("")
 	if assert.Check(t, z != nil) {
@@ -25,6 +25,25 @@ const (
 	// Patroni Switchover (or Failover).
 	PatroniSwitchover = annotationPrefix + "trigger-switchover"
 
+	// PatroniReinitialize is the annotation added to an instance Pod to
+	// reinitialize its replica. The operator removes it once the Patroni
+	// member on that Pod is reinitializing.
+	PatroniReinitialize = annotationPrefix + "trigger-reinitialize"
+
+	// PatroniTags is the annotation added to instance Pods to record the Patroni
+	// tags they were started with. Patroni reads its tags only when it starts, so
+	// a change to this annotation causes the Pods to be redeployed.
+	PatroniTags = annotationPrefix + "patroni-tags"
+
+	// PatroniCallback is the annotation that Patroni callbacks add to instance
+	// Pods to record the most recent callback, e.g. a change of role.
+	PatroniCallback = annotationPrefix + "patroni-callback"
+
+	// RecoveryMinApplyDelay is the annotation added to instance Pods to record
+	// the replay delay they were started with. A change to this annotation
+	// causes the Pods to be redeployed.
+	RecoveryMinApplyDelay = annotationPrefix + "recovery-min-apply-delay"
+
 	// PGBackRestBackup is the annotation that is added to a PostgresCluster to initiate a manual
 	// backup.  The value of the annotation will be a unique identifier for a backup Job (e.g. a
 	// timestamp), which will be stored in the PostgresCluster status to properly track completion
@@ -25,8 +25,11 @@ import (
 func TestAnnotationsValid(t *testing.T) {
 	assert.Assert(t, nil == validation.IsQualifiedName(Finalizer))
 	assert.Assert(t, nil == validation.IsQualifiedName(PatroniSwitchover))
+	assert.Assert(t, nil == validation.IsQualifiedName(PatroniTags))
 	assert.Assert(t, nil == validation.IsQualifiedName(PGBackRestBackup))
 	assert.Assert(t, nil == validation.IsQualifiedName(PGBackRestConfigHash))
 	assert.Assert(t, nil == validation.IsQualifiedName(PGBackRestCurrentConfig))
 	assert.Assert(t, nil == validation.IsQualifiedName(PGBackRestRestore))
+	assert.Assert(t, nil == validation.IsQualifiedName(PatroniCallback))
+	assert.Assert(t, nil == validation.IsQualifiedName(RecoveryMinApplyDelay))
 }
@@ -33,6 +33,10 @@ const (
 	LabelPatroni = labelPrefix + "patroni"
 	LabelRole    = labelPrefix + "role"
 
+	// LabelLoadBalance is used to identify instance Pods that may receive
+	// traffic from the replica Service.
+	LabelLoadBalance = labelPrefix + "load-balance"
+
 	// LabelClusterCertificate is used to identify a secret containing a cluster certificate
 	LabelClusterCertificate = labelPrefix + "cluster-certificate"
 
@@ -77,6 +81,10 @@ const (
 	// LabelPGBackRestRestore is used to indicate that a Job or Pod is for a pgBackRest restore
 	LabelPGBackRestRestore = labelPrefix + "pgbackrest-restore"
 
+	// LabelPGBackRestVerify is used to indicate that a resource is for verifying the backups
+	// of a pgBackRest repository. Its value is the name of the repository.
+	LabelPGBackRestVerify = labelPrefix + "pgbackrest-verify"
+
 	// LabelPGBackRestRestoreConfig is used to indicate that a configuration
 	// resource (e.g. a ConfigMap or Secret) is for a pgBackRest restore
 	LabelPGBackRestRestoreConfig = labelPrefix + "pgbackrest-restore-config"
@@ -111,9 +119,15 @@ const (
 	// RolePGAdmin is the LabelRole applied to pgAdmin objects.
 	RolePGAdmin = "pgadmin"
 
+	// RolePGBackRestSpool is the LabelRole applied to pgBackRest spool volumes.
+	RolePGBackRestSpool = "pgbackrest-spool"
+
 	// RolePostgresData is the LabelRole applied to PostgreSQL data volumes.
 	RolePostgresData = "pgdata"
 
+	// RolePostgresTablespace is the LabelRole applied to PostgreSQL tablespace volumes.
+	RolePostgresTablespace = "tablespace"
+
 	// RolePostgresUser is the LabelRole applied to PostgreSQL user secrets.
 	RolePostgresUser = "pguser"
 
@@ -272,6 +286,16 @@ func PGBackRestCronJobLabels(clusterName, repoName, backupType string) labels.Se
 	return labels.Merge(commonLabels, cronJobLabels)
 }
 
+// PGBackRestVerifyLabels provides labels for the resources that verify the backups of a
+// pgBackRest repository
+func PGBackRestVerifyLabels(clusterName, repoName string) labels.Set {
+	commonLabels := PGBackRestLabels(clusterName)
+	verifyLabels := map[string]string{
+		LabelPGBackRestVerify: repoName,
+	}
+	return labels.Merge(commonLabels, verifyLabels)
+}
+
 // PGBackRestDedicatedLabels provides labels for a pgBackRest dedicated repository host
 func PGBackRestDedicatedLabels(clusterName string) labels.Set {
 	commonLabels := PGBackRestLabels(clusterName)
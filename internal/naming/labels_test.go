@@ -28,6 +28,7 @@ func TestLabelsValid(t *testing.T) {
 	assert.Assert(t, nil == validation.IsQualifiedName(LabelData))
 	assert.Assert(t, nil == validation.IsQualifiedName(LabelInstance))
 	assert.Assert(t, nil == validation.IsQualifiedName(LabelInstanceSet))
+	assert.Assert(t, nil == validation.IsQualifiedName(LabelLoadBalance))
 	assert.Assert(t, nil == validation.IsQualifiedName(LabelMoveJob))
 	assert.Assert(t, nil == validation.IsQualifiedName(LabelMovePGBackRestRepoDir))
 	assert.Assert(t, nil == validation.IsQualifiedName(LabelMovePGDataDir))
@@ -42,6 +43,7 @@ func TestLabelsValid(t *testing.T) {
 	assert.Assert(t, nil == validation.IsQualifiedName(LabelPGBackRestRepoVolume))
 	assert.Assert(t, nil == validation.IsQualifiedName(LabelPGBackRestRestore))
 	assert.Assert(t, nil == validation.IsQualifiedName(LabelPGBackRestRestoreConfig))
+	assert.Assert(t, nil == validation.IsQualifiedName(LabelPGBackRestVerify))
 	assert.Assert(t, nil == validation.IsQualifiedName(LabelPGMonitorDiscovery))
 	assert.Assert(t, nil == validation.IsQualifiedName(LabelPostgresUser))
 	assert.Assert(t, nil == validation.IsQualifiedName(LabelStartupInstance))
@@ -56,8 +58,10 @@ func TestLabelValuesValid(t *testing.T) {
 	assert.Assert(t, nil == validation.IsValidLabelValue(RolePGAdmin))
 	assert.Assert(t, nil == validation.IsValidLabelValue(RolePGBouncer))
 	assert.Assert(t, nil == validation.IsValidLabelValue(RolePostgresData))
+	assert.Assert(t, nil == validation.IsValidLabelValue(RolePostgresTablespace))
 	assert.Assert(t, nil == validation.IsValidLabelValue(RolePostgresUser))
 	assert.Assert(t, nil == validation.IsValidLabelValue(RolePostgresWAL))
+	assert.Assert(t, nil == validation.IsValidLabelValue(RolePGBackRestSpool))
 	assert.Assert(t, nil == validation.IsValidLabelValue(RolePrimary))
 	assert.Assert(t, nil == validation.IsValidLabelValue(RoleReplica))
 	assert.Assert(t, nil == validation.IsValidLabelValue(string(BackupReplicaCreate)))
@@ -214,6 +218,13 @@ func TestPGBackRestLabelFuncs(t *testing.T) {
 	assert.Check(t, pgBackRestRestoreJobLabels.Has(LabelPGBackRest))
 	assert.Check(t, pgBackRestRestoreJobLabels.Has(LabelPGBackRestRestore))
 
+	// verify the labels that identify pgBackRest backup verification resources
+	pgBackRestVerifyLabels := PGBackRestVerifyLabels(clusterName, repoName)
+	assert.Equal(t, pgBackRestVerifyLabels.Get(LabelCluster), clusterName)
+	assert.Check(t, pgBackRestVerifyLabels.Has(LabelPGBackRest))
+	assert.Equal(t, pgBackRestVerifyLabels.Get(LabelPGBackRestVerify), repoName)
+	assert.Check(t, !pgBackRestVerifyLabels.Has(LabelPGBackRestRepo))
+
 	// verify the labels that identify pgBackRest restore configuration resources
 	pgBackRestRestoreConfigLabels := PGBackRestRestoreConfigLabels(clusterName)
 	assert.Equal(t, pgBackRestRestoreConfigLabels.Get(LabelCluster), clusterName)
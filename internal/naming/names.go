@@ -161,6 +161,11 @@ const (
 	// for instance, if the cluster is named 'mycluster', the
 	// secret will be named 'mycluster-ssh'
 	sshSecretNameSuffix = "%s-ssh"
+
+	// suffix used with postgrescluster name for associated secret.
+	// for instance, if the cluster is named 'mycluster', the
+	// secret will be named 'mycluster-pgbackrest-cipher'
+	cipherSecretNameSuffix = "%s-pgbackrest-cipher"
 )
 
 // AsObjectKey converts the ObjectMeta API type to a client.ObjectKey.
@@ -282,6 +287,17 @@ func InstanceCertificates(instance metav1.Object) metav1.ObjectMeta {
 	}
 }
 
+// InstanceSet returns the ObjectMeta necessary to lookup objects that apply
+// to every instance of set, such as its PodDisruptionBudget.
+func InstanceSet(
+	cluster *v1beta1.PostgresCluster, set *v1beta1.PostgresInstanceSetSpec,
+) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Namespace: cluster.Namespace,
+		Name:      cluster.Name + "-set-" + set.Name,
+	}
+}
+
 // InstancePostgresDataVolume returns the ObjectMeta for the PostgreSQL data
 // volume for instance.
 func InstancePostgresDataVolume(instance *appsv1.StatefulSet) metav1.ObjectMeta {
@@ -300,6 +316,24 @@ func InstancePostgresWALVolume(instance *appsv1.StatefulSet) metav1.ObjectMeta {
 	}
 }
 
+// InstancePGBackRestSpoolVolume returns the ObjectMeta for the pgBackRest
+// spool volume for instance.
+func InstancePGBackRestSpoolVolume(instance *appsv1.StatefulSet) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Namespace: instance.GetNamespace(),
+		Name:      instance.GetName() + "-pgbackrest-spool",
+	}
+}
+
+// InstanceTablespaceVolume returns the ObjectMeta for the PostgreSQL
+// tablespace volume named tablespace for instance.
+func InstanceTablespaceVolume(instance *appsv1.StatefulSet, tablespace string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Namespace: instance.GetNamespace(),
+		Name:      instance.GetName() + "-" + tablespace + "-tablespace",
+	}
+}
+
 // MonitoringUserSecret returns ObjectMeta necessary to lookup the Secret
 // containing authentication credentials for monitoring tools.
 func MonitoringUserSecret(cluster *v1beta1.PostgresCluster) metav1.ObjectMeta {
@@ -353,6 +387,16 @@ func PatroniScope(cluster *v1beta1.PostgresCluster) string {
 	return cluster.Name + "-ha"
 }
 
+// PatroniSync returns the ObjectMeta necessary to lookup the ConfigMap or
+// Endpoints Patroni creates for cluster to track its synchronous standbys.
+// See Patroni DCS "sync_path".
+func PatroniSync(cluster *v1beta1.PostgresCluster) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Namespace: cluster.Namespace,
+		Name:      PatroniScope(cluster) + "-sync",
+	}
+}
+
 // PatroniTrigger returns the ObjectMeta necessary to lookup the ConfigMap or
 // Endpoints Patroni creates for cluster to initiate a controlled change of the
 // leader. See Patroni DCS "failover_path".
@@ -388,6 +432,24 @@ func PGBackRestCronJob(cluster *v1beta1.PostgresCluster, backuptype, repoName st
 	}
 }
 
+// PGBackRestVerifyCronJob returns the ObjectMeta for the CronJob that verifies the backups of
+// a pgBackRest repository
+func PGBackRestVerifyCronJob(cluster *v1beta1.PostgresCluster, repoName string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Namespace: cluster.GetNamespace(),
+		Name:      cluster.Name + "-pgbackrest-" + repoName + "-verify",
+	}
+}
+
+// PGBackRestVerifyVolume returns the ObjectMeta for the temporary PersistentVolumeClaim that the
+// backups of a pgBackRest repository are restored into when they are verified
+func PGBackRestVerifyVolume(cluster *v1beta1.PostgresCluster, repoName string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Namespace: cluster.GetNamespace(),
+		Name:      cluster.Name + "-pgbackrest-" + repoName + "-verify-scratch",
+	}
+}
+
 // PGBackRestRestoreJob returns the ObjectMeta for a pgBackRest restore Job
 func PGBackRestRestoreJob(cluster *v1beta1.PostgresCluster) metav1.ObjectMeta {
 	return metav1.ObjectMeta{
@@ -405,6 +467,15 @@ func PGBackRestRBAC(cluster *v1beta1.PostgresCluster) metav1.ObjectMeta {
 	}
 }
 
+// PGBackRestRepoHost returns the ObjectMeta necessary to lookup the
+// StatefulSet and PodDisruptionBudget of cluster's dedicated repository host.
+func PGBackRestRepoHost(cluster *v1beta1.PostgresCluster) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Namespace: cluster.Namespace,
+		Name:      cluster.Name + "-repo-host",
+	}
+}
+
 // PGBackRestRepoVolume returns the ObjectMeta for a pgBackRest repository volume
 func PGBackRestRepoVolume(cluster *v1beta1.PostgresCluster,
 	repoName string) metav1.ObjectMeta {
@@ -430,6 +501,25 @@ func PGBackRestSSHSecret(cluster *v1beta1.PostgresCluster) metav1.ObjectMeta {
 	}
 }
 
+// PGBackRestCipherSecret returns the ObjectMeta for the Secret that holds the
+// passphrases of encrypted pgBackRest repositories
+func PGBackRestCipherSecret(cluster *v1beta1.PostgresCluster) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      fmt.Sprintf(cipherSecretNameSuffix, cluster.GetName()),
+		Namespace: cluster.GetNamespace(),
+	}
+}
+
+// PGBackRestSFTPSecret returns the ObjectMeta for the Secret that holds a copy
+// of the private key of SFTP pgBackRest repository repoName, e.g. when restoring
+// across namespaces.
+func PGBackRestSFTPSecret(cluster *v1beta1.PostgresCluster, repoName string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      cluster.GetName() + "-pgbackrest-" + repoName + "-sftp",
+		Namespace: cluster.GetNamespace(),
+	}
+}
+
 // PGUpgradeJob returns the ObjectMeta for the pg_upgrade Job utilized to
 // upgrade from one major PostgreSQL version to another
 func PGUpgradeJob(cluster *v1beta1.PostgresCluster) metav1.ObjectMeta {
@@ -101,6 +101,7 @@ func TestClusterNamesUniqueAndValid(t *testing.T) {
 			{"PGBackRestCronJon", PGBackRestCronJob(cluster, "incr", "repo2")},
 			{"PGBackRestCronJon", PGBackRestCronJob(cluster, "diff", "repo3")},
 			{"PGBackRestCronJon", PGBackRestCronJob(cluster, "full", "repo4")},
+			{"PGBackRestVerifyCronJob", PGBackRestVerifyCronJob(cluster, "repo1")},
 		})
 	})
 
@@ -118,6 +119,15 @@ func TestClusterNamesUniqueAndValid(t *testing.T) {
 		})
 	})
 
+	t.Run("PodDisruptionBudgets", func(t *testing.T) {
+		testUniqueAndValid(t, []test{
+			{"ClusterPGAdmin", ClusterPGAdmin(cluster)},
+			{"ClusterPGBouncer", ClusterPGBouncer(cluster)},
+			{"InstanceSet", InstanceSet(cluster, &v1beta1.PostgresInstanceSetSpec{Name: "00"})},
+			{"PGBackRestRepoHost", PGBackRestRepoHost(cluster)},
+		})
+	})
+
 	t.Run("RoleBindings", func(t *testing.T) {
 		testUniqueAndValid(t, []test{
 			{"ClusterInstanceRBAC", ClusterInstanceRBAC(cluster)},
@@ -139,6 +149,8 @@ func TestClusterNamesUniqueAndValid(t *testing.T) {
 			{"PostgresTLSSecret", PostgresTLSSecret(cluster)},
 			{"ReplicationClientCertSecret", ReplicationClientCertSecret(cluster)},
 			{"PGBackRestSSHSecret", PGBackRestSSHSecret(cluster)},
+			{"PGBackRestCipherSecret", PGBackRestCipherSecret(cluster)},
+			{"PGBackRestSFTPSecret", PGBackRestSFTPSecret(cluster, "repo1")},
 			{"MonitoringUserSecret", MonitoringUserSecret(cluster)},
 		})
 
@@ -180,6 +192,7 @@ func TestClusterNamesUniqueAndValid(t *testing.T) {
 		testUniqueAndValid(t, []test{
 			{"ClusterPGAdmin", ClusterPGAdmin(cluster)},
 			{"PGBackRestRepoVolume", PGBackRestRepoVolume(cluster, repoName)},
+			{"PGBackRestVerifyVolume", PGBackRestVerifyVolume(cluster, repoName)},
 		})
 	})
 }
@@ -216,6 +229,8 @@ func TestInstanceNamesUniqueAndValid(t *testing.T) {
 		for _, tt := range []test{
 			{"InstancePostgresDataVolume", InstancePostgresDataVolume(instance)},
 			{"InstancePostgresWALVolume", InstancePostgresWALVolume(instance)},
+			{"InstancePGBackRestSpoolVolume", InstancePGBackRestSpoolVolume(instance)},
+			{"InstanceTablespaceVolume", InstanceTablespaceVolume(instance, "space")},
 		} {
 			t.Run(tt.name, func(t *testing.T) {
 				assert.Equal(t, tt.value.Namespace, instance.Namespace)
@@ -91,6 +91,18 @@ func TestClusterInstanceSet(t *testing.T) {
 	assert.ErrorContains(t, err, "invalid")
 }
 
+func TestClusterInstanceSets(t *testing.T) {
+	s, err := AsSelector(ClusterInstanceSets("something"))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, s.String(), strings.Join([]string{
+		"postgres-operator.crunchydata.com/cluster=something",
+		"postgres-operator.crunchydata.com/instance-set",
+	}, ","))
+
+	_, err = AsSelector(ClusterInstanceSets("--whoa/yikes"))
+	assert.ErrorContains(t, err, "invalid")
+}
+
 func TestClusterPatronis(t *testing.T) {
 	cluster := &v1beta1.PostgresCluster{}
 	cluster.Name = "something"
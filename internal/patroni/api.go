@@ -32,8 +32,31 @@ type API interface {
 	// paused, next cannot be blank.
 	ChangePrimaryAndWait(ctx context.Context, current, next string) (bool, error)
 
+	// FailoverAndWait tries to change the current Patroni leader to target
+	// even when there is no leader. It returns true when an election completes
+	// successfully.
+	FailoverAndWait(ctx context.Context, target string) (bool, error)
+
+	// GetCluster returns the members of the Patroni cluster scope and their
+	// state.
+	GetCluster(ctx context.Context, scope string) (ClusterStatus, error)
+
+	// ReinitializeMember reloads the configuration files of the Patroni member
+	// in scope then discards its data directory and creates it again from the
+	// replica creation methods in that configuration.
+	ReinitializeMember(ctx context.Context, scope, member string) error
+
 	// ReplaceConfiguration replaces Patroni's entire dynamic configuration.
 	ReplaceConfiguration(ctx context.Context, configuration map[string]interface{}) error
+
+	// RestartPendingMembers restarts Patroni members with role in scope that
+	// have a pending restart.
+	RestartPendingMembers(ctx context.Context, role, scope string) error
+
+	// SwitchoverAndWait tries to change the current Patroni leader to target.
+	// It returns true when an election completes successfully. When target is
+	// blank, Patroni chooses the best candidate.
+	SwitchoverAndWait(ctx context.Context, target string) (bool, error)
 }
 
 // Executor implements API by calling "patronictl".
@@ -133,6 +156,96 @@ func (exec Executor) FailoverAndWait(
 	return strings.Contains(stdout.String(), "failed over"), err
 }
 
+// GetCluster returns the members of the Patroni cluster scope and their state
+// by calling "patronictl". Similar to the "GET /cluster" REST endpoint, but
+// replication lag is rounded to the nearest megabyte.
+func (exec Executor) GetCluster(ctx context.Context, scope string) (ClusterStatus, error) {
+	var stdout, stderr bytes.Buffer
+	var status ClusterStatus
+
+	err := exec(ctx, nil, &stdout, &stderr,
+		"patronictl", "list", "--format=json", scope)
+
+	log := logging.FromContext(ctx)
+	log.V(1).Info("listed members",
+		"stdout", stdout.String(),
+		"stderr", stderr.String(),
+	)
+
+	// The JSON format uses the same column titles as the table format.
+	// - https://github.com/zalando/patroni/blob/v2.1.1/patroni/ctl.py#L799-L859
+	var rows []struct {
+		Member         string      `json:"Member"`
+		Host           string      `json:"Host"`
+		Role           string      `json:"Role"`
+		State          string      `json:"State"`
+		Timeline       int64       `json:"TL"`
+		Lag            interface{} `json:"Lag in MB"`
+		PendingRestart string      `json:"Pending restart"`
+		Tags           interface{} `json:"Tags"`
+	}
+	if err == nil {
+		err = json.Unmarshal(stdout.Bytes(), &rows)
+	}
+
+	for _, row := range rows {
+		member := ClusterMember{
+			Name:           row.Member,
+			Host:           row.Host,
+			State:          row.State,
+			Timeline:       row.Timeline,
+			PendingRestart: row.PendingRestart == "*",
+		}
+
+		// Convert "Sync Standby" to "sync_standby", etc.
+		member.Role = strings.ReplaceAll(strings.ToLower(row.Role), " ", "_")
+
+		if lag, ok := row.Lag.(float64); ok {
+			member.Lag = lag * 1024 * 1024
+		} else if row.Lag != nil && row.Lag != "" {
+			member.Lag = row.Lag
+		}
+
+		// Tags may be an object or a string of JSON.
+		switch tags := row.Tags.(type) {
+		case map[string]interface{}:
+			member.Tags = tags
+		case string:
+			_ = json.Unmarshal([]byte(tags), &member.Tags)
+		}
+
+		status.Members = append(status.Members, member)
+	}
+
+	return status, err
+}
+
+// ReinitializeMember reloads the configuration files of the Patroni member in
+// scope then reinitializes it by calling "patronictl". Similar to the
+// "POST /reload" and "POST /reinitialize" REST endpoints.
+func (exec Executor) ReinitializeMember(ctx context.Context, scope, member string) error {
+	var stdout, stderr bytes.Buffer
+
+	// Patroni reads its configuration files only when it starts or reloads.
+	// Reload first so the member uses the current replica creation methods.
+	// - https://github.com/zalando/patroni/blob/v2.1.1/patroni/ctl.py#L548-L577
+	err := exec(ctx, nil, &stdout, &stderr,
+		"patronictl", "reload", "--force", scope, member)
+
+	if err == nil {
+		err = exec(ctx, nil, &stdout, &stderr,
+			"patronictl", "reinit", "--force", scope, member)
+	}
+
+	log := logging.FromContext(ctx)
+	log.V(1).Info("reinitialized member",
+		"stdout", stdout.String(),
+		"stderr", stderr.String(),
+	)
+
+	return err
+}
+
 // ReplaceConfiguration replaces Patroni's entire dynamic configuration by
 // calling "patronictl". Similar to the "POST /switchover" REST endpoint.
 func (exec Executor) ReplaceConfiguration(
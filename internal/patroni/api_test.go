@@ -217,6 +217,32 @@ func TestExecutorReplaceConfiguration(t *testing.T) {
 	assert.Equal(t, expected, actual, "should call exec")
 }
 
+func TestExecutorReinitializeMember(t *testing.T) {
+	var calls []string
+	expected := errors.New("oop")
+	exec := func(
+		_ context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+	) error {
+		calls = append(calls, strings.Join(command, " "))
+		assert.Assert(t, stdin == nil, "expected no stdin, got %T", stdin)
+		assert.Assert(t, stderr != nil, "should capture stderr")
+		assert.Assert(t, stdout != nil, "should capture stdout")
+		if len(calls) > 1 {
+			return expected
+		}
+		return nil
+	}
+
+	actual := Executor(exec).ReinitializeMember(
+		context.Background(), "shoe-scope", "sock-member")
+
+	assert.Equal(t, expected, actual, "should call exec")
+	assert.DeepEqual(t, calls, []string{
+		`patronictl reload --force shoe-scope sock-member`,
+		`patronictl reinit --force shoe-scope sock-member`,
+	})
+}
+
 func TestExecutorRestartPendingMembers(t *testing.T) {
 	expected := errors.New("oop")
 	exec := func(
@@ -236,3 +262,50 @@ func TestExecutorRestartPendingMembers(t *testing.T) {
 
 	assert.Equal(t, expected, actual, "should call exec")
 }
+
+func TestExecutorGetCluster(t *testing.T) {
+	t.Run("Arguments", func(t *testing.T) {
+		expected := errors.New("bang")
+		_, actual := Executor(func(
+			_ context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			assert.DeepEqual(t, command, strings.Fields(
+				`patronictl list --format=json some-scope`,
+			))
+			assert.Assert(t, stdin == nil, "expected no stdin, got %T", stdin)
+			assert.Assert(t, stderr != nil, "should capture stderr")
+			assert.Assert(t, stdout != nil, "should capture stdout")
+			return expected
+		}).GetCluster(context.Background(), "some-scope")
+
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("Result", func(t *testing.T) {
+		status, err := Executor(func(
+			_ context.Context, _ io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			_, _ = stdout.Write([]byte(`[
+{"Cluster": "hippo-ha", "Member": "one-0", "Host": "10.0.0.1", "Role": "Leader", "State": "running", "TL": 2},
+{"Cluster": "hippo-ha", "Member": "two-0", "Host": "10.0.0.2", "Role": "Sync Standby", "State": "running", "TL": 2, "Lag in MB": 3, "Pending restart": "*", "Tags": {"nofailover": true}},
+{"Cluster": "hippo-ha", "Member": "three-0", "Host": "10.0.0.3", "Role": "Replica", "State": "starting", "TL": 1, "Lag in MB": "unknown"}
+]`))
+			return nil
+		}).GetCluster(context.Background(), "any")
+
+		assert.NilError(t, err)
+		assert.DeepEqual(t, status, ClusterStatus{Members: []ClusterMember{
+			{Name: "one-0", Host: "10.0.0.1", Role: "leader", State: "running", Timeline: 2},
+			{Name: "two-0", Host: "10.0.0.2", Role: "sync_standby", State: "running", Timeline: 2,
+				Lag: float64(3 * 1024 * 1024), PendingRestart: true,
+				Tags: map[string]interface{}{"nofailover": true}},
+			{Name: "three-0", Host: "10.0.0.3", Role: "replica", State: "starting", Timeline: 1,
+				Lag: "unknown"},
+		}})
+
+		assert.Assert(t, status.Members[0].IsLeader())
+		lag, known := status.Members[1].LagBytes()
+		assert.Assert(t, known)
+		assert.Equal(t, lag, int64(3*1024*1024))
+	})
+}
@@ -19,6 +19,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/crunchydata/postgres-operator/internal/pki"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
 
 const (
@@ -27,6 +28,14 @@ const (
 
 	certAuthorityFileKey = "patroni.ca-roots"
 	certServerFileKey    = "patroni.crt-combined"
+
+	etcdAuthorityConfigPath   = "~postgres-operator/etcd-ca.crt"
+	etcdCertificateConfigPath = "~postgres-operator/etcd-tls.crt"
+	etcdPrivateKeyConfigPath  = "~postgres-operator/etcd-tls.key"
+
+	tlsAuthoritySecretKey   = "ca.crt"
+	tlsCertificateSecretKey = corev1.TLSCertKey
+	tlsPrivateKeySecretKey  = corev1.TLSPrivateKeyKey
 )
 
 // certAuthorities encodes roots in a format suitable for Patroni's TLS verification.
@@ -85,3 +94,57 @@ func instanceCertificates(certificates *corev1.Secret) []corev1.VolumeProjection
 		},
 	}}
 }
+
+// etcdCertificates returns projections of the client certificate Patroni uses
+// to connect to etcd, if any, to include in the instance configuration volume.
+func etcdCertificates(cluster *v1beta1.PostgresCluster) []corev1.VolumeProjection {
+	if DCSType(cluster) != "Etcd" ||
+		cluster.Spec.Patroni == nil || cluster.Spec.Patroni.DCS == nil ||
+		cluster.Spec.Patroni.DCS.Etcd == nil ||
+		cluster.Spec.Patroni.DCS.Etcd.TLSSecret == nil {
+		return nil
+	}
+
+	// The custom projection may have more or less than the three items we need
+	// to mount. Search for items that have the Path we expect and mount them at
+	// the path we need. When no items are specified, the Key serves as the Path.
+
+	var items []corev1.KeyToPath
+	result := cluster.Spec.Patroni.DCS.Etcd.TLSSecret.DeepCopy()
+
+	for i := range result.Items {
+		switch result.Items[i].Path {
+		case tlsAuthoritySecretKey:
+			result.Items[i].Path = etcdAuthorityConfigPath
+			items = append(items, result.Items[i])
+
+		case tlsCertificateSecretKey:
+			result.Items[i].Path = etcdCertificateConfigPath
+			items = append(items, result.Items[i])
+
+		case tlsPrivateKeySecretKey:
+			result.Items[i].Path = etcdPrivateKeyConfigPath
+			items = append(items, result.Items[i])
+		}
+	}
+
+	if len(items) == 0 {
+		items = []corev1.KeyToPath{
+			{
+				Key:  tlsAuthoritySecretKey,
+				Path: etcdAuthorityConfigPath,
+			},
+			{
+				Key:  tlsCertificateSecretKey,
+				Path: etcdCertificateConfigPath,
+			},
+			{
+				Key:  tlsPrivateKeySecretKey,
+				Path: etcdPrivateKeyConfigPath,
+			},
+		}
+	}
+
+	result.Items = items
+	return []corev1.VolumeProjection{{Secret: result}}
+}
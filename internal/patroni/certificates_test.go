@@ -24,6 +24,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/crunchydata/postgres-operator/internal/pki"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
 
 const rootPEM = `-----BEGIN CERTIFICATE-----
@@ -91,3 +92,60 @@ func TestInstanceCertificates(t *testing.T) {
     name: some-name
 	`)+"\n"))
 }
+
+func TestEtcdCertificates(t *testing.T) {
+	cluster := new(v1beta1.PostgresCluster)
+	assert.Assert(t, etcdCertificates(cluster) == nil)
+
+	cluster.Spec.Patroni = &v1beta1.PatroniSpec{
+		DCS: &v1beta1.PatroniDCS{
+			Type: "Etcd",
+			Etcd: &v1beta1.PatroniEtcd{Hosts: []string{"etcd:2379"}},
+		},
+	}
+	assert.Assert(t, etcdCertificates(cluster) == nil, "expected nothing without TLS")
+
+	t.Run("Default", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Patroni.DCS.Etcd.TLSSecret = &corev1.SecretProjection{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "etcd-client"},
+		}
+
+		assert.Assert(t, marshalEquals(etcdCertificates(cluster), strings.TrimSpace(`
+- secret:
+    items:
+    - key: ca.crt
+      path: ~postgres-operator/etcd-ca.crt
+    - key: tls.crt
+      path: ~postgres-operator/etcd-tls.crt
+    - key: tls.key
+      path: ~postgres-operator/etcd-tls.key
+    name: etcd-client
+		`)+"\n"))
+	})
+
+	t.Run("CustomItems", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Patroni.DCS.Etcd.TLSSecret = &corev1.SecretProjection{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "etcd-client"},
+			Items: []corev1.KeyToPath{
+				{Key: "trusted", Path: "ca.crt"},
+				{Key: "cert", Path: "tls.crt"},
+				{Key: "private", Path: "tls.key"},
+				{Key: "other", Path: "other"},
+			},
+		}
+
+		assert.Assert(t, marshalEquals(etcdCertificates(cluster), strings.TrimSpace(`
+- secret:
+    items:
+    - key: trusted
+      path: ~postgres-operator/etcd-ca.crt
+    - key: cert
+      path: ~postgres-operator/etcd-tls.crt
+    - key: private
+      path: ~postgres-operator/etcd-tls.key
+    name: etcd-client
+		`)+"\n"))
+	})
+}
@@ -0,0 +1,414 @@
+/*
+ Copyright 2021 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package patroni
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/crunchydata/postgres-operator/internal/logging"
+	"github.com/crunchydata/postgres-operator/internal/pki"
+)
+
+// Client implements API by calling the Patroni REST API of one member.
+// - https://github.com/zalando/patroni/blob/v2.1.1/docs/rest_api.rst
+type Client struct {
+	// BaseURL is the scheme, host, and port of the member, e.g.
+	// "https://some-pod-0.some-service.some-namespace.svc:8008".
+	BaseURL string
+
+	// MemberURL returns the scheme, host, and port of another member by name.
+	// The "api_url" that Patroni reports for each member is not qualified by
+	// namespace, so it does not resolve from outside the cluster's namespace.
+	MemberURL func(member string) *url.URL
+
+	// HTTP sends requests to Patroni. Its transport should present a client
+	// certificate that Patroni trusts.
+	HTTP *http.Client
+}
+
+// Client implements API.
+var _ API = (*Client)(nil)
+
+// clientTimeout is the longest a Client waits for any one response. Patroni
+// answers most requests immediately; those that wait, such as a switchover,
+// return within a few loop intervals.
+const clientTimeout = 60 * time.Second
+
+// NewClient returns a Client that calls the Patroni REST API at baseURL over
+// mutual TLS. It verifies the server against root and identifies itself using
+// cert and key.
+func NewClient(
+	baseURL string, root *pki.Certificate, cert *pki.Certificate, key *pki.PrivateKey,
+) (*Client, error) {
+	client, err := NewHTTPClient(root, cert, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{BaseURL: strings.TrimSuffix(baseURL, "/"), HTTP: client}, nil
+}
+
+// NewHTTPClient returns an HTTP client that calls Patroni over mutual TLS. It
+// verifies servers against root and identifies itself using cert and key. It
+// keeps connections open, so share it among Clients of the same cluster.
+func NewHTTPClient(
+	root *pki.Certificate, cert *pki.Certificate, key *pki.PrivateKey,
+) (*http.Client, error) {
+	config, err := clientTLSConfig(root, cert, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Client{
+		Timeout: clientTimeout,
+		Transport: &http.Transport{
+			Proxy:           nil, // Patroni is always reached directly.
+			TLSClientConfig: config,
+		},
+	}, nil
+}
+
+// APIError is a response from the Patroni REST API that indicates the request
+// did not succeed.
+type APIError struct {
+	Method, Path string
+	StatusCode   int
+	Message      string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("patroni: %s %s: %d %s",
+		e.Method, e.Path, e.StatusCode, strings.TrimSpace(e.Message))
+}
+
+// ClusterStatus is the response of the "GET /cluster" REST endpoint.
+type ClusterStatus struct {
+	Members []ClusterMember `json:"members"`
+	Paused  bool            `json:"pause,omitempty"`
+}
+
+// ClusterMember is one member of a ClusterStatus.
+type ClusterMember struct {
+	Name     string `json:"name"`
+	Role     string `json:"role"`
+	State    string `json:"state"`
+	APIURL   string `json:"api_url"`
+	Host     string `json:"host"`
+	Port     int32  `json:"port"`
+	Timeline int64  `json:"timeline,omitempty"`
+
+	// Lag is the number of bytes this member is behind the leader. Patroni
+	// reports "unknown" when it cannot be determined.
+	Lag interface{} `json:"lag,omitempty"`
+
+	PendingRestart bool                   `json:"pending_restart,omitempty"`
+	Tags           map[string]interface{} `json:"tags,omitempty"`
+}
+
+// IsLeader returns true when m is the leader of its cluster.
+func (m ClusterMember) IsLeader() bool {
+	return m.Role == "leader" || m.Role == "master" || m.Role == "standby_leader"
+}
+
+// IsFailed returns true when PostgreSQL on m failed to start or crashed.
+func (m ClusterMember) IsFailed() bool {
+	return m.State == "start failed" || m.State == "restart failed" || m.State == "crashed"
+}
+
+// LagBytes returns the number of bytes m is behind the leader and whether or
+// not that is known.
+func (m ClusterMember) LagBytes() (int64, bool) {
+	if lag, ok := m.Lag.(float64); ok {
+		return int64(lag), true
+	}
+	return 0, false
+}
+
+// do sends a request with a JSON body, when not nil, to the Patroni REST API
+// and returns the status code and body of its response.
+func (c *Client) do(
+	ctx context.Context, method, target string, body interface{},
+) (int, []byte, error) {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return 0, nil, err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	// Relative targets are relative to BaseURL. Absolute targets are used as-is.
+	if !strings.HasPrefix(target, "https://") && !strings.HasPrefix(target, "http://") {
+		target = c.BaseURL + target
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return 0, nil, err
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := c.HTTP.Do(request)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer response.Body.Close()
+
+	content, err := ioutil.ReadAll(response.Body)
+
+	logging.FromContext(ctx).V(1).Info("called Patroni",
+		"method", method, "url", target,
+		"status", response.StatusCode, "response", string(content))
+
+	return response.StatusCode, content, err
+}
+
+// changePrimary calls either the "POST /switchover" or "POST /failover" REST
+// endpoint. It returns true when Patroni reports that an election completed
+// successfully and chose the candidate, when one was requested.
+func (c *Client) changePrimary(
+	ctx context.Context, path, success string, body map[string]string,
+) (bool, error) {
+	code, content, err := c.do(ctx, http.MethodPost, path, body)
+	if err == nil && code != http.StatusOK {
+		err = &APIError{Method: http.MethodPost, Path: path,
+			StatusCode: code, Message: string(content)}
+	}
+
+	// Patroni responds OK even when it promotes a member other than the
+	// requested candidate. Only the success message is in lowercase.
+	// - https://github.com/zalando/patroni/blob/v2.1.1/patroni/api.py#L461-L477
+	return err == nil && strings.Contains(string(content), success), err
+}
+
+// ChangePrimaryAndWait tries to demote the current Patroni leader by calling
+// the "POST /switchover" REST endpoint. It returns true when an election
+// completes successfully. When Patroni is paused, next cannot be blank.
+func (c *Client) ChangePrimaryAndWait(
+	ctx context.Context, current, next string,
+) (bool, error) {
+	body := map[string]string{"leader": current}
+	if next != "" {
+		body["candidate"] = next
+	}
+	return c.changePrimary(ctx, "/switchover", "switched over", body)
+}
+
+// SwitchoverAndWait tries to change the current Patroni leader to target by
+// calling the "POST /switchover" REST endpoint. It returns true when an
+// election completes successfully. The endpoint requires the name of the
+// current leader, so it is read from the "GET /cluster" REST endpoint first.
+func (c *Client) SwitchoverAndWait(
+	ctx context.Context, target string,
+) (bool, error) {
+	status, err := c.GetCluster(ctx, "")
+	if err != nil {
+		return false, err
+	}
+
+	var leader string
+	for _, member := range status.Members {
+		if member.IsLeader() {
+			leader = member.Name
+		}
+	}
+	if leader == "" {
+		return false, fmt.Errorf("patroni: cluster has no leader to switchover")
+	}
+
+	return c.ChangePrimaryAndWait(ctx, leader, target)
+}
+
+// FailoverAndWait tries to change the current Patroni leader to target by
+// calling the "POST /failover" REST endpoint. It returns true when an election
+// completes successfully. Unlike switchover, this works when there is no
+// leader.
+func (c *Client) FailoverAndWait(
+	ctx context.Context, target string,
+) (bool, error) {
+	return c.changePrimary(ctx, "/failover", "failed over",
+		map[string]string{"candidate": target})
+}
+
+// GetCluster returns the members of the cluster and their state by calling the
+// "GET /cluster" REST endpoint. The scope is ignored; a Client only ever calls
+// one cluster.
+func (c *Client) GetCluster(ctx context.Context, _ string) (ClusterStatus, error) {
+	var status ClusterStatus
+
+	code, content, err := c.do(ctx, http.MethodGet, "/cluster", nil)
+	if err == nil && code != http.StatusOK {
+		err = &APIError{Method: http.MethodGet, Path: "/cluster",
+			StatusCode: code, Message: string(content)}
+	}
+	if err == nil {
+		err = json.Unmarshal(content, &status)
+	}
+
+	return status, err
+}
+
+// memberURL returns the scheme, host, and port of member using MemberURL.
+func (c *Client) memberURL(member string) (*url.URL, error) {
+	if c.MemberURL == nil {
+		return nil, fmt.Errorf("patroni: no URL for member %q", member)
+	}
+	return c.MemberURL(member), nil
+}
+
+// ReinitializeMember reloads the configuration files of member then
+// reinitializes it by calling the "POST /reload" and "POST /reinitialize" REST
+// endpoints of that member. The scope is ignored; a Client only ever calls one
+// cluster.
+func (c *Client) ReinitializeMember(ctx context.Context, _, member string) error {
+	status, err := c.GetCluster(ctx, "")
+
+	var target *url.URL
+	for i := range status.Members {
+		if err == nil && status.Members[i].Name == member {
+			target, err = c.memberURL(member)
+		}
+	}
+	if err == nil && target == nil {
+		err = fmt.Errorf("patroni: cluster has no member %q", member)
+	}
+
+	for _, request := range []struct {
+		path string
+		body interface{}
+	}{
+		// Patroni accepts a reload before it has finished.
+		// - https://github.com/zalando/patroni/blob/v2.1.1/patroni/api.py#L340-L344
+		{path: "/reload"},
+		{path: "/reinitialize", body: map[string]interface{}{"force": true}},
+	} {
+		if err != nil {
+			break
+		}
+
+		var code int
+		var content []byte
+		target.Path = request.path
+		code, content, err = c.do(ctx, http.MethodPost, target.String(), request.body)
+
+		if err == nil && code != http.StatusOK && code != http.StatusAccepted {
+			err = &APIError{Method: http.MethodPost, Path: request.path,
+				StatusCode: code, Message: string(content)}
+		}
+	}
+
+	return err
+}
+
+// ReplaceConfiguration replaces Patroni's entire dynamic configuration by
+// calling the "PUT /config" REST endpoint.
+func (c *Client) ReplaceConfiguration(
+	ctx context.Context, configuration map[string]interface{},
+) error {
+	code, content, err := c.do(ctx, http.MethodPut, "/config", configuration)
+	if err == nil && code != http.StatusOK {
+		err = &APIError{Method: http.MethodPut, Path: "/config",
+			StatusCode: code, Message: string(content)}
+	}
+	return err
+}
+
+// RestartPendingMembers looks up Patroni members with role and restarts those
+// that have a pending restart by calling the "POST /restart" REST endpoint of
+// each. The scope is ignored; a Client only ever calls one cluster.
+func (c *Client) RestartPendingMembers(ctx context.Context, role, _ string) error {
+	status, err := c.GetCluster(ctx, "")
+
+	for _, member := range status.Members {
+		if err != nil {
+			break
+		}
+		if !member.PendingRestart || member.IsLeader() != (role == "master") {
+			continue
+		}
+
+		var target *url.URL
+		target, err = c.memberURL(member.Name)
+		if err != nil {
+			break
+		}
+		target.Path = "/restart"
+
+		var code int
+		var content []byte
+		code, content, err = c.do(ctx, http.MethodPost, target.String(),
+			map[string]interface{}{"restart_pending": true})
+
+		// Patroni responds "Service Unavailable" when a member has already
+		// restarted. That is normal.
+		// - https://github.com/zalando/patroni/blob/v2.1.1/patroni/api.py#L412-L430
+		if err == nil && code != http.StatusOK && code != http.StatusServiceUnavailable {
+			err = &APIError{Method: http.MethodPost, Path: "/restart",
+				StatusCode: code, Message: string(content)}
+		}
+	}
+
+	return err
+}
+
+// clientTLSConfig returns a TLS configuration that verifies servers against
+// root and identifies itself using cert and key.
+func clientTLSConfig(
+	root *pki.Certificate, cert *pki.Certificate, key *pki.PrivateKey,
+) (*tls.Config, error) {
+	rootPEM, err := root.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	certPEM, err := cert.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := key.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(rootPEM) {
+		return nil, fmt.Errorf("patroni: unable to use root certificate")
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{pair},
+		MinVersion:   tls.VersionTLS12,
+		RootCAs:      roots,
+	}, nil
+}
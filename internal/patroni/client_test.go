@@ -0,0 +1,393 @@
+/*
+ Copyright 2021 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package patroni
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/crunchydata/postgres-operator/internal/pki"
+)
+
+// newTestClient starts a local HTTPS stand-in for Patroni that requires client
+// certificates and returns a Client that calls it.
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*Client, *httptest.Server) {
+	t.Helper()
+
+	root := pki.NewRootCertificateAuthority()
+	assert.NilError(t, root.Generate())
+
+	server := pki.NewLeafCertificate("127.0.0.1", nil, []net.IP{net.IPv4(127, 0, 0, 1)})
+	assert.NilError(t, server.Generate(root))
+
+	client := pki.NewLeafCertificate("postgres-operator", nil, nil)
+	assert.NilError(t, client.Generate(root))
+
+	serverCert, _ := server.Certificate.MarshalText()
+	serverKey, _ := server.PrivateKey.MarshalText()
+	serverPair, err := tls.X509KeyPair(serverCert, serverKey)
+	assert.NilError(t, err)
+
+	rootCert, _ := root.Certificate.MarshalText()
+	roots := x509.NewCertPool()
+	assert.Assert(t, roots.AppendCertsFromPEM(rootCert))
+
+	stand := httptest.NewUnstartedServer(handler)
+	stand.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverPair},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    roots,
+	}
+	stand.StartTLS()
+	t.Cleanup(stand.Close)
+
+	api, err := NewClient(stand.URL+"/", root.Certificate, client.Certificate, client.PrivateKey)
+	assert.NilError(t, err)
+	assert.Equal(t, api.BaseURL, stand.URL)
+
+	return api, stand
+}
+
+// memberQuery returns a MemberURL function that calls stand with the name of
+// the member in the query string.
+func memberQuery(t *testing.T, stand *httptest.Server) func(string) *url.URL {
+	return func(member string) *url.URL {
+		u, err := url.Parse(stand.URL + "?member=" + member)
+		assert.NilError(t, err)
+		return u
+	}
+}
+
+// readJSON decodes the body of request.
+func readJSON(t *testing.T, request *http.Request) map[string]interface{} {
+	t.Helper()
+
+	var body map[string]interface{}
+	b, err := ioutil.ReadAll(request.Body)
+	assert.NilError(t, err)
+	assert.NilError(t, json.Unmarshal(b, &body))
+	assert.Equal(t, request.Header.Get("Content-Type"), "application/json")
+	return body
+}
+
+func TestClientMutualTLS(t *testing.T) {
+	ctx := context.Background()
+
+	api, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, len(r.TLS.PeerCertificates), 1)
+		assert.Equal(t, r.TLS.PeerCertificates[0].Subject.CommonName, "postgres-operator")
+		_, _ = w.Write([]byte(`{"members":[]}`))
+	})
+
+	_, err := api.GetCluster(ctx, "")
+	assert.NilError(t, err)
+
+	t.Run("UntrustedServer", func(t *testing.T) {
+		other := pki.NewRootCertificateAuthority()
+		assert.NilError(t, other.Generate())
+
+		client := pki.NewLeafCertificate("postgres-operator", nil, nil)
+		assert.NilError(t, client.Generate(other))
+
+		untrusting, err := NewClient(api.BaseURL,
+			other.Certificate, client.Certificate, client.PrivateKey)
+		assert.NilError(t, err)
+
+		_, err = untrusting.GetCluster(ctx, "")
+		assert.ErrorContains(t, err, "certificate")
+	})
+}
+
+func TestClientChangePrimaryAndWait(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		api, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, r.Method, "POST")
+			assert.Equal(t, r.URL.Path, "/switchover")
+			assert.DeepEqual(t, readJSON(t, r), map[string]interface{}{
+				"leader": "old", "candidate": "new",
+			})
+			_, _ = w.Write([]byte(`Successfully switched over to "new"`))
+		})
+
+		success, err := api.ChangePrimaryAndWait(ctx, "old", "new")
+		assert.NilError(t, err)
+		assert.Assert(t, success)
+	})
+
+	t.Run("AnyCandidate", func(t *testing.T) {
+		api, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.DeepEqual(t, readJSON(t, r), map[string]interface{}{"leader": "old"})
+			_, _ = w.Write([]byte(`Successfully switched over to "whoever"`))
+		})
+
+		success, err := api.ChangePrimaryAndWait(ctx, "old", "")
+		assert.NilError(t, err)
+		assert.Assert(t, success)
+	})
+
+	t.Run("OtherCandidate", func(t *testing.T) {
+		api, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`Switched over to "other" instead of "new"`))
+		})
+
+		success, err := api.ChangePrimaryAndWait(ctx, "old", "new")
+		assert.NilError(t, err)
+		assert.Assert(t, !success)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		api, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			_, _ = w.Write([]byte(`candidate name does not match with sync_standby`))
+		})
+
+		success, err := api.ChangePrimaryAndWait(ctx, "old", "new")
+		assert.Assert(t, !success)
+
+		var apiError *APIError
+		assert.Assert(t, errors.As(err, &apiError))
+		assert.Equal(t, apiError.StatusCode, http.StatusPreconditionFailed)
+		assert.ErrorContains(t, err, "POST /switchover: 412 candidate name")
+	})
+}
+
+func TestClientSwitchoverAndWait(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		api, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/cluster":
+				assert.Equal(t, r.Method, "GET")
+				_, _ = w.Write([]byte(`{"members":[
+					{"name":"one","role":"replica"},
+					{"name":"two","role":"leader"}
+				]}`))
+			case "/switchover":
+				assert.Equal(t, r.Method, "POST")
+				assert.DeepEqual(t, readJSON(t, r), map[string]interface{}{
+					"leader": "two", "candidate": "one",
+				})
+				_, _ = w.Write([]byte(`Successfully switched over to "one"`))
+			default:
+				t.Errorf("unexpected request: %v %v", r.Method, r.URL)
+			}
+		})
+
+		success, err := api.SwitchoverAndWait(ctx, "one")
+		assert.NilError(t, err)
+		assert.Assert(t, success)
+	})
+
+	t.Run("NoLeader", func(t *testing.T) {
+		api, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, r.URL.Path, "/cluster")
+			_, _ = w.Write([]byte(`{"members":[{"name":"one","role":"replica"}]}`))
+		})
+
+		success, err := api.SwitchoverAndWait(ctx, "one")
+		assert.Assert(t, !success)
+		assert.ErrorContains(t, err, "no leader")
+	})
+}
+
+func TestClientFailoverAndWait(t *testing.T) {
+	ctx := context.Background()
+
+	api, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, "POST")
+		assert.Equal(t, r.URL.Path, "/failover")
+		assert.DeepEqual(t, readJSON(t, r), map[string]interface{}{"candidate": "new"})
+		_, _ = w.Write([]byte(`Successfully failed over to "new"`))
+	})
+
+	success, err := api.FailoverAndWait(ctx, "new")
+	assert.NilError(t, err)
+	assert.Assert(t, success)
+}
+
+func TestClientGetCluster(t *testing.T) {
+	ctx := context.Background()
+
+	api, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"members": [
+			{"name": "one", "role": "leader", "state": "running", "host": "one.pods",
+			 "port": 5432, "api_url": "https://one.pods:8008/patroni", "timeline": 4},
+			{"name": "two", "role": "replica", "state": "running", "host": "two.pods",
+			 "port": 5432, "api_url": "https://two.pods:8008/patroni", "timeline": 4,
+			 "lag": 1024, "pending_restart": true, "tags": {"nofailover": true}},
+			{"name": "three", "role": "replica", "state": "starting", "lag": "unknown"}
+		], "pause": true}`))
+	})
+
+	status, err := api.GetCluster(ctx, "")
+	assert.NilError(t, err)
+	assert.Assert(t, status.Paused)
+	assert.Equal(t, len(status.Members), 3)
+
+	assert.Assert(t, status.Members[0].IsLeader())
+	assert.Equal(t, status.Members[0].APIURL, "https://one.pods:8008/patroni")
+	assert.Equal(t, status.Members[0].Timeline, int64(4))
+
+	assert.Assert(t, !status.Members[1].IsLeader())
+	assert.Assert(t, status.Members[1].PendingRestart)
+	assert.DeepEqual(t, status.Members[1].Tags, map[string]interface{}{"nofailover": true})
+
+	lag, known := status.Members[1].LagBytes()
+	assert.Assert(t, known)
+	assert.Equal(t, lag, int64(1024))
+
+	_, known = status.Members[2].LagBytes()
+	assert.Assert(t, !known)
+}
+
+func TestClientReplaceConfiguration(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		api, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, r.Method, "PUT")
+			assert.Equal(t, r.URL.Path, "/config")
+			assert.DeepEqual(t, readJSON(t, r), map[string]interface{}{
+				"some": "values", "ttl": float64(30),
+			})
+			_, _ = w.Write([]byte(`{"some":"values","ttl":30}`))
+		})
+
+		assert.NilError(t, api.ReplaceConfiguration(ctx, map[string]interface{}{
+			"some": "values", "ttl": 30,
+		}))
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		api, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		})
+
+		err := api.ReplaceConfiguration(ctx, map[string]interface{}{})
+		assert.ErrorContains(t, err, "PUT /config: 400")
+	})
+}
+
+func TestClientReinitializeMember(t *testing.T) {
+	ctx := context.Background()
+
+	var requests []string
+	api, stand := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/cluster":
+			members := []ClusterMember{
+				{Name: "leader", Role: "leader"},
+				{Name: "broken", Role: "replica", State: "start failed"},
+			}
+			_ = json.NewEncoder(w).Encode(ClusterStatus{Members: members})
+
+		case "/reload":
+			assert.Equal(t, r.Method, "POST")
+			requests = append(requests, "reload "+r.URL.Query().Get("member"))
+			w.WriteHeader(http.StatusAccepted)
+
+		case "/reinitialize":
+			assert.Equal(t, r.Method, "POST")
+			assert.DeepEqual(t, readJSON(t, r), map[string]interface{}{"force": true})
+
+			member := r.URL.Query().Get("member")
+			requests = append(requests, "reinitialize "+member)
+
+			// Patroni refuses to reinitialize the leader.
+			if member == "leader" {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write([]byte(`I am the leader, can not reinitialize`))
+			}
+
+		default:
+			t.Errorf("unexpected request: %v %v", r.Method, r.URL)
+		}
+	})
+
+	err := api.ReinitializeMember(ctx, "ignored", "broken")
+	assert.ErrorContains(t, err, `no URL for member "broken"`)
+	assert.Assert(t, requests == nil)
+
+	api.MemberURL = memberQuery(t, stand)
+
+	assert.NilError(t, api.ReinitializeMember(ctx, "ignored", "broken"))
+	assert.DeepEqual(t, requests, []string{"reload broken", "reinitialize broken"})
+
+	err = api.ReinitializeMember(ctx, "ignored", "leader")
+	assert.ErrorContains(t, err, "POST /reinitialize: 503")
+
+	requests = nil
+	err = api.ReinitializeMember(ctx, "ignored", "missing")
+	assert.ErrorContains(t, err, `no member "missing"`)
+	assert.Assert(t, requests == nil)
+}
+
+func TestClientRestartPendingMembers(t *testing.T) {
+	ctx := context.Background()
+
+	var restarted []string
+	api, stand := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/cluster":
+			members := []ClusterMember{
+				{Name: "leader", Role: "leader", PendingRestart: true},
+				{Name: "current", Role: "replica", PendingRestart: false},
+				{Name: "pending", Role: "replica", PendingRestart: true},
+				{Name: "finished", Role: "sync_standby", PendingRestart: true},
+			}
+			_ = json.NewEncoder(w).Encode(ClusterStatus{Members: members})
+
+		case "/restart":
+			assert.Equal(t, r.Method, "POST")
+			assert.DeepEqual(t, readJSON(t, r), map[string]interface{}{"restart_pending": true})
+
+			member := r.URL.Query().Get("member")
+			restarted = append(restarted, member)
+
+			// Patroni refuses when the restart is no longer pending.
+			if member == "finished" {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write([]byte(`restart conditions are not satisfied`))
+			}
+
+		default:
+			t.Errorf("unexpected request: %v %v", r.Method, r.URL)
+		}
+	})
+
+	api.MemberURL = memberQuery(t, stand)
+
+	assert.NilError(t, api.RestartPendingMembers(ctx, "replica", "ignored"))
+	assert.DeepEqual(t, restarted, []string{"pending", "finished"})
+
+	restarted = nil
+	assert.NilError(t, api.RestartPendingMembers(ctx, "master", "ignored"))
+	assert.DeepEqual(t, restarted, []string{"leader"})
+}
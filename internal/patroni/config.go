@@ -31,8 +31,63 @@ import (
 const (
 	configDirectory  = "/etc/patroni"
 	configMapFileKey = "patroni.yaml"
+
+	// Patroni reads only YAML files from its configuration directory, so the
+	// callback script can be there, too.
+	callbackConfigPath = "~postgres-operator_callback.py"
+	callbackFileKey    = "patroni-callback.py"
+
+	// callbackRoleLabelFlag tells the callback script to label its Pod with
+	// the role of its member.
+	callbackRoleLabelFlag = "--role-label"
 )
 
+// callbackCommand is the command Patroni runs when PostgreSQL changes role or
+// state. Patroni appends the name of the callback, the role, and the scope.
+// Patroni is written in Python, so there is always a Python interpreter.
+// - https://github.com/zalando/patroni/blob/v2.1.1/patroni/postgresql/callback_executor.py
+var callbackCommand = "python3 " + path.Join(configDirectory, callbackConfigPath)
+
+// callbackScript records the arguments of a Patroni callback in an annotation
+// on its own Pod using the Kubernetes API and the Pod's service account. When
+// called with callbackRoleLabelFlag, it also sets the role label of the Pod.
+//
+// Patroni removes the PATRONI_NAME variable from its environment as it starts,
+// so the script uses the hostname of the Pod, which is the Pod name.
+const callbackScript = `# Generated by postgres-operator. DO NOT EDIT.
+import datetime, json, os, ssl, sys, urllib.request
+
+args = sys.argv[1:]
+label = args[0] == "` + callbackRoleLabelFlag + `"
+if label: args = args[1:]
+
+account = "/var/run/secrets/kubernetes.io/serviceaccount"
+with open(account + "/namespace") as f: namespace = f.read().strip()
+with open(account + "/token") as f: token = f.read().strip()
+
+host = os.environ["KUBERNETES_SERVICE_HOST"]
+if ":" in host: host = "[" + host + "]"
+
+value = json.dumps({
+    "action": args[0], "role": args[1],
+    "time": datetime.datetime.utcnow().strftime("%Y-%m-%dT%H:%M:%SZ"),
+}, sort_keys=True)
+
+metadata = {"annotations": {"` + naming.PatroniCallback + `": value}}
+if label and args[0] != "on_stop":
+    metadata["labels"] = {"` + naming.LabelRole + `": "` + naming.RolePatroniLeader + `"
+        if args[1] in ("master", "standby_leader") else "` + naming.RolePatroniReplica + `"}
+
+request = urllib.request.Request(
+    "https://%s:%s/api/v1/namespaces/%s/pods/%s" % (
+        host, os.environ["KUBERNETES_SERVICE_PORT"], namespace, os.environ["HOSTNAME"]),
+    data=json.dumps({"metadata": metadata}).encode(),
+    headers={"Authorization": "Bearer " + token, "Content-Type": "application/merge-patch+json"},
+    method="PATCH")
+
+urllib.request.urlopen(request, context=ssl.create_default_context(cafile=account + "/ca.crt"), timeout=5)
+`
+
 const (
 	pgBackRestCreateReplicaMethod = "pgbackrest"
 )
@@ -59,27 +114,15 @@ func clusterYAML(
 		// lifetime.
 		"scope": naming.PatroniScope(cluster),
 
-		// Use Kubernetes Endpoints for the distributed configuration store (DCS).
-		// These values cannot change during the cluster's lifetime.
-		//
-		// NOTE(cbandy): It *might* be possible to *carefully* change the role and
-		// scope labels, but there is no way to reconfigure all instances at once.
-		"kubernetes": map[string]interface{}{
-			"namespace":     cluster.Namespace,
-			"role_label":    naming.LabelRole,
-			"scope_label":   naming.LabelPatroni,
-			"use_endpoints": true,
-
-			// In addition to "scope_label" above, Patroni will add the following to
-			// every object it creates. It will also use these as filters when doing
-			// any lookups.
-			"labels": map[string]string{
-				naming.LabelCluster: cluster.Name,
-			},
-		},
-
 		"postgresql": map[string]interface{}{
-			// TODO(cbandy): "callbacks"
+			// Record changes of role and state on the instance Pod. The operator
+			// reports these as Events.
+			// - https://github.com/zalando/patroni/blob/v2.1.1/docs/SETTINGS.rst#postgresql
+			"callbacks": map[string]string{
+				"on_role_change": callbackCommand,
+				"on_start":       callbackCommand,
+				"on_stop":        callbackCommand,
+			},
 
 			// Custom configuration "must exist on all cluster nodes".
 			//
@@ -161,6 +204,43 @@ func clusterYAML(
 		},
 	}
 
+	// The distributed configuration store (DCS) cannot change while any
+	// instance is running. The operator changes it only while the cluster is
+	// shutdown; see DCSType.
+	if dcs := DCSType(cluster); dcs == "Etcd" {
+		root["etcd3"] = etcdSettings(cluster)
+
+		// Every PostgresCluster sharing an etcd cluster has its own keys.
+		// - https://github.com/zalando/patroni/blob/v2.1.1/docs/SETTINGS.rst#global-universal
+		root["namespace"] = "/postgres-operator/" + cluster.Namespace + "/"
+
+		// Patroni labels Pods with their role only when it is using Kubernetes.
+		// Have the callback do it instead.
+		root["postgresql"].(map[string]interface{})["callbacks"] = map[string]string{
+			"on_role_change": callbackCommand + " " + callbackRoleLabelFlag,
+			"on_start":       callbackCommand + " " + callbackRoleLabelFlag,
+			"on_stop":        callbackCommand + " " + callbackRoleLabelFlag,
+		}
+	} else {
+		// Use Kubernetes Endpoints or ConfigMaps.
+		//
+		// NOTE(cbandy): It *might* be possible to *carefully* change the role and
+		// scope labels, but there is no way to reconfigure all instances at once.
+		root["kubernetes"] = map[string]interface{}{
+			"namespace":     cluster.Namespace,
+			"role_label":    naming.LabelRole,
+			"scope_label":   naming.LabelPatroni,
+			"use_endpoints": dcs == "Endpoints",
+
+			// In addition to "scope_label" above, Patroni will add the following to
+			// every object it creates. It will also use these as filters when doing
+			// any lookups.
+			"labels": map[string]string{
+				naming.LabelCluster: cluster.Name,
+			},
+		}
+	}
+
 	if !ClusterBootstrapped(cluster) {
 		// Patroni has not yet bootstrapped. Populate the "bootstrap.dcs" field to
 		// facilitate it. When Patroni is already bootstrapped, this field is ignored.
@@ -203,16 +283,29 @@ func DynamicConfiguration(
 	root["ttl"] = *cluster.Spec.Patroni.LeaderLeaseDurationSeconds
 	root["loop_wait"] = *cluster.Spec.Patroni.SyncPeriodSeconds
 
+	// Patroni leaves maintenance mode when "pause" is missing from its
+	// configuration. The spec controls it, not the "dynamicConfiguration" field.
+	// - https://github.com/zalando/patroni/blob/v2.1.1/docs/pause.rst
+	delete(root, "pause")
+	if ClusterPaused(cluster) {
+		root["pause"] = true
+	}
+
 	// Copy the "postgresql" section before making any changes.
 	postgresql := map[string]interface{}{
-		// TODO(cbandy): explain this. requires an archive, perhaps.
-		"use_slots": false,
+		// Replication slots keep the primary from removing WAL that replicas
+		// still need. A replica that stops could then fill the primary's disk,
+		// so use them by default only where that WAL can be limited.
+		"use_slots": UseSlots(cluster),
 	}
 	if section, ok := root["postgresql"].(map[string]interface{}); ok {
 		for k, v := range section {
 			postgresql[k] = v
 		}
 	}
+	if cluster.Spec.Patroni.UseSlots != nil {
+		postgresql["use_slots"] = *cluster.Spec.Patroni.UseSlots
+	}
 	root["postgresql"] = postgresql
 
 	// Copy the "postgresql.parameters" section over any defaults.
@@ -243,6 +336,13 @@ func DynamicConfiguration(
 			}
 		}
 	}
+	// Limit the WAL kept for replication slots unless something else is specified.
+	// - https://www.postgresql.org/docs/current/runtime-config-replication.html#GUC-MAX-SLOT-WAL-KEEP-SIZE
+	if _, ok := parameters["max_slot_wal_keep_size"]; !ok && cluster.Spec.PostgresVersion >= 13 {
+		if size := slotWALKeepSize(cluster); size > 0 {
+			parameters["max_slot_wal_keep_size"] = fmt.Sprintf("%dMB", size)
+		}
+	}
 	postgresql["parameters"] = parameters
 
 	// Copy the "postgresql.pg_hba" section after any mandatory values.
@@ -269,6 +369,69 @@ func DynamicConfiguration(
 	// TODO(cbandy): explain this.
 	postgresql["use_pg_rewind"] = true
 
+	// Typed permanent slots override any in the "dynamicConfiguration" field.
+	// Patroni copies logical slots to replicas, which needs feedback from them.
+	// - https://github.com/zalando/patroni/blob/v2.1.1/docs/SETTINGS.rst#dynamic-configuration-settings
+	if spec := cluster.Spec.Patroni.PermanentSlots; len(spec) > 0 {
+		slots := make(map[string]interface{})
+		if section, ok := root["slots"].(map[string]interface{}); ok {
+			for k, v := range section {
+				slots[k] = v
+			}
+		}
+		for _, slot := range spec {
+			if slot.Type != "Logical" {
+				slots[slot.Name] = map[string]interface{}{"type": "physical"}
+			} else if slot.Database != "" {
+				plugin := slot.Plugin
+				if plugin == "" {
+					plugin = "pgoutput"
+				}
+				slots[slot.Name] = map[string]interface{}{
+					"type": "logical", "database": slot.Database, "plugin": plugin,
+				}
+				parameters["hot_standby_feedback"] = "on"
+			}
+		}
+		root["slots"] = slots
+	}
+
+	// Typed failover settings override any in the "dynamicConfiguration" field.
+	// - https://github.com/zalando/patroni/blob/v2.1.1/docs/SETTINGS.rst#dynamic-configuration-settings
+	if spec := cluster.Spec.Patroni.Failover; spec != nil {
+		root["maximum_lag_on_failover"] = maximumLagOnFailover(spec)
+
+		root["master_start_timeout"] = int32(300)
+		if spec.PrimaryStartTimeoutSeconds != nil {
+			root["master_start_timeout"] = *spec.PrimaryStartTimeoutSeconds
+		}
+
+		root["check_timeline"] = true
+		if spec.CheckTimeline != nil {
+			root["check_timeline"] = *spec.CheckTimeline
+		}
+
+		// Patroni before 3.0 ignores this setting.
+		if spec.FailsafeMode {
+			root["failsafe_mode"] = true
+		} else {
+			delete(root, "failsafe_mode")
+		}
+	}
+
+	// Typed synchronous settings override any in the "dynamicConfiguration" field.
+	// They are not applied when there are not enough instances for the requested
+	// number of synchronous standbys.
+	// - https://github.com/zalando/patroni/blob/v2.1.1/docs/replication_modes.rst
+	if spec := cluster.Spec.Patroni.Synchronous; spec != nil &&
+		SynchronousStandbyCount(cluster) <= SynchronousStandbyCandidates(cluster) {
+		root["synchronous_mode"] = spec.Mode != "Off"
+		root["synchronous_mode_strict"] = spec.Mode == "Strict"
+		if count := SynchronousStandbyCount(cluster); count > 0 {
+			root["synchronous_node_count"] = count
+		}
+	}
+
 	if cluster.Spec.Standby != nil && cluster.Spec.Standby.Enabled {
 		// Copy the "standby_cluster" section before making any changes.
 		standby := make(map[string]interface{})
@@ -294,6 +457,27 @@ func DynamicConfiguration(
 	return root
 }
 
+// etcdSettings returns the Patroni settings for connecting to the etcd cluster
+// of cluster.
+// - https://github.com/zalando/patroni/blob/v2.1.1/docs/SETTINGS.rst#etcdv3
+func etcdSettings(cluster *v1beta1.PostgresCluster) map[string]interface{} {
+	settings := map[string]interface{}{"hosts": []string{}}
+
+	if spec := cluster.Spec.Patroni; spec != nil && spec.DCS != nil && spec.DCS.Etcd != nil {
+		settings["hosts"] = spec.DCS.Etcd.Hosts
+
+		if spec.DCS.Etcd.TLSSecret != nil {
+			// NOTE(cbandy): The path package always uses slash separators.
+			settings["protocol"] = "https"
+			settings["cacert"] = path.Join(configDirectory, etcdAuthorityConfigPath)
+			settings["cert"] = path.Join(configDirectory, etcdCertificateConfigPath)
+			settings["key"] = path.Join(configDirectory, etcdPrivateKeyConfigPath)
+		}
+	}
+
+	return settings
+}
+
 // instanceEnvironment returns the environment variables needed by Patroni's
 // instance container.
 func instanceEnvironment(
@@ -416,6 +600,18 @@ func instanceEnvironment(
 		},
 	}
 
+	// Patroni uses the first DCS it finds in its configuration. Leave out the
+	// Kubernetes settings when it is using something else.
+	if DCSType(cluster) == "Etcd" {
+		filtered := variables[:0]
+		for _, v := range variables {
+			if !strings.HasPrefix(v.Name, "PATRONI_KUBERNETES_") {
+				filtered = append(filtered, v)
+			}
+		}
+		variables = filtered
+	}
+
 	return variables
 }
 
@@ -431,6 +627,9 @@ func instanceConfigFiles(cluster, instance *corev1.ConfigMap) []corev1.VolumePro
 				Items: []corev1.KeyToPath{{
 					Key:  configMapFileKey,
 					Path: "~postgres-operator_cluster.yaml",
+				}, {
+					Key:  callbackFileKey,
+					Path: callbackConfigPath,
 				}},
 			},
 		},
@@ -448,6 +647,159 @@ func instanceConfigFiles(cluster, instance *corev1.ConfigMap) []corev1.VolumePro
 	}
 }
 
+// NoFailover returns true when members of instance should never be promoted.
+// Delayed replicas are never promoted.
+func NoFailover(instance *v1beta1.PostgresInstanceSetSpec) bool {
+	return instance != nil && (instance.RecoveryMinApplyDelay != nil ||
+		(instance.PatroniTags != nil && instance.PatroniTags.NoFailover))
+}
+
+// NoLoadBalance returns true when members of instance should not receive
+// traffic from the replica Service. Delayed replicas receive no traffic.
+func NoLoadBalance(instance *v1beta1.PostgresInstanceSetSpec) bool {
+	return instance != nil && (instance.RecoveryMinApplyDelay != nil ||
+		(instance.PatroniTags != nil && instance.PatroniTags.NoLoadBalance))
+}
+
+// AnyNoLoadBalance returns true when members of any instance set of cluster
+// should not receive traffic from the replica Service.
+func AnyNoLoadBalance(cluster *v1beta1.PostgresCluster) bool {
+	for i := range cluster.Spec.InstanceSets {
+		if NoLoadBalance(&cluster.Spec.InstanceSets[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// NoSync returns true when members of instance should never be synchronous
+// standbys of cluster.
+func NoSync(cluster *v1beta1.PostgresCluster, instance *v1beta1.PostgresInstanceSetSpec) bool {
+	if instance == nil {
+		return false
+	}
+	if instance.PatroniTags != nil && instance.PatroniTags.NoSync {
+		return true
+	}
+	if cluster.Spec.Patroni != nil && cluster.Spec.Patroni.Synchronous != nil {
+		if sets := cluster.Spec.Patroni.Synchronous.InstanceSets; len(sets) > 0 {
+			for _, name := range sets {
+				if name == instance.Name {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// SynchronousStandbyCount returns the number of synchronous standbys requested
+// for cluster, or zero when synchronous replication is not enabled.
+func SynchronousStandbyCount(cluster *v1beta1.PostgresCluster) int32 {
+	if cluster.Spec.Patroni == nil || cluster.Spec.Patroni.Synchronous == nil ||
+		cluster.Spec.Patroni.Synchronous.Mode == "Off" {
+		return 0
+	}
+	if count := cluster.Spec.Patroni.Synchronous.StandbyCount; count != nil {
+		return *count
+	}
+	return 1
+}
+
+// SynchronousStandbyCandidates returns the number of instances of cluster that
+// can be synchronous standbys at any one time. One eligible instance is not
+// counted when it might be the primary.
+func SynchronousStandbyCandidates(cluster *v1beta1.PostgresCluster) int32 {
+	var candidates int32
+	var primary bool
+	for i := range cluster.Spec.InstanceSets {
+		instance := &cluster.Spec.InstanceSets[i]
+		if NoSync(cluster, instance) {
+			continue
+		}
+
+		replicas := int32(1)
+		if instance.Replicas != nil {
+			replicas = *instance.Replicas
+		}
+		candidates += replicas
+		primary = primary || (replicas > 0 && !NoFailover(instance))
+	}
+	if primary {
+		candidates--
+	}
+	return candidates
+}
+
+// UseSlots returns the default of Patroni "use_slots" for cluster. Slots are
+// used when PostgreSQL can limit the WAL they keep or when the spec has slots
+// that must survive failover.
+func UseSlots(cluster *v1beta1.PostgresCluster) bool {
+	return cluster.Spec.PostgresVersion >= 13 ||
+		(cluster.Spec.Patroni != nil && len(cluster.Spec.Patroni.PermanentSlots) > 0)
+}
+
+// slotWALKeepSize returns half the size, in megabytes, of the smallest volume
+// that holds WAL in cluster. It returns zero when no volume has a size.
+func slotWALKeepSize(cluster *v1beta1.PostgresCluster) int64 {
+	var smallest int64
+	for i := range cluster.Spec.InstanceSets {
+		claim := &cluster.Spec.InstanceSets[i].DataVolumeClaimSpec
+		if wal := cluster.Spec.InstanceSets[i].WALVolumeClaimSpec; wal != nil {
+			claim = wal
+		}
+		if size, ok := claim.Resources.Requests[corev1.ResourceStorage]; ok {
+			if v := size.Value(); v > 0 && (smallest == 0 || v < smallest) {
+				smallest = v
+			}
+		}
+	}
+	return smallest / 2 / (1024 * 1024)
+}
+
+// RecoveryMinApplyDelay returns the value of the "recovery_min_apply_delay"
+// parameter for members of instance, if any.
+// - https://www.postgresql.org/docs/current/runtime-config-replication.html#GUC-RECOVERY-MIN-APPLY-DELAY
+func RecoveryMinApplyDelay(instance *v1beta1.PostgresInstanceSetSpec) string {
+	if instance == nil || instance.RecoveryMinApplyDelay == nil {
+		return ""
+	}
+	return fmt.Sprintf("%dms", instance.RecoveryMinApplyDelay.Milliseconds())
+}
+
+// instanceTags returns the Patroni tags of members of instance. Patroni reads
+// these only when it starts.
+// - https://github.com/zalando/patroni/blob/v2.0.2/docs/SETTINGS.rst#tags
+func instanceTags(
+	cluster *v1beta1.PostgresCluster, instance *v1beta1.PostgresInstanceSetSpec,
+) map[string]interface{} {
+	tags := map[string]interface{}{}
+
+	if NoFailover(instance) {
+		tags["nofailover"] = true
+	}
+	if NoLoadBalance(instance) {
+		tags["noloadbalance"] = true
+	}
+	if NoSync(cluster, instance) {
+		tags["nosync"] = true
+	}
+	if spec := instance.PatroniTags; spec != nil {
+		if spec.CloneFrom {
+			tags["clonefrom"] = true
+		}
+
+		// The Patroni member name is the name of the instance Pod, which is
+		// the first and only Pod of its StatefulSet.
+		if spec.ReplicateFrom != "" {
+			tags["replicatefrom"] = spec.ReplicateFrom + "-0"
+		}
+	}
+
+	return tags
+}
+
 // instanceYAML returns Patroni settings that apply to instance.
 func instanceYAML(
 	cluster *v1beta1.PostgresCluster, instance *v1beta1.PostgresInstanceSetSpec,
@@ -476,10 +828,7 @@ func instanceYAML(
 			// See the PATRONI_RESTAPI_LISTEN environment variable.
 		},
 
-		"tags": map[string]interface{}{
-			// TODO(cbandy): "nofailover"
-			// TODO(cbandy): "nosync"
-		},
+		"tags": instanceTags(cluster, instance),
 	}
 
 	postgresql := map[string]interface{}{
@@ -503,6 +852,15 @@ func instanceYAML(
 	}
 	root["postgresql"] = postgresql
 
+	// Parameters here take precedence over those in DCS but apply only to
+	// members of this instance set. PostgreSQL ignores this one on a primary.
+	// - https://github.com/zalando/patroni/blob/v2.0.2/docs/SETTINGS.rst#postgresql
+	if delay := RecoveryMinApplyDelay(instance); delay != "" {
+		postgresql["parameters"] = map[string]interface{}{
+			"recovery_min_apply_delay": delay,
+		}
+	}
+
 	// The "basebackup" replica method is configured differently from others.
 	// Patroni prepends "--" before it calls `pg_basebackup`.
 	// - https://github.com/zalando/patroni/blob/v2.0.2/patroni/postgresql/bootstrap.py#L45
@@ -513,7 +871,8 @@ func instanceYAML(
 	methods := []string{"basebackup"}
 
 	// Prefer a pgBackRest method when it is available, and fallback to other
-	// methods when it fails.
+	// methods when it fails. When the primary is the preferred source, try
+	// pgBackRest only after "basebackup" fails.
 	if command := pgbackrestReplicaCreateCommand; len(command) > 0 {
 
 		// Regardless of the "keep_data" setting below, Patroni deletes the
@@ -543,7 +902,12 @@ func instanceYAML(
 			"no_master": true,
 			"no_params": true,
 		}
-		methods = append([]string{pgBackRestCreateReplicaMethod}, methods...)
+		if spec := cluster.Spec.Patroni; spec != nil && spec.Reinitialization != nil &&
+			spec.Reinitialization.Source == "Primary" {
+			methods = append(methods, pgBackRestCreateReplicaMethod)
+		} else {
+			methods = append([]string{pgBackRestCreateReplicaMethod}, methods...)
+		}
 	}
 
 	// NOTE(cbandy): Is there any chance a user might want to specify their own
@@ -603,12 +967,26 @@ func instanceYAML(
 	return string(append([]byte(yamlGeneratedWarning), b...)), err
 }
 
+// maximumLagOnFailover returns the number of bytes a replica can be behind the
+// primary and still be promoted according to spec. It defaults to 1Mi.
+func maximumLagOnFailover(spec *v1beta1.PatroniFailoverLimits) int64 {
+	if spec.MaximumLag == nil {
+		return 1024 * 1024
+	}
+	return spec.MaximumLag.Value()
+}
+
 // probeTiming returns a Probe with thresholds and timeouts set according to spec.
 func probeTiming(spec *v1beta1.PatroniSpec) *corev1.Probe {
 	// "Probes should be configured in such a way that they start failing about
 	// time when the leader key is expiring."
 	// - https://github.com/zalando/patroni/blob/v2.0.1/docs/rest_api.rst
 	// - https://github.com/zalando/patroni/blob/v2.0.1/docs/watchdog.rst
+	//
+	// The failover limits decide which replica is promoted, not when. Patroni
+	// keeps its loop running while it waits for a primary to start, so these
+	// thresholds hold. Patroni enforces the lag limit itself; a replica that
+	// is behind is still ready to serve reads.
 
 	// TODO(cbandy): When the probe times out, failure triggers at
 	// (FailureThreshold × PeriodSeconds + TimeoutSeconds)
@@ -626,5 +1004,12 @@ func probeTiming(spec *v1beta1.PatroniSpec) *corev1.Probe {
 		probe.FailureThreshold = 1
 	}
 
+	// In failsafe mode, a primary that cannot reach the DCS calls every member
+	// before its loop continues. Allow that loop one more period.
+	// - https://patroni.readthedocs.io/en/latest/dcs_failsafe_mode.html
+	if spec.Failover != nil && spec.Failover.FailsafeMode {
+		probe.FailureThreshold++
+	}
+
 	return &probe
 }
@@ -21,12 +21,15 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"gotest.tools/v3/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/yaml"
 
+	"github.com/crunchydata/postgres-operator/internal/initialize"
 	"github.com/crunchydata/postgres-operator/internal/postgres"
 	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
@@ -79,6 +82,10 @@ postgresql:
       sslmode: verify-ca
       sslrootcert: /tmp/replication/ca.crt
       username: _crunchyrepl
+  callbacks:
+    on_role_change: python3 /etc/patroni/~postgres-operator_callback.py
+    on_start: python3 /etc/patroni/~postgres-operator_callback.py
+    on_stop: python3 /etc/patroni/~postgres-operator_callback.py
 restapi:
   cafile: /etc/patroni/~postgres-operator/patroni.ca-roots
   certfile: /etc/patroni/~postgres-operator/patroni.crt+key
@@ -90,6 +97,76 @@ watchdog:
 	`)+"\n")
 }
 
+func TestClusterYAMLDCS(t *testing.T) {
+	t.Parallel()
+
+	parse := func(t *testing.T, cluster *v1beta1.PostgresCluster) map[string]interface{} {
+		data, err := clusterYAML(cluster, postgres.HBAs{}, postgres.Parameters{})
+		assert.NilError(t, err)
+
+		var parsed map[string]interface{}
+		assert.NilError(t, yaml.Unmarshal([]byte(data), &parsed))
+		return parsed
+	}
+
+	cluster := new(v1beta1.PostgresCluster)
+	cluster.Default()
+	cluster.Namespace = "some-namespace"
+	cluster.Name = "cluster-name"
+
+	t.Run("ConfigMaps", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Patroni.DCS = &v1beta1.PatroniDCS{Type: "ConfigMaps"}
+
+		parsed := parse(t, cluster)
+		assert.Equal(t, parsed["kubernetes"].(map[string]interface{})["use_endpoints"], false)
+		assert.Assert(t, parsed["etcd3"] == nil)
+	})
+
+	t.Run("Etcd", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Patroni.DCS = &v1beta1.PatroniDCS{
+			Type: "Etcd",
+			Etcd: &v1beta1.PatroniEtcd{
+				Hosts:     []string{"etcd-0:2379", "etcd-1:2379"},
+				TLSSecret: &corev1.SecretProjection{},
+			},
+		}
+
+		parsed := parse(t, cluster)
+		assert.Assert(t, parsed["kubernetes"] == nil)
+		assert.Equal(t, parsed["namespace"], "/postgres-operator/some-namespace/")
+		assert.DeepEqual(t, parsed["etcd3"], map[string]interface{}{
+			"cacert":   "/etc/patroni/~postgres-operator/etcd-ca.crt",
+			"cert":     "/etc/patroni/~postgres-operator/etcd-tls.crt",
+			"hosts":    []interface{}{"etcd-0:2379", "etcd-1:2379"},
+			"key":      "/etc/patroni/~postgres-operator/etcd-tls.key",
+			"protocol": "https",
+		})
+
+		// The callback labels Pods with their role.
+		callbacks := parsed["postgresql"].(map[string]interface{})["callbacks"].(map[string]interface{})
+		assert.Equal(t, callbacks["on_role_change"],
+			"python3 /etc/patroni/~postgres-operator_callback.py --role-label")
+
+		// Patroni is not told about Kubernetes at all.
+		for _, v := range instanceEnvironment(cluster, new(corev1.Service), new(corev1.Service), nil) {
+			assert.Assert(t, !strings.HasPrefix(v.Name, "PATRONI_KUBERNETES_"), "got %q", v.Name)
+		}
+	})
+
+	t.Run("Status", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Patroni.DCS = &v1beta1.PatroniDCS{Type: "Etcd"}
+		cluster.Status.Patroni.DCS = "Endpoints"
+
+		// The store in use takes precedence over the spec.
+		parsed := parse(t, cluster)
+		assert.Equal(t, parsed["kubernetes"].(map[string]interface{})["use_endpoints"], true)
+		assert.Assert(t, parsed["etcd3"] == nil)
+	})
+}
+
 func TestDynamicConfiguration(t *testing.T) {
 	t.Parallel()
 
@@ -537,6 +614,311 @@ func TestDynamicConfiguration(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "pause: spec overrides input",
+			cluster: &v1beta1.PostgresCluster{
+				Spec: v1beta1.PostgresClusterSpec{
+					Patroni: &v1beta1.PatroniSpec{Paused: true},
+				},
+			},
+			input: map[string]interface{}{
+				"pause": false,
+			},
+			expected: map[string]interface{}{
+				"loop_wait": int32(10),
+				"ttl":       int32(30),
+				"pause":     true,
+				"postgresql": map[string]interface{}{
+					"parameters":    map[string]interface{}{},
+					"pg_hba":        []string{},
+					"use_pg_rewind": true,
+					"use_slots":     false,
+				},
+			},
+		},
+		{
+			name: "pause: removed when not paused",
+			input: map[string]interface{}{
+				"pause": true,
+			},
+			expected: map[string]interface{}{
+				"loop_wait": int32(10),
+				"ttl":       int32(30),
+				"postgresql": map[string]interface{}{
+					"parameters":    map[string]interface{}{},
+					"pg_hba":        []string{},
+					"use_pg_rewind": true,
+					"use_slots":     false,
+				},
+			},
+		},
+		{
+			name: "slots: limited on PostgreSQL 13",
+			cluster: &v1beta1.PostgresCluster{
+				Spec: v1beta1.PostgresClusterSpec{
+					PostgresVersion: 13,
+					InstanceSets: []v1beta1.PostgresInstanceSetSpec{
+						{DataVolumeClaimSpec: corev1.PersistentVolumeClaimSpec{
+							Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+								corev1.ResourceStorage: resource.MustParse("10Gi"),
+							}},
+						}},
+						{
+							DataVolumeClaimSpec: corev1.PersistentVolumeClaimSpec{
+								Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+									corev1.ResourceStorage: resource.MustParse("10Gi"),
+								}},
+							},
+							WALVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
+								Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+									corev1.ResourceStorage: resource.MustParse("1Gi"),
+								}},
+							},
+						},
+					},
+				},
+			},
+			expected: map[string]interface{}{
+				"loop_wait": int32(10),
+				"ttl":       int32(30),
+				"postgresql": map[string]interface{}{
+					"parameters": map[string]interface{}{
+						"max_slot_wal_keep_size": "512MB",
+					},
+					"pg_hba":        []string{},
+					"use_pg_rewind": true,
+					"use_slots":     true,
+				},
+			},
+		},
+		{
+			name: "slots: input parameter and spec override defaults",
+			cluster: &v1beta1.PostgresCluster{
+				Spec: v1beta1.PostgresClusterSpec{
+					PostgresVersion: 14,
+					Patroni: &v1beta1.PatroniSpec{
+						UseSlots: initialize.Bool(false),
+					},
+					InstanceSets: []v1beta1.PostgresInstanceSetSpec{
+						{DataVolumeClaimSpec: corev1.PersistentVolumeClaimSpec{
+							Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+								corev1.ResourceStorage: resource.MustParse("10Gi"),
+							}},
+						}},
+					},
+				},
+			},
+			input: map[string]interface{}{
+				"postgresql": map[string]interface{}{
+					"parameters": map[string]interface{}{
+						"max_slot_wal_keep_size": "-1",
+					},
+					"use_slots": true,
+				},
+			},
+			expected: map[string]interface{}{
+				"loop_wait": int32(10),
+				"ttl":       int32(30),
+				"postgresql": map[string]interface{}{
+					"parameters": map[string]interface{}{
+						"max_slot_wal_keep_size": "-1",
+					},
+					"pg_hba":        []string{},
+					"use_pg_rewind": true,
+					"use_slots":     false,
+				},
+			},
+		},
+		{
+			name: "slots: permanent slots override input",
+			cluster: &v1beta1.PostgresCluster{
+				Spec: v1beta1.PostgresClusterSpec{
+					Patroni: &v1beta1.PatroniSpec{
+						PermanentSlots: []v1beta1.PatroniPermanentSlot{
+							{Name: "standby", Type: "Physical"},
+							{Name: "cdc", Type: "Logical", Database: "app"},
+							{Name: "decoder", Type: "Logical", Database: "app", Plugin: "wal2json"},
+							{Name: "nowhere", Type: "Logical"},
+						},
+					},
+				},
+			},
+			input: map[string]interface{}{
+				"slots": map[string]interface{}{
+					"other":   map[string]interface{}{"type": "physical"},
+					"standby": map[string]interface{}{"type": "logical"},
+				},
+			},
+			expected: map[string]interface{}{
+				"loop_wait": int32(10),
+				"ttl":       int32(30),
+				"postgresql": map[string]interface{}{
+					"parameters": map[string]interface{}{
+						"hot_standby_feedback": "on",
+					},
+					"pg_hba":        []string{},
+					"use_pg_rewind": true,
+					"use_slots":     true,
+				},
+				"slots": map[string]interface{}{
+					"cdc": map[string]interface{}{
+						"type": "logical", "database": "app", "plugin": "pgoutput",
+					},
+					"decoder": map[string]interface{}{
+						"type": "logical", "database": "app", "plugin": "wal2json",
+					},
+					"other":   map[string]interface{}{"type": "physical"},
+					"standby": map[string]interface{}{"type": "physical"},
+				},
+			},
+		},
+		{
+			name: "failover: spec overrides input",
+			cluster: &v1beta1.PostgresCluster{
+				Spec: v1beta1.PostgresClusterSpec{
+					Patroni: &v1beta1.PatroniSpec{
+						Failover: &v1beta1.PatroniFailoverLimits{
+							MaximumLag:                 resource.NewQuantity(4096, resource.BinarySI),
+							PrimaryStartTimeoutSeconds: newInt32(0),
+							FailsafeMode:               true,
+						},
+					},
+				},
+			},
+			input: map[string]interface{}{
+				"check_timeline":          false,
+				"maximum_lag_on_failover": 999999999,
+				"master_start_timeout":    60,
+			},
+			expected: map[string]interface{}{
+				"loop_wait":               int32(10),
+				"ttl":                     int32(30),
+				"check_timeline":          true,
+				"failsafe_mode":           true,
+				"maximum_lag_on_failover": int64(4096),
+				"master_start_timeout":    int32(0),
+				"postgresql": map[string]interface{}{
+					"parameters":    map[string]interface{}{},
+					"pg_hba":        []string{},
+					"use_pg_rewind": true,
+					"use_slots":     false,
+				},
+			},
+		},
+		{
+			name: "failover: defaults",
+			cluster: &v1beta1.PostgresCluster{
+				Spec: v1beta1.PostgresClusterSpec{
+					Patroni: &v1beta1.PatroniSpec{
+						Failover: &v1beta1.PatroniFailoverLimits{},
+					},
+				},
+			},
+			input: map[string]interface{}{
+				"failsafe_mode": true,
+			},
+			expected: map[string]interface{}{
+				"loop_wait":               int32(10),
+				"ttl":                     int32(30),
+				"check_timeline":          true,
+				"maximum_lag_on_failover": int64(1024 * 1024),
+				"master_start_timeout":    int32(300),
+				"postgresql": map[string]interface{}{
+					"parameters":    map[string]interface{}{},
+					"pg_hba":        []string{},
+					"use_pg_rewind": true,
+					"use_slots":     false,
+				},
+			},
+		},
+		{
+			name: "synchronous: spec overrides input",
+			cluster: &v1beta1.PostgresCluster{
+				Spec: v1beta1.PostgresClusterSpec{
+					InstanceSets: []v1beta1.PostgresInstanceSetSpec{
+						{Name: "a", Replicas: newInt32(3)},
+					},
+					Patroni: &v1beta1.PatroniSpec{
+						Synchronous: &v1beta1.PatroniSynchronous{
+							Mode:         "Strict",
+							StandbyCount: newInt32(2),
+						},
+					},
+				},
+			},
+			input: map[string]interface{}{
+				"synchronous_mode":       false,
+				"synchronous_node_count": 5,
+			},
+			expected: map[string]interface{}{
+				"loop_wait": int32(10),
+				"ttl":       int32(30),
+				"postgresql": map[string]interface{}{
+					"parameters":    map[string]interface{}{},
+					"pg_hba":        []string{},
+					"use_pg_rewind": true,
+					"use_slots":     false,
+				},
+				"synchronous_mode":        true,
+				"synchronous_mode_strict": true,
+				"synchronous_node_count":  int32(2),
+			},
+		},
+		{
+			name: "synchronous: not enough instances",
+			cluster: &v1beta1.PostgresCluster{
+				Spec: v1beta1.PostgresClusterSpec{
+					InstanceSets: []v1beta1.PostgresInstanceSetSpec{
+						{Name: "a", Replicas: newInt32(2)},
+					},
+					Patroni: &v1beta1.PatroniSpec{
+						Synchronous: &v1beta1.PatroniSynchronous{
+							Mode:         "Strict",
+							StandbyCount: newInt32(2),
+						},
+					},
+				},
+			},
+			input: map[string]interface{}{
+				"synchronous_mode": false,
+			},
+			expected: map[string]interface{}{
+				"loop_wait": int32(10),
+				"ttl":       int32(30),
+				"postgresql": map[string]interface{}{
+					"parameters":    map[string]interface{}{},
+					"pg_hba":        []string{},
+					"use_pg_rewind": true,
+					"use_slots":     false,
+				},
+				"synchronous_mode": false,
+			},
+		},
+		{
+			name: "synchronous: off",
+			cluster: &v1beta1.PostgresCluster{
+				Spec: v1beta1.PostgresClusterSpec{
+					Patroni: &v1beta1.PatroniSpec{
+						Synchronous: &v1beta1.PatroniSynchronous{Mode: "Off"},
+					},
+				},
+			},
+			input: map[string]interface{}{
+				"synchronous_mode": true,
+			},
+			expected: map[string]interface{}{
+				"loop_wait": int32(10),
+				"ttl":       int32(30),
+				"postgresql": map[string]interface{}{
+					"parameters":    map[string]interface{}{},
+					"pg_hba":        []string{},
+					"use_pg_rewind": true,
+					"use_slots":     false,
+				},
+				"synchronous_mode":        false,
+				"synchronous_mode_strict": false,
+			},
+		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			cluster := tt.cluster
@@ -563,6 +945,8 @@ func TestInstanceConfigFiles(t *testing.T) {
     items:
     - key: patroni.yaml
       path: ~postgres-operator_cluster.yaml
+    - key: patroni-callback.py
+      path: ~postgres-operator_callback.py
     name: cm1
 - configMap:
     items:
@@ -716,6 +1100,153 @@ postgresql:
 restapi: {}
 tags: {}
 	`, "\t\n")+"\n")
+
+	fromPrimary := cluster.DeepCopy()
+	fromPrimary.Spec.Patroni = &v1beta1.PatroniSpec{
+		Reinitialization: &v1beta1.PatroniReinitialization{Source: "Primary"},
+	}
+
+	dataFromPrimary, err := instanceYAML(fromPrimary, instance, []string{"some", "backrest", "cmd"})
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(dataFromPrimary, `
+  create_replica_methods:
+  - basebackup
+  - pgbackrest
+`), "expected pgBackRest after basebackup, got:\n%s", dataFromPrimary)
+
+	delayed := instance.DeepCopy()
+	delayed.RecoveryMinApplyDelay = &metav1.Duration{Duration: time.Hour}
+
+	dataWithDelay, err := instanceYAML(cluster, delayed, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, dataWithDelay, strings.Trim(`
+# Generated by postgres-operator. DO NOT EDIT.
+# Your changes will not be saved.
+bootstrap:
+  initdb:
+  - data-checksums
+  - encoding=UTF8
+  - waldir=/pgdata/pg12_wal
+  method: initdb
+kubernetes: {}
+postgresql:
+  basebackup:
+  - waldir=/pgdata/pg12_wal
+  create_replica_methods:
+  - basebackup
+  parameters:
+    recovery_min_apply_delay: 3600000ms
+  pgpass: /tmp/.pgpass
+  use_unix_socket: true
+restapi: {}
+tags:
+  nofailover: true
+  noloadbalance: true
+	`, "\t\n")+"\n")
+}
+
+func TestInstanceTags(t *testing.T) {
+	t.Parallel()
+
+	cluster := new(v1beta1.PostgresCluster)
+	instance := new(v1beta1.PostgresInstanceSetSpec)
+	assert.DeepEqual(t, instanceTags(cluster, instance), map[string]interface{}{})
+	assert.Assert(t, !NoFailover(instance))
+	assert.Assert(t, !NoLoadBalance(instance))
+
+	instance.PatroniTags = &v1beta1.PatroniTags{
+		CloneFrom:     true,
+		NoFailover:    true,
+		NoLoadBalance: true,
+		NoSync:        true,
+		ReplicateFrom: "some-instance",
+	}
+	assert.DeepEqual(t, instanceTags(cluster, instance), map[string]interface{}{
+		"clonefrom":     true,
+		"nofailover":    true,
+		"noloadbalance": true,
+		"nosync":        true,
+		"replicatefrom": "some-instance-0",
+	})
+	assert.Assert(t, NoFailover(instance))
+	assert.Assert(t, NoLoadBalance(instance))
+
+	assert.Assert(t, !AnyNoLoadBalance(cluster))
+	cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{{Name: "00"}, *instance}
+	assert.Assert(t, AnyNoLoadBalance(cluster))
+
+	t.Run("RecoveryMinApplyDelay", func(t *testing.T) {
+		instance := new(v1beta1.PostgresInstanceSetSpec)
+		assert.Equal(t, RecoveryMinApplyDelay(instance), "")
+
+		instance.RecoveryMinApplyDelay = &metav1.Duration{Duration: 90 * time.Second}
+		assert.Equal(t, RecoveryMinApplyDelay(instance), "90000ms")
+		assert.Assert(t, NoFailover(instance))
+		assert.Assert(t, NoLoadBalance(instance))
+		assert.DeepEqual(t, instanceTags(cluster, instance), map[string]interface{}{
+			"nofailover":    true,
+			"noloadbalance": true,
+		})
+	})
+}
+
+func TestSynchronousStandbys(t *testing.T) {
+	t.Parallel()
+
+	replicas := func(i int32) *int32 { return &i }
+
+	cluster := new(v1beta1.PostgresCluster)
+	cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{
+		{Name: "a", Replicas: replicas(2)},
+		{Name: "b", Replicas: replicas(3), PatroniTags: &v1beta1.PatroniTags{NoFailover: true}},
+		{Name: "c", PatroniTags: &v1beta1.PatroniTags{NoSync: true}},
+	}
+	a, b, c := &cluster.Spec.InstanceSets[0], &cluster.Spec.InstanceSets[1], &cluster.Spec.InstanceSets[2]
+
+	assert.Equal(t, SynchronousStandbyCount(cluster), int32(0))
+	assert.Assert(t, !NoSync(cluster, a))
+	assert.Assert(t, !NoSync(cluster, b))
+	assert.Assert(t, NoSync(cluster, c))
+
+	// The primary might be in set "a".
+	assert.Equal(t, SynchronousStandbyCandidates(cluster), int32(4))
+
+	cluster.Spec.Patroni = &v1beta1.PatroniSpec{
+		Synchronous: &v1beta1.PatroniSynchronous{InstanceSets: []string{"b"}},
+	}
+	assert.Equal(t, SynchronousStandbyCount(cluster), int32(1))
+	assert.Assert(t, NoSync(cluster, a))
+	assert.Assert(t, !NoSync(cluster, b))
+	assert.Assert(t, NoSync(cluster, c))
+	assert.DeepEqual(t, instanceTags(cluster, a), map[string]interface{}{"nosync": true})
+
+	// Set "b" cannot have the primary.
+	assert.Equal(t, SynchronousStandbyCandidates(cluster), int32(3))
+
+	cluster.Spec.Patroni.Synchronous.StandbyCount = replicas(2)
+	assert.Equal(t, SynchronousStandbyCount(cluster), int32(2))
+
+	cluster.Spec.Patroni.Synchronous.Mode = "Off"
+	assert.Equal(t, SynchronousStandbyCount(cluster), int32(0))
+}
+
+func TestCallbackScript(t *testing.T) {
+	t.Parallel()
+
+	python, err := exec.LookPath("python3")
+	if err != nil {
+		t.Skip(`requires "python3" executable`)
+	}
+
+	assert.Equal(t, callbackCommand, "python3 /etc/patroni/~postgres-operator_callback.py")
+	assert.Assert(t, strings.Contains(callbackScript, `"postgres-operator.crunchydata.com/patroni-callback"`))
+	assert.Assert(t, strings.Contains(callbackScript, `"postgres-operator.crunchydata.com/role"`))
+
+	// Compile the script without running it.
+	cmd := exec.Command(python, "-c", "import sys; compile(sys.stdin.read(), 'callback', 'exec')")
+	cmd.Stdin = strings.NewReader(callbackScript)
+	output, err := cmd.CombinedOutput()
+	assert.NilError(t, err, "%s", output)
 }
 
 func TestPGBackRestCreateReplicaCommand(t *testing.T) {
@@ -866,4 +1397,20 @@ func TestProbeTiming(t *testing.T) {
 		assert.Assert(t, actual.SuccessThreshold == 1) // Must be 1 for liveness and startup.
 		assert.Assert(t, actual.FailureThreshold >= 1) // Minimum value is 1.
 	}
+
+	// Failsafe mode allows one more period.
+	failsafe := defaults.DeepCopy()
+	failsafe.Failover = &v1beta1.PatroniFailoverLimits{FailsafeMode: true}
+	assert.DeepEqual(t, probeTiming(failsafe), &corev1.Probe{
+		TimeoutSeconds:   5,
+		PeriodSeconds:    10,
+		SuccessThreshold: 1,
+		FailureThreshold: 4,
+	})
+
+	// Other failover limits do not change the timing.
+	failsafe.Failover = &v1beta1.PatroniFailoverLimits{
+		MaximumLag: resource.NewQuantity(4096, resource.BinarySI),
+	}
+	assert.DeepEqual(t, probeTiming(failsafe), probeTiming(defaults))
 }
@@ -125,4 +125,60 @@ func TestPermissions(t *testing.T) {
   - create
 		`, "\t\n")+"\n"))
 	})
+
+	t.Run("ConfigMaps", func(t *testing.T) {
+		cluster := new(v1beta1.PostgresCluster)
+		cluster.Default()
+		cluster.Spec.Patroni.DCS = &v1beta1.PatroniDCS{Type: "ConfigMaps"}
+
+		assert.Assert(t, marshalEquals(Permissions(cluster), strings.Trim(`
+- apiGroups:
+  - ""
+  resources:
+  - configmaps
+  verbs:
+  - create
+  - deletecollection
+  - get
+  - list
+  - patch
+  - watch
+- apiGroups:
+  - ""
+  resources:
+  - pods
+  verbs:
+  - get
+  - list
+  - patch
+  - watch
+		`, "\t\n")+"\n"))
+	})
+
+	t.Run("Etcd", func(t *testing.T) {
+		cluster := new(v1beta1.PostgresCluster)
+		cluster.Default()
+		cluster.Spec.Patroni.DCS = &v1beta1.PatroniDCS{Type: "Etcd"}
+
+		assert.Assert(t, marshalEquals(Permissions(cluster), strings.Trim(`
+- apiGroups:
+  - ""
+  resources:
+  - pods
+  verbs:
+  - get
+  - patch
+		`, "\t\n")+"\n"))
+	})
+
+	t.Run("Status", func(t *testing.T) {
+		cluster := new(v1beta1.PostgresCluster)
+		cluster.Default()
+		cluster.Spec.Patroni.DCS = &v1beta1.PatroniDCS{Type: "Etcd"}
+		cluster.Status.Patroni.DCS = "Endpoints"
+
+		permissions := Permissions(cluster)
+		assert.Equal(t, len(permissions), 3)
+		assert.DeepEqual(t, permissions[0].Resources, []string{"endpoints"})
+	})
 }
@@ -17,8 +17,12 @@ package patroni
 
 import (
 	"context"
+	"encoding/json"
+	"strconv"
 	"strings"
 
+	"github.com/pkg/errors"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -37,6 +41,26 @@ func ClusterBootstrapped(postgresCluster *v1beta1.PostgresCluster) bool {
 	return postgresCluster.Status.Patroni.SystemIdentifier != ""
 }
 
+// ClusterPaused returns true when Patroni of postgresCluster should be in
+// maintenance mode.
+func ClusterPaused(postgresCluster *v1beta1.PostgresCluster) bool {
+	return postgresCluster.Spec.Patroni != nil && postgresCluster.Spec.Patroni.Paused
+}
+
+// DCSType returns the distributed configuration store Patroni of
+// postgresCluster uses: Endpoints, ConfigMaps, or Etcd. Once recorded in the
+// status, that value takes precedence over the spec.
+func DCSType(postgresCluster *v1beta1.PostgresCluster) string {
+	if dcs := postgresCluster.Status.Patroni.DCS; dcs != "" {
+		return dcs
+	}
+	if spec := postgresCluster.Spec.Patroni; spec != nil &&
+		spec.DCS != nil && spec.DCS.Type != "" {
+		return spec.DCS.Type
+	}
+	return "Endpoints"
+}
+
 // ClusterConfigMap populates the shared ConfigMap with fields needed to run Patroni.
 func ClusterConfigMap(ctx context.Context,
 	inCluster *v1beta1.PostgresCluster,
@@ -50,6 +74,7 @@ func ClusterConfigMap(ctx context.Context,
 
 	outClusterConfigMap.Data[configMapFileKey], err = clusterYAML(inCluster, inHBAs,
 		inParameters)
+	outClusterConfigMap.Data[callbackFileKey] = callbackScript
 
 	return err
 }
@@ -109,6 +134,32 @@ func InstancePod(ctx context.Context,
 	// "kubernetes.labels" settings.
 	outInstancePod.Labels[naming.LabelPatroni] = naming.PatroniScope(inCluster)
 
+	// When any instance should not receive traffic from the replica Service,
+	// that Service selects Pods with this label. Kubernetes selectors cannot
+	// exclude Pods, so every instance Pod has a value. The value depends only
+	// on its own instance set so that changing another set does not redeploy it.
+	outInstancePod.Labels[naming.LabelLoadBalance] =
+		strconv.FormatBool(!NoLoadBalance(inInstanceSpec))
+
+	// Patroni reads its tags only when it starts. Record them on the Pod so
+	// that changing them redeploys it.
+	if tags := instanceTags(inCluster, inInstanceSpec); len(tags) > 0 {
+		encoded, err := json.Marshal(tags)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		initialize.Annotations(outInstancePod)
+		outInstancePod.Annotations[naming.PatroniTags] = string(encoded)
+	}
+
+	// The delay is an instance-local parameter. Patroni reads it only when it
+	// starts, so changing it redeploys the Pod too.
+	if delay := RecoveryMinApplyDelay(inInstanceSpec); delay != "" {
+		initialize.Annotations(outInstancePod)
+		outInstancePod.Annotations[naming.RecoveryMinApplyDelay] = delay
+	}
+
 	container := findOrAppendContainer(&outInstancePod.Spec.Containers,
 		naming.ContainerDatabase)
 
@@ -124,11 +175,12 @@ func InstancePod(ctx context.Context,
 	// Add our projections after those specified in the CR. Items later in the
 	// list take precedence over earlier items (that is, last write wins).
 	// - https://kubernetes.io/docs/concepts/storage/volumes/#projected
-	volume.Projected.Sources = append(append(append(
+	volume.Projected.Sources = append(append(append(append(
 		// TODO(cbandy): User config will come from the spec.
 		volume.Projected.Sources, []corev1.VolumeProjection(nil)...),
 		instanceConfigFiles(inClusterConfigMap, inInstanceConfigMap)...),
-		instanceCertificates(inInstanceCertificates)...)
+		instanceCertificates(inInstanceCertificates)...),
+		etcdCertificates(inCluster)...)
 
 	outInstancePod.Spec.Volumes = mergeVolumes(outInstancePod.Spec.Volumes, volume)
 
@@ -210,3 +262,51 @@ func PodRequiresRestart(pod metav1.Object) bool {
 	status := pod.GetAnnotations()["status"]
 	return strings.Contains(status, `"pending_restart":true`)
 }
+
+// Callback is the most recent Patroni callback recorded on an instance Pod.
+// - https://github.com/zalando/patroni/blob/v2.1.1/docs/SETTINGS.rst#postgresql
+type Callback struct {
+	// Action is the name of the callback, e.g. "on_role_change".
+	Action string `json:"action"`
+
+	// Role is the role of the member when Patroni called, e.g. "master".
+	Role string `json:"role"`
+
+	// Time is when the callback ran.
+	Time metav1.Time `json:"time"`
+}
+
+// IsPrimary returns true when c reports that its member is the primary.
+func (c Callback) IsPrimary() bool {
+	return c.Role == "master" || c.Role == "primary" || c.Role == "standby_leader"
+}
+
+// PodCallback returns the most recent callback recorded on pod and whether or
+// not there is one.
+func PodCallback(pod metav1.Object) (Callback, bool) {
+	var callback Callback
+	if pod == nil {
+		return callback, false
+	}
+
+	value := pod.GetAnnotations()[naming.PatroniCallback]
+	err := json.Unmarshal([]byte(value), &callback)
+	return callback, value != "" && err == nil
+}
+
+// PodTimeline returns the PostgreSQL timeline that Patroni last reported for
+// pod, or zero when it is unknown. Patroni reports it on pod only when it uses
+// Kubernetes for DCS, so this is always zero with etcd.
+func PodTimeline(pod metav1.Object) int64 {
+	if pod == nil {
+		return 0
+	}
+
+	// Patroni writes its member data, including timeline, to the "status" annotation.
+	// - https://github.com/zalando/patroni/blob/v2.1.1/patroni/dcs/kubernetes.py
+	var status struct {
+		Timeline int64 `json:"timeline"`
+	}
+	_ = json.Unmarshal([]byte(pod.GetAnnotations()["status"]), &status)
+	return status.Timeline
+}
@@ -19,9 +19,11 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 
 	"gotest.tools/v3/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/crunchydata/postgres-operator/internal/naming"
@@ -46,6 +48,9 @@ func TestClusterConfigMap(t *testing.T) {
 	data, _ := clusterYAML(cluster, pgHBAs, pgParameters)
 	assert.DeepEqual(t, config.Data["patroni.yaml"], data)
 
+	// The callback script should go into config.
+	assert.Equal(t, config.Data["patroni-callback.py"], callbackScript)
+
 	// No change when called again.
 	before := config.DeepCopy()
 	assert.NilError(t, ClusterConfigMap(ctx, cluster, pgHBAs, pgParameters, config))
@@ -125,7 +130,10 @@ func TestInstancePod(t *testing.T) {
 	assert.NilError(t, call())
 
 	assert.DeepEqual(t, template.ObjectMeta, metav1.ObjectMeta{
-		Labels: map[string]string{naming.LabelPatroni: "some-such-ha"},
+		Labels: map[string]string{
+			naming.LabelLoadBalance: "true",
+			naming.LabelPatroni:     "some-such-ha",
+		},
 	})
 
 	assert.Assert(t, marshalEquals(template.Spec, strings.TrimSpace(`
@@ -195,6 +203,8 @@ volumes:
         items:
         - key: patroni.yaml
           path: ~postgres-operator_cluster.yaml
+        - key: patroni-callback.py
+          path: ~postgres-operator_callback.py
     - configMap:
         items:
         - key: patroni.yaml
@@ -331,6 +341,79 @@ volumes:
 		assert.NilError(t, call())
 		assert.DeepEqual(t, template, before)
 	})
+
+	t.Run("PatroniTags", func(t *testing.T) {
+		instanceSpec := instanceSpec.DeepCopy()
+		instanceSpec.PatroniTags = &v1beta1.PatroniTags{
+			NoFailover: true, NoLoadBalance: true, ReplicateFrom: "other",
+		}
+		template := new(corev1.PodTemplateSpec)
+
+		assert.NilError(t, InstancePod(context.Background(),
+			cluster, clusterConfigMap, clusterPodService, patroniLeaderService,
+			instanceSpec, instanceCertficates, instanceConfigMap, template))
+
+		assert.Equal(t, template.Labels[naming.LabelLoadBalance], "false")
+		assert.Equal(t, template.Annotations[naming.PatroniTags],
+			`{"nofailover":true,"noloadbalance":true,"replicatefrom":"other-0"}`)
+	})
+
+	t.Run("LoadBalance", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{
+			*instanceSpec, {Name: "other"},
+		}
+		before := new(corev1.PodTemplateSpec)
+		assert.NilError(t, InstancePod(context.Background(),
+			cluster, clusterConfigMap, clusterPodService, patroniLeaderService,
+			instanceSpec, instanceCertficates, instanceConfigMap, before))
+
+		// Another set opting out does not change the Pods of this one.
+		cluster.Spec.InstanceSets[1].PatroniTags = &v1beta1.PatroniTags{NoLoadBalance: true}
+		after := new(corev1.PodTemplateSpec)
+		assert.NilError(t, InstancePod(context.Background(),
+			cluster, clusterConfigMap, clusterPodService, patroniLeaderService,
+			instanceSpec, instanceCertficates, instanceConfigMap, after))
+
+		assert.Equal(t, after.Labels[naming.LabelLoadBalance], "true")
+		assert.DeepEqual(t, before, after)
+	})
+
+	t.Run("FailoverLimits", func(t *testing.T) {
+		before := new(corev1.PodTemplateSpec)
+		assert.NilError(t, InstancePod(context.Background(),
+			cluster, clusterConfigMap, clusterPodService, patroniLeaderService,
+			instanceSpec, instanceCertficates, instanceConfigMap, before))
+
+		// The lag limit does not change the Pods.
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Patroni.Failover = &v1beta1.PatroniFailoverLimits{
+			MaximumLag: resource.NewQuantity(4096, resource.BinarySI),
+		}
+		after := new(corev1.PodTemplateSpec)
+		assert.NilError(t, InstancePod(context.Background(),
+			cluster, clusterConfigMap, clusterPodService, patroniLeaderService,
+			instanceSpec, instanceCertficates, instanceConfigMap, after))
+
+		assert.DeepEqual(t, before, after)
+		assert.Equal(t, after.Spec.Containers[0].ReadinessProbe.HTTPGet.Path, "/readiness")
+	})
+
+	t.Run("RecoveryMinApplyDelay", func(t *testing.T) {
+		instanceSpec := instanceSpec.DeepCopy()
+		instanceSpec.RecoveryMinApplyDelay = &metav1.Duration{Duration: time.Minute}
+		template := new(corev1.PodTemplateSpec)
+
+		assert.NilError(t, InstancePod(context.Background(),
+			cluster, clusterConfigMap, clusterPodService, patroniLeaderService,
+			instanceSpec, instanceCertficates, instanceConfigMap, template))
+
+		assert.Equal(t, template.Labels[naming.LabelLoadBalance], "false")
+		assert.DeepEqual(t, template.Annotations, map[string]string{
+			naming.PatroniTags:           `{"nofailover":true,"noloadbalance":true}`,
+			naming.RecoveryMinApplyDelay: "60000ms",
+		})
+	})
 }
 
 func TestPodIsStandbyLeader(t *testing.T) {
@@ -357,3 +440,48 @@ func TestPodIsStandbyLeader(t *testing.T) {
 	pod.Annotations["status"] = `{"role":"standby_leader"}`
 	assert.Assert(t, PodIsStandbyLeader(pod))
 }
+
+func TestPodCallback(t *testing.T) {
+	// No object
+	_, ok := PodCallback(nil)
+	assert.Assert(t, !ok)
+
+	// No annotations
+	pod := &corev1.Pod{}
+	_, ok = PodCallback(pod)
+	assert.Assert(t, !ok)
+
+	// Invalid
+	pod.Annotations = map[string]string{naming.PatroniCallback: `nope`}
+	_, ok = PodCallback(pod)
+	assert.Assert(t, !ok)
+
+	// Promoted
+	pod.Annotations[naming.PatroniCallback] =
+		`{"action":"on_role_change","role":"master","time":"2021-10-31T12:34:56Z"}`
+	callback, ok := PodCallback(pod)
+	assert.Assert(t, ok)
+	assert.Equal(t, callback.Action, "on_role_change")
+	assert.Assert(t, callback.IsPrimary())
+	assert.Equal(t, callback.Time.UTC(), time.Date(2021, 10, 31, 12, 34, 56, 0, time.UTC))
+
+	// Demoted
+	pod.Annotations[naming.PatroniCallback] =
+		`{"action":"on_role_change","role":"replica","time":"2021-10-31T12:34:56Z"}`
+	callback, ok = PodCallback(pod)
+	assert.Assert(t, ok)
+	assert.Assert(t, !callback.IsPrimary())
+}
+
+func TestPodTimeline(t *testing.T) {
+	assert.Equal(t, PodTimeline(nil), int64(0))
+
+	pod := &corev1.Pod{}
+	assert.Equal(t, PodTimeline(pod), int64(0))
+
+	pod.Annotations = map[string]string{"status": `{"role":"master"}`}
+	assert.Equal(t, PodTimeline(pod), int64(0))
+
+	pod.Annotations["status"] = `{"role":"master","timeline":4}`
+	assert.Equal(t, PodTimeline(pod), int64(4))
+}
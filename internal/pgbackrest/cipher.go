@@ -0,0 +1,117 @@
+/*
+ Copyright 2021 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pgbackrest
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crunchydata/postgres-operator/internal/initialize"
+	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+const (
+	// CipherConfigKey is the name of the pgBackRest configuration file that
+	// holds the passphrases of encrypted repositories
+	CipherConfigKey = "pgbackrest_cipher.conf"
+
+	// DefaultCipher is the cipher used when a repository does not specify one
+	DefaultCipher = "aes-256-cbc"
+)
+
+// CipherEnabled returns whether or not any pgBackRest repository of the
+// PostgresCluster is, or should be, encrypted.
+func CipherEnabled(postgresCluster *v1beta1.PostgresCluster) bool {
+	for _, repo := range postgresCluster.Spec.Backups.PGBackRest.Repos {
+		if repo.Encryption != nil {
+			return true
+		}
+	}
+	if postgresCluster.Status.PGBackRest != nil {
+		for _, repo := range postgresCluster.Status.PGBackRest.Repos {
+			if repo.Cipher != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RepoCipher returns the cipher repo should be encrypted with, or an empty
+// string when it should not be encrypted.
+func RepoCipher(repo v1beta1.PGBackRestRepo) string {
+	switch {
+	case repo.Encryption == nil:
+		return ""
+	case repo.Encryption.Cipher == "":
+		return DefaultCipher
+	default:
+		return repo.Encryption.Cipher
+	}
+}
+
+// GeneratePassphrase returns a random passphrase for a repository. It has
+// 384 bits of entropy, the same as "openssl rand -base64 48".
+func GeneratePassphrase() (string, error) {
+	b := make([]byte, 48)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.WithStack(err)
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// CreateCipherSecretIntent returns the Secret that stores the passphrase of
+// each encrypted repository along with the pgBackRest configuration that uses
+// them. Both ciphers and passphrases are keyed by repository name.
+func CreateCipherSecretIntent(postgresCluster *v1beta1.PostgresCluster,
+	ciphers, passphrases map[string]string) *corev1.Secret {
+
+	meta := naming.PGBackRestCipherSecret(postgresCluster)
+	meta.Annotations = naming.Merge(
+		postgresCluster.Spec.Metadata.GetAnnotationsOrNil(),
+		postgresCluster.Spec.Backups.PGBackRest.Metadata.GetAnnotationsOrNil())
+	meta.Labels = naming.Merge(postgresCluster.Spec.Metadata.GetLabelsOrNil(),
+		postgresCluster.Spec.Backups.PGBackRest.Metadata.GetLabelsOrNil(),
+		naming.PGBackRestConfigLabels(postgresCluster.GetName()),
+	)
+
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: "v1",
+		},
+		ObjectMeta: meta,
+		Type:       "Opaque",
+	}
+
+	global := iniMultiSet{}
+	initialize.ByteMap(&secret.Data)
+	for name, passphrase := range passphrases {
+		secret.Data[name] = []byte(passphrase)
+		global.Set(name+"-cipher-pass", passphrase)
+		global.Set(name+"-cipher-type", ciphers[name])
+	}
+
+	secret.Data[CipherConfigKey] = []byte(iniGeneratedWarning +
+		iniSectionSet{"global": global}.String())
+
+	return secret
+}
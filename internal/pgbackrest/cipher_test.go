@@ -0,0 +1,105 @@
+/*
+ Copyright 2021 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pgbackrest
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+func TestCipherEnabled(t *testing.T) {
+	cluster := &v1beta1.PostgresCluster{}
+	cluster.Spec.Backups.PGBackRest.Repos = []v1beta1.PGBackRestRepo{{Name: "repo1"}}
+	assert.Assert(t, !CipherEnabled(cluster))
+
+	t.Run("Spec", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Backups.PGBackRest.Repos[0].Encryption = &v1beta1.PGBackRestEncryption{}
+		assert.Assert(t, CipherEnabled(cluster))
+	})
+
+	t.Run("Status", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Status.PGBackRest = &v1beta1.PGBackRestStatus{
+			Repos: []v1beta1.RepoStatus{{Name: "repo1", Cipher: "aes-256-cbc"}},
+		}
+		assert.Assert(t, CipherEnabled(cluster))
+	})
+}
+
+func TestRepoCipher(t *testing.T) {
+	repo := v1beta1.PGBackRestRepo{Name: "repo1"}
+	assert.Equal(t, RepoCipher(repo), "")
+
+	repo.Encryption = &v1beta1.PGBackRestEncryption{}
+	assert.Equal(t, RepoCipher(repo), "aes-256-cbc")
+
+	repo.Encryption.Cipher = "other"
+	assert.Equal(t, RepoCipher(repo), "other")
+}
+
+func TestGeneratePassphrase(t *testing.T) {
+	one, err := GeneratePassphrase()
+	assert.NilError(t, err)
+	two, err := GeneratePassphrase()
+	assert.NilError(t, err)
+
+	assert.Equal(t, len(one), 64)
+	assert.Assert(t, one != two)
+	assert.Assert(t, !strings.ContainsAny(one, " \n#="))
+}
+
+func TestCreateCipherSecretIntent(t *testing.T) {
+	cluster := &v1beta1.PostgresCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "hippo", Namespace: "ns1"},
+	}
+	cluster.Spec.Backups.PGBackRest.Metadata = &v1beta1.Metadata{
+		Labels: map[string]string{"some": "label"},
+	}
+
+	t.Run("Empty", func(t *testing.T) {
+		secret := CreateCipherSecretIntent(cluster, nil, nil)
+
+		assert.Equal(t, secret.Name, "hippo-pgbackrest-cipher")
+		assert.Equal(t, secret.Namespace, "ns1")
+		assert.Equal(t, secret.Labels["some"], "label")
+		assert.Equal(t, secret.Labels["postgres-operator.crunchydata.com/cluster"], "hippo")
+		assert.DeepEqual(t, secret.Data, map[string][]byte{
+			CipherConfigKey: []byte(iniGeneratedWarning),
+		})
+	})
+
+	t.Run("Repos", func(t *testing.T) {
+		secret := CreateCipherSecretIntent(cluster,
+			map[string]string{"repo1": "aes-256-cbc", "repo3": "aes-256-cbc"},
+			map[string]string{"repo1": "one", "repo3": "three"})
+
+		assert.Equal(t, string(secret.Data["repo1"]), "one")
+		assert.Equal(t, string(secret.Data["repo3"]), "three")
+		assert.Equal(t, string(secret.Data[CipherConfigKey]), iniGeneratedWarning+`
+[global]
+repo1-cipher-pass = one
+repo1-cipher-type = aes-256-cbc
+repo3-cipher-pass = three
+repo3-cipher-type = aes-256-cbc
+`)
+	})
+}
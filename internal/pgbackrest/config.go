@@ -18,6 +18,7 @@ package pgbackrest
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -66,6 +67,9 @@ const (
 
 	// repoMountPath is where to mount the pgBackRest repo volume.
 	repoMountPath = "/pgbackrest"
+
+	// spoolMountPath is where to mount the pgBackRest spool volume.
+	spoolMountPath = "/pgbackrest/spool"
 )
 
 const (
@@ -111,6 +115,7 @@ func CreatePGBackRestConfigMapIntent(postgresCluster *v1beta1.PostgresCluster,
 		populatePGInstanceConfigurationMap(serviceName, serviceNamespace, repoHostName,
 			pgdataDir, pgPort, postgresCluster.Spec.Backups.PGBackRest.Repos,
 			postgresCluster.Spec.Backups.PGBackRest.Global,
+			postgresCluster.Spec.Backups.PGBackRest.ArchiveAsync,
 		).String()
 
 	if addDedicatedHost && repoHostName != "" {
@@ -119,6 +124,7 @@ func CreatePGBackRestConfigMapIntent(postgresCluster *v1beta1.PostgresCluster,
 				pgdataDir, pgPort, instanceNames,
 				postgresCluster.Spec.Backups.PGBackRest.Repos,
 				postgresCluster.Spec.Backups.PGBackRest.Global,
+				backupStandbyOption(postgresCluster),
 			).String()
 	}
 
@@ -201,26 +207,36 @@ func JobConfigVolumeAndMount(pgBackRestConfigMap *corev1.ConfigMap, pod *corev1.
 //   Patroni config when bootstrapping a cluster using an existing data directory.
 func RestoreCommand(pgdata string, args ...string) []string {
 
-	// After pgBackRest restores files, PostgreSQL starts in recovery to finish
-	// replaying WAL files. "hot_standby" is "on" (by default) so we can detect
-	// when recovery has finished. In that mode, some parameters cannot be
-	// smaller than they were when PostgreSQL was backed up. Configure them to
-	// match the values reported by "pg_controldata". Those parameters are also
-	// written to WAL files and may change during recovery. When they increase,
-	// PostgreSQL exits and we reconfigure and restart it.
-	// For PG14, when some parameters from WAL require a restart, the behavior is
-	// to pause unless a restart is requested. For this edge case, we run a CASE
-	// query to check
-	// (a) if the instance is in recovery;
-	// (b) if so, if the WAL replay is paused;
-	// (c) if so, to unpause WAL replay, allowing our expected behavior to resume.
-	// A note on the PostgreSQL code: we cast `pg_catalog.pg_wal_replay_resume()` as text
-	// because that method returns a void (which is a non-NULL but empty result). When
-	// that void is cast as a string, it is an ''
-	// - https://www.postgresql.org/docs/current/hot-standby.html
-	// - https://www.postgresql.org/docs/current/app-pgcontroldata.html
-
-	const restoreScript = `declare -r pgdata="$1" opts="$2"
+	const restoreScript = recoverScript + `
+
+pg_ctl stop --silent --wait
+mv "${pgdata}" "${pgdata}_bootstrap"`
+
+	return append([]string{"bash", "-ceu", "--", restoreScript, "-", pgdata}, args...)
+}
+
+// recoverScript restores pgdata using pgBackRest and then starts PostgreSQL and
+// waits for recovery to finish. PostgreSQL is left running.
+//
+// After pgBackRest restores files, PostgreSQL starts in recovery to finish
+// replaying WAL files. "hot_standby" is "on" (by default) so we can detect
+// when recovery has finished. In that mode, some parameters cannot be
+// smaller than they were when PostgreSQL was backed up. Configure them to
+// match the values reported by "pg_controldata". Those parameters are also
+// written to WAL files and may change during recovery. When they increase,
+// PostgreSQL exits and we reconfigure and restart it.
+// For PG14, when some parameters from WAL require a restart, the behavior is
+// to pause unless a restart is requested. For this edge case, we run a CASE
+// query to check
+// (a) if the instance is in recovery;
+// (b) if so, if the WAL replay is paused;
+// (c) if so, to unpause WAL replay, allowing our expected behavior to resume.
+// A note on the PostgreSQL code: we cast `pg_catalog.pg_wal_replay_resume()` as text
+// because that method returns a void (which is a non-NULL but empty result). When
+// that void is cast as a string, it is an empty string.
+// - https://www.postgresql.org/docs/current/hot-standby.html
+// - https://www.postgresql.org/docs/current/app-pgcontroldata.html
+const recoverScript = `declare -r pgdata="$1" opts="$2"
 install --directory --mode=0700 "${pgdata}"
 eval "pgbackrest restore ${opts}"
 rm -f "${pgdata}/patroni.dynamic.json"
@@ -257,26 +273,78 @@ recovery=$(psql -Atc "SELECT CASE
   WHEN NOT pg_catalog.pg_is_wal_replay_paused() THEN true
   ELSE pg_catalog.pg_wal_replay_resume()::text = ''
 END recovery" && sleep 1) || true
+done`
+
+// VerifyCommand returns the command for verifying a pgBackRest backup. Like
+// RestoreCommand, it restores pgdata and waits for PostgreSQL to finish
+// recovery. It then runs each check in database and exits nonzero when any of
+// them raises an error or returns false. Checks are keyed by name.
+func VerifyCommand(pgdata, database string, checks []v1beta1.PGBackRestVerificationCheck,
+	args ...string) []string {
+
+	const verifyScript = recoverScript + `
+
+declare -r database="$3"
+shift 3
+failures=0
+while [ "$#" -gt 1 ]; do
+if result=$(psql --dbname="${database}" --set=ON_ERROR_STOP=1 -Atc "$2" 2>&1) &&
+  [ "${result}" != 'f' ]; then
+echo "check passed: $1"
+else
+echo "check failed: $1: ${result}"
+failures=$((failures + 1))
+fi
+shift 2
 done
 
 pg_ctl stop --silent --wait
-mv "${pgdata}" "${pgdata}_bootstrap"`
+echo "${failures} check(s) failed"
+[ "${failures}" -eq 0 ]`
 
-	return append([]string{"bash", "-ceu", "--", restoreScript, "-", pgdata}, args...)
+	command := []string{"bash", "-ceu", "--", verifyScript, "-", pgdata,
+		strings.Join(args, " "), database}
+	for _, check := range checks {
+		command = append(command, check.Name, check.SQL)
+	}
+	return command
 }
 
 // populatePGInstanceConfigurationMap returns options representing the pgBackRest configuration for
 // a PostgreSQL instance
 func populatePGInstanceConfigurationMap(serviceName, serviceNamespace, repoHostName, pgdataDir string,
 	pgPort int32, repos []v1beta1.PGBackRestRepo,
-	globalConfig map[string]string,
+	globalConfig map[string]string, archiveAsync *v1beta1.PGBackRestArchiveAsync,
 ) iniSectionSet {
 
 	global := iniMultiSet{}
 	stanza := iniMultiSet{}
+	sections := iniSectionSet{}
 
 	global.Set("log-path", defaultLogPath)
 
+	// Push and fetch WAL files in the background, keeping track of them in the
+	// spool. Only the archive commands use more than one process so that
+	// backups are not affected.
+	// - https://pgbackrest.org/user-guide.html#async-archiving
+	if archiveAsync != nil {
+		global.Set("archive-async", "y")
+		global.Set("spool-path", spoolMountPath)
+
+		if archiveAsync.PushQueueMax != nil {
+			global.Set("archive-push-queue-max", fmt.Sprint(archiveAsync.PushQueueMax.Value()))
+		}
+		if archiveAsync.GetQueueMax != nil {
+			global.Set("archive-get-queue-max", fmt.Sprint(archiveAsync.GetQueueMax.Value()))
+		}
+		if archiveAsync.ProcessMax != nil {
+			processMax := iniMultiSet{}
+			processMax.Set("process-max", fmt.Sprint(*archiveAsync.ProcessMax))
+			sections["global:archive-get"] = processMax
+			sections["global:archive-push"] = processMax
+		}
+	}
+
 	for _, repo := range repos {
 		global.Set(repo.Name+"-path", defaultRepo1Path+repo.Name)
 
@@ -304,23 +372,30 @@ func populatePGInstanceConfigurationMap(serviceName, serviceNamespace, repoHostN
 		global.Set(option, val)
 	}
 
+	// Typed repository options take precedence over those in globalConfig.
+	for _, repo := range repos {
+		for option, val := range getRepoOptions(repo) {
+			global.Set(option, val)
+		}
+	}
+
 	// Now add the local PG instance to the stanza section. The local PG host must always be
 	// index 1: https://github.com/pgbackrest/pgbackrest/issues/1197#issuecomment-708381800
 	stanza.Set("pg1-path", pgdataDir)
 	stanza.Set("pg1-port", fmt.Sprint(pgPort))
 	stanza.Set("pg1-socket-path", postgres.SocketDirectory)
 
-	return iniSectionSet{
-		"global":          global,
-		DefaultStanzaName: stanza,
-	}
+	sections["global"] = global
+	sections[DefaultStanzaName] = stanza
+
+	return sections
 }
 
 // populateRepoHostConfigurationMap returns options representing the pgBackRest configuration for
 // a pgBackRest dedicated repository host
 func populateRepoHostConfigurationMap(serviceName, serviceNamespace, pgdataDir string,
 	pgPort int32, pgHosts []string, repos []v1beta1.PGBackRestRepo,
-	globalConfig map[string]string,
+	globalConfig map[string]string, backupStandby string,
 ) iniSectionSet {
 
 	global := iniMultiSet{}
@@ -345,6 +420,13 @@ func populateRepoHostConfigurationMap(serviceName, serviceNamespace, pgdataDir s
 		global.Set(option, val)
 	}
 
+	// Typed repository options take precedence over those in globalConfig.
+	for _, repo := range repos {
+		for option, val := range getRepoOptions(repo) {
+			global.Set(option, val)
+		}
+	}
+
 	// set the configs for all PG hosts
 	for i, pgHost := range pgHosts {
 		stanza.Set(fmt.Sprintf("pg%d-host", i+1), pgHost+"-0."+
@@ -356,30 +438,198 @@ func populateRepoHostConfigurationMap(serviceName, serviceNamespace, pgdataDir s
 		stanza.Set(fmt.Sprintf("pg%d-socket-path", i+1), postgres.SocketDirectory)
 	}
 
+	// Backups copy files from a replica, which pgBackRest finds among the PG hosts above.
+	// The primary is still needed to start and stop each backup.
+	if backupStandby != "" {
+		stanza.Set("backup-standby", backupStandby)
+	}
+
 	return iniSectionSet{
 		"global":          global,
 		DefaultStanzaName: stanza,
 	}
 }
 
+// backupStandbyOption returns the value of the pgBackRest "backup-standby" option for
+// postgresCluster, or "" when backups are taken from the primary. pgBackRest chooses the
+// replica, and with "prefer" it uses the primary when no replica is available.
+// - https://pgbackrest.org/configuration.html#section-backup/option-backup-standby
+func backupStandbyOption(postgresCluster *v1beta1.PostgresCluster) string {
+	if !BackupFromStandbyEnabled(postgresCluster) {
+		return ""
+	}
+	if postgresCluster.Spec.Backups.PGBackRest.BackupFromStandby.Fallback == "Fail" {
+		return "y"
+	}
+	return "prefer"
+}
+
 // getExternalRepoConfigs returns a map containing the configuration settings for an external
 // pgBackRest repository as defined in the PostgresCluster spec
 func getExternalRepoConfigs(repo v1beta1.PGBackRestRepo) map[string]string {
 
 	repoConfigs := make(map[string]string)
 
+	// set adds an option to the repo when its value is not empty
+	set := func(option, value string) {
+		if value != "" {
+			repoConfigs[repo.Name+"-"+option] = value
+		}
+	}
+
 	if repo.Azure != nil {
 		repoConfigs[repo.Name+"-type"] = "azure"
 		repoConfigs[repo.Name+"-azure-container"] = repo.Azure.Container
+		set("azure-account", repo.Azure.Account)
+		set("azure-key-type", repo.Azure.KeyType)
+		set("azure-endpoint", repo.Azure.Endpoint)
+		set("azure-uri-style", repo.Azure.URIStyle)
+		addStorageConnectionConfigs(repo.Name, repo.Azure.Connection, set)
 	} else if repo.GCS != nil {
 		repoConfigs[repo.Name+"-type"] = "gcs"
 		repoConfigs[repo.Name+"-gcs-bucket"] = repo.GCS.Bucket
+		set("gcs-key-type", repo.GCS.KeyType)
+		set("gcs-endpoint", repo.GCS.Endpoint)
+		addStorageConnectionConfigs(repo.Name, repo.GCS.Connection, set)
 	} else if repo.S3 != nil {
 		repoConfigs[repo.Name+"-type"] = "s3"
 		repoConfigs[repo.Name+"-s3-bucket"] = repo.S3.Bucket
 		repoConfigs[repo.Name+"-s3-endpoint"] = repo.S3.Endpoint
 		repoConfigs[repo.Name+"-s3-region"] = repo.S3.Region
+		set("s3-key-type", repo.S3.KeyType)
+		set("s3-role", repo.S3.Role)
+		set("s3-uri-style", repo.S3.URIStyle)
+		set("s3-kms-key-id", repo.S3.KMSKeyID)
+		set("s3-storage-class", repo.S3.StorageClass)
+		addStorageConnectionConfigs(repo.Name, repo.S3.Connection, set)
+	} else if repo.SFTP != nil {
+		hashType := repo.SFTP.HostKeyHashType
+		if hashType == "" {
+			hashType = "sha256"
+		}
+		repoConfigs[repo.Name+"-type"] = "sftp"
+		repoConfigs[repo.Name+"-sftp-host"] = repo.SFTP.Host
+		repoConfigs[repo.Name+"-sftp-host-user"] = repo.SFTP.User
+		repoConfigs[repo.Name+"-sftp-host-key-check-type"] = "fingerprint"
+		repoConfigs[repo.Name+"-sftp-host-fingerprint"] =
+			strings.ToLower(strings.ReplaceAll(repo.SFTP.HostKeyFingerprint, ":", ""))
+		repoConfigs[repo.Name+"-sftp-host-key-hash-type"] = hashType
+		repoConfigs[repo.Name+"-sftp-private-key-file"] = sftpPrivateKeyPath(repo.Name)
+		if repo.SFTP.Port != nil {
+			repoConfigs[repo.Name+"-sftp-host-port"] = fmt.Sprint(*repo.SFTP.Port)
+		}
+	} else if repo.SharedVolume != nil {
+		repoConfigs[repo.Name+"-type"] = "posix"
 	}
 
 	return repoConfigs
 }
+
+// addStorageConnectionConfigs calls set for each pgBackRest option defined by
+// the connection settings of an object storage repo.
+func addStorageConnectionConfigs(repoName string,
+	connection *v1beta1.RepoStorageConnection, set func(option, value string)) {
+
+	if connection == nil {
+		return
+	}
+	if connection.Port != nil {
+		set("storage-port", fmt.Sprint(*connection.Port))
+	}
+	if connection.VerifyTLS != nil {
+		set("storage-verify-tls", yesNo(*connection.VerifyTLS))
+	}
+	if connection.CABundle != nil {
+		set("storage-ca-file", storageCAPath(repoName))
+	}
+}
+
+// storageConnection returns the connection settings of repo when it is an
+// object storage repo.
+func storageConnection(repo v1beta1.PGBackRestRepo) *v1beta1.RepoStorageConnection {
+	switch {
+	case repo.Azure != nil:
+		return repo.Azure.Connection
+	case repo.GCS != nil:
+		return repo.GCS.Connection
+	case repo.S3 != nil:
+		return repo.S3.Connection
+	}
+	return nil
+}
+
+// storageCAPath returns the path of the file that holds the trusted certificate
+// authorities of the storage service of repoName.
+func storageCAPath(repoName string) string {
+	return ConfigDir + "/" + repoName + "-storage-ca.crt"
+}
+
+// sftpPrivateKeyPath returns the path of the private key used to log into the
+// SFTP server of repoName. It is outside the files pgBackRest reads as
+// configuration, which end in ".conf".
+func sftpPrivateKeyPath(repoName string) string {
+	return ConfigDir + "/" + repoName + "-sftp.key"
+}
+
+// getRepoOptions returns the pgBackRest configuration for the typed retention
+// and bundling options of repo.
+func getRepoOptions(repo v1beta1.PGBackRestRepo) map[string]string {
+	options := make(map[string]string)
+
+	if retention := repo.Retention; retention != nil {
+		if retention.Full != nil {
+			options[repo.Name+"-retention-full"] = fmt.Sprint(*retention.Full)
+			if retention.FullType != "" {
+				options[repo.Name+"-retention-full-type"] = retention.FullType
+			}
+		}
+		if retention.Diff != nil {
+			options[repo.Name+"-retention-diff"] = fmt.Sprint(*retention.Diff)
+		}
+		if retention.Archive != nil {
+			options[repo.Name+"-retention-archive"] = fmt.Sprint(*retention.Archive)
+			if retention.ArchiveType != "" {
+				options[repo.Name+"-retention-archive-type"] = retention.ArchiveType
+			}
+		}
+	}
+
+	if repo.Bundle != nil {
+		options[repo.Name+"-bundle"] = yesNo(*repo.Bundle)
+
+		if repo.BlockIncremental != nil {
+			options[repo.Name+"-block"] = yesNo(*repo.BlockIncremental)
+		}
+	}
+
+	return options
+}
+
+// BackupCommandOptions returns the command-line options for a backup to repo.
+// pgBackRest applies compression and parallelism to a command rather than a
+// repository, so these are not part of its configuration.
+func BackupCommandOptions(repo v1beta1.PGBackRestRepo) []string {
+	var options []string
+
+	if compression := repo.Compression; compression != nil {
+		options = append(options, "--compress-type="+compression.Type)
+
+		if compression.Level != nil {
+			options = append(options, fmt.Sprintf("--compress-level=%d", *compression.Level))
+		}
+	}
+
+	if repo.ProcessMax != nil {
+		options = append(options, fmt.Sprintf("--process-max=%d", *repo.ProcessMax))
+	}
+
+	return options
+}
+
+// yesNo returns the pgBackRest representation of a boolean option.
+func yesNo(b bool) string {
+	if b {
+		return "y"
+	}
+	return "n"
+}
@@ -30,6 +30,7 @@ import (
 
 	"gotest.tools/v3/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/rand"
@@ -304,6 +305,79 @@ pg1-socket-path = /tmp/postgres
 	})
 }
 
+func TestArchiveAsyncConfiguration(t *testing.T) {
+	repos := []v1beta1.PGBackRestRepo{{Name: "repo1", Volume: &v1beta1.RepoPVC{}}}
+
+	config := populatePGInstanceConfigurationMap("svc", "ns", "", "/pgdata", 5432,
+		repos, nil, &v1beta1.PGBackRestArchiveAsync{})
+	assert.Equal(t, config.String(), `
+[global]
+archive-async = y
+log-path = /tmp
+repo1-path = /pgbackrest/repo1
+spool-path = /pgbackrest/spool
+
+[db]
+pg1-path = /pgdata
+pg1-port = 5432
+pg1-socket-path = /tmp/postgres
+`)
+
+	getMax, pushMax := resource.MustParse("256Mi"), resource.MustParse("10Gi")
+	config = populatePGInstanceConfigurationMap("svc", "ns", "", "/pgdata", 5432,
+		repos, nil, &v1beta1.PGBackRestArchiveAsync{
+			ProcessMax:   initialize.Int32(4),
+			GetQueueMax:  &getMax,
+			PushQueueMax: &pushMax,
+		})
+	assert.Equal(t, config.String(), `
+[global]
+archive-async = y
+archive-get-queue-max = 268435456
+archive-push-queue-max = 10737418240
+log-path = /tmp
+repo1-path = /pgbackrest/repo1
+spool-path = /pgbackrest/spool
+
+[db]
+pg1-path = /pgdata
+pg1-port = 5432
+pg1-socket-path = /tmp/postgres
+
+[global:archive-get]
+process-max = 4
+
+[global:archive-push]
+process-max = 4
+`)
+}
+
+func TestBackupStandbyConfiguration(t *testing.T) {
+	repos := []v1beta1.PGBackRestRepo{{Name: "repo1", Volume: &v1beta1.RepoPVC{}}}
+
+	config := populateRepoHostConfigurationMap("svc", "ns", "/pgdata", 5432,
+		[]string{"hippo-a", "hippo-b"}, repos, nil, "prefer")
+	assert.Assert(t, strings.HasPrefix(config[DefaultStanzaName].String(), `
+backup-standby = prefer
+pg1-host = hippo-a-0.svc.ns.svc.cluster.local.
+`[1:]), "got:\n%s", config[DefaultStanzaName].String())
+
+	config = populateRepoHostConfigurationMap("svc", "ns", "/pgdata", 5432,
+		[]string{"hippo-a", "hippo-b"}, repos, nil, "")
+	assert.Assert(t, !strings.Contains(config[DefaultStanzaName].String(), "backup-standby"))
+
+	cluster := &v1beta1.PostgresCluster{}
+	cluster.Spec.Backups.PGBackRest.Repos = repos
+	assert.Equal(t, backupStandbyOption(cluster), "")
+
+	cluster.Spec.Backups.PGBackRest.BackupFromStandby =
+		&v1beta1.PGBackRestBackupFromStandby{Enabled: true}
+	assert.Equal(t, backupStandbyOption(cluster), "prefer")
+
+	cluster.Spec.Backups.PGBackRest.BackupFromStandby.Fallback = "Fail"
+	assert.Equal(t, backupStandbyOption(cluster), "y")
+}
+
 func TestRestoreCommand(t *testing.T) {
 	shellcheck, err := exec.LookPath("shellcheck")
 	if err != nil {
@@ -338,3 +412,189 @@ func TestRestoreCommandPrettyYAML(t *testing.T) {
 	assert.Assert(t, strings.Contains(string(b), "\n- |"),
 		"expected literal block scalar, got:\n%s", b)
 }
+
+func TestVerifyCommand(t *testing.T) {
+	checks := []v1beta1.PGBackRestVerificationCheck{
+		{Name: "orders", SQL: "SELECT count(*) > 0 FROM orders"},
+		{Name: "amcheck", SQL: "SELECT bt_index_check('orders_pkey')"},
+	}
+	command := VerifyCommand("/pgdata/pg13", "app", checks, "--stanza=db", "--repo=1")
+
+	assert.DeepEqual(t, command[:3], []string{"bash", "-ceu", "--"})
+	assert.DeepEqual(t, command[4:], []string{"-", "/pgdata/pg13", "--stanza=db --repo=1", "app",
+		"orders", "SELECT count(*) > 0 FROM orders",
+		"amcheck", "SELECT bt_index_check('orders_pkey')",
+	})
+
+	shellcheck, err := exec.LookPath("shellcheck")
+	if err != nil {
+		t.Skip(`requires "shellcheck" executable`)
+	}
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "script.bash")
+	assert.NilError(t, ioutil.WriteFile(file, []byte(command[3]), 0o600))
+
+	cmd := exec.Command(shellcheck, "--enable=all", file)
+	output, err := cmd.CombinedOutput()
+	assert.NilError(t, err, "%q\n%s", cmd.Args, output)
+}
+
+func TestRepoOptions(t *testing.T) {
+	repo := v1beta1.PGBackRestRepo{
+		Name:   "repo2",
+		Volume: &v1beta1.RepoPVC{},
+		Retention: &v1beta1.PGBackRestRetention{
+			Full: initialize.Int32(14), FullType: "time",
+			Diff:    initialize.Int32(3),
+			Archive: initialize.Int32(2), ArchiveType: "diff",
+		},
+		Compression: &v1beta1.PGBackRestCompression{Type: "zst", Level: initialize.Int32(6)},
+		ProcessMax:  initialize.Int32(4),
+		Bundle:      initialize.Bool(true), BlockIncremental: initialize.Bool(true),
+	}
+	assert.DeepEqual(t, BackupCommandOptions(repo), []string{
+		"--compress-type=zst", "--compress-level=6", "--process-max=4",
+	})
+
+	// Typed options take precedence over global ones.
+	config := populatePGInstanceConfigurationMap("svc", "ns", "", "/pgdata", 5432,
+		[]v1beta1.PGBackRestRepo{repo}, map[string]string{
+			"repo2-retention-full": "99",
+			"repo2-cipher-type":    "aes-256-cbc",
+		}, nil)
+	assert.Equal(t, config["global"].String(), strings.Trim(`
+log-path = /tmp
+repo2-block = y
+repo2-bundle = y
+repo2-cipher-type = aes-256-cbc
+repo2-path = /pgbackrest/repo2
+repo2-retention-archive = 2
+repo2-retention-archive-type = diff
+repo2-retention-diff = 3
+repo2-retention-full = 14
+repo2-retention-full-type = time
+	`, "\t\n")+"\n")
+
+	assert.DeepEqual(t,
+		populateRepoHostConfigurationMap("svc", "ns", "/pgdata", 5432, nil,
+			[]v1beta1.PGBackRestRepo{repo}, nil, "")["global"],
+		populatePGInstanceConfigurationMap("svc", "ns", "", "/pgdata", 5432,
+			[]v1beta1.PGBackRestRepo{repo}, nil, nil)["global"])
+}
+
+func TestExternalRepoConfigs(t *testing.T) {
+	t.Run("SFTP", func(t *testing.T) {
+		repo := v1beta1.PGBackRestRepo{
+			Name: "repo3",
+			SFTP: &v1beta1.RepoSFTP{
+				Host: "backups.example.com", User: "pgbackrest",
+				HostKeyFingerprint: "F8:4E:17:2D",
+			},
+		}
+		assert.DeepEqual(t, getExternalRepoConfigs(repo), map[string]string{
+			"repo3-type":                     "sftp",
+			"repo3-sftp-host":                "backups.example.com",
+			"repo3-sftp-host-user":           "pgbackrest",
+			"repo3-sftp-host-key-check-type": "fingerprint",
+			"repo3-sftp-host-fingerprint":    "f84e172d",
+			"repo3-sftp-host-key-hash-type":  "sha256",
+			"repo3-sftp-private-key-file":    "/etc/pgbackrest/conf.d/repo3-sftp.key",
+		})
+
+		repo.SFTP.Port = initialize.Int32(2222)
+		repo.SFTP.HostKeyHashType = "md5"
+		options := getExternalRepoConfigs(repo)
+		assert.Equal(t, options["repo3-sftp-host-port"], "2222")
+		assert.Equal(t, options["repo3-sftp-host-key-hash-type"], "md5")
+	})
+
+	t.Run("S3", func(t *testing.T) {
+		repo := v1beta1.PGBackRestRepo{
+			Name: "repo1",
+			S3: &v1beta1.RepoS3{
+				Bucket: "bucket", Endpoint: "minio.example.com", Region: "us-east-1",
+			},
+		}
+		assert.DeepEqual(t, getExternalRepoConfigs(repo), map[string]string{
+			"repo1-type":        "s3",
+			"repo1-s3-bucket":   "bucket",
+			"repo1-s3-endpoint": "minio.example.com",
+			"repo1-s3-region":   "us-east-1",
+		})
+
+		repo.S3.KeyType = "web-id"
+		repo.S3.URIStyle = "path"
+		repo.S3.KMSKeyID = "arn:aws:kms:us-east-1:123456789012:key/abc"
+		repo.S3.StorageClass = "STANDARD_IA"
+		repo.S3.Connection = &v1beta1.RepoStorageConnection{
+			Port:      initialize.Int32(9000),
+			VerifyTLS: initialize.Bool(false),
+			CABundle:  &corev1.ConfigMapKeySelector{Key: "ca.crt"},
+		}
+		assert.DeepEqual(t, getExternalRepoConfigs(repo), map[string]string{
+			"repo1-type":               "s3",
+			"repo1-s3-bucket":          "bucket",
+			"repo1-s3-endpoint":        "minio.example.com",
+			"repo1-s3-region":          "us-east-1",
+			"repo1-s3-key-type":        "web-id",
+			"repo1-s3-uri-style":       "path",
+			"repo1-s3-kms-key-id":      "arn:aws:kms:us-east-1:123456789012:key/abc",
+			"repo1-s3-storage-class":   "STANDARD_IA",
+			"repo1-storage-port":       "9000",
+			"repo1-storage-verify-tls": "n",
+			"repo1-storage-ca-file":    "/etc/pgbackrest/conf.d/repo1-storage-ca.crt",
+		})
+	})
+
+	t.Run("GCS", func(t *testing.T) {
+		repo := v1beta1.PGBackRestRepo{
+			Name: "repo2",
+			GCS: &v1beta1.RepoGCS{
+				Bucket: "bucket", KeyType: "auto", Endpoint: "gcs.example.com",
+				Connection: &v1beta1.RepoStorageConnection{VerifyTLS: initialize.Bool(true)},
+			},
+		}
+		assert.DeepEqual(t, getExternalRepoConfigs(repo), map[string]string{
+			"repo2-type":               "gcs",
+			"repo2-gcs-bucket":         "bucket",
+			"repo2-gcs-key-type":       "auto",
+			"repo2-gcs-endpoint":       "gcs.example.com",
+			"repo2-storage-verify-tls": "y",
+		})
+	})
+
+	t.Run("Azure", func(t *testing.T) {
+		repo := v1beta1.PGBackRestRepo{
+			Name: "repo4",
+			Azure: &v1beta1.RepoAzure{
+				Container: "container", Account: "hippo", KeyType: "sas",
+				Endpoint: "blob.core.usgovcloudapi.net", URIStyle: "path",
+			},
+		}
+		assert.DeepEqual(t, getExternalRepoConfigs(repo), map[string]string{
+			"repo4-type":            "azure",
+			"repo4-azure-container": "container",
+			"repo4-azure-account":   "hippo",
+			"repo4-azure-key-type":  "sas",
+			"repo4-azure-endpoint":  "blob.core.usgovcloudapi.net",
+			"repo4-azure-uri-style": "path",
+		})
+	})
+
+	t.Run("SharedVolume", func(t *testing.T) {
+		repo := v1beta1.PGBackRestRepo{
+			Name:         "repo2",
+			SharedVolume: &v1beta1.RepoSharedVolume{ClaimName: "smb"},
+		}
+		config := populatePGInstanceConfigurationMap("svc", "ns", "repo-host", "/pgdata", 5432,
+			[]v1beta1.PGBackRestRepo{repo}, nil, nil)
+
+		// The repository is on a local filesystem rather than the repo host.
+		assert.Equal(t, config["global"].String(), strings.Trim(`
+log-path = /tmp
+repo2-path = /pgbackrest/repo2
+repo2-type = posix
+		`, "\t\n")+"\n")
+	})
+}
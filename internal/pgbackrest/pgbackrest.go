@@ -18,8 +18,11 @@ package pgbackrest
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"strconv"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -71,3 +74,168 @@ fi
 
 	return false, nil
 }
+
+// Check runs the pgBackRest "check" command. It confirms that every repository
+// is reachable and that PostgreSQL can archive a WAL file to each of them within
+// archiveTimeout. The error includes the messages pgBackRest printed.
+// - https://pgbackrest.org/command.html#command-check
+func (exec Executor) Check(ctx context.Context, archiveTimeout time.Duration) error {
+	var stdout, stderr bytes.Buffer
+
+	if err := exec(ctx, nil, &stdout, &stderr, "pgbackrest", "check",
+		"--stanza="+DefaultStanzaName,
+		fmt.Sprintf("--archive-timeout=%d", int64(archiveTimeout.Seconds())),
+	); err != nil {
+		return errors.WithStack(fmt.Errorf("%w: %v", err, stderr.String()))
+	}
+
+	return nil
+}
+
+// BackupInfo is one backup in a repository as reported by "pgbackrest info".
+type BackupInfo struct {
+	Label string
+	Type  string
+	Start time.Time
+	Stop  time.Time
+
+	// DatabaseSize is the size in bytes of the database when it was backed
+	// up. RepositorySize is the size in bytes of this backup in the repository.
+	DatabaseSize   int64
+	RepositorySize int64
+
+	// ArchiveStart and ArchiveStop are the first and last WAL files needed to
+	// make this backup consistent.
+	ArchiveStart string
+	ArchiveStop  string
+}
+
+// RepoInfo is the content of one repository as reported by "pgbackrest info".
+type RepoInfo struct {
+	// Backups are sorted oldest to newest.
+	Backups []BackupInfo
+
+	// ArchiveMin and ArchiveMax are the oldest and newest WAL files archived
+	// for the current database.
+	ArchiveMin string
+	ArchiveMax string
+}
+
+// EarliestRecoveryTime returns the earliest time to which the cluster can be
+// restored using the backups and WAL in repo. This is the end of the oldest
+// backup whose WAL is still in the repository.
+func (repo RepoInfo) EarliestRecoveryTime() (time.Time, bool) {
+	_, oldest, ok := walSegment(repo.ArchiveMin)
+	if !ok {
+		return time.Time{}, false
+	}
+	for _, backup := range repo.Backups {
+		if _, start, ok := walSegment(backup.ArchiveStart); ok && start >= oldest {
+			return backup.Stop, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// walSegment parses the name of a WAL file into its timeline and its position
+// in the WAL. Names sort by timeline first, but every timeline continues from
+// a position of its parent, so WAL files are ordered by position.
+// - https://www.postgresql.org/docs/current/continuous-archiving.html
+func walSegment(name string) (timeline, position uint64, ok bool) {
+	if len(name) != 24 {
+		return 0, 0, false
+	}
+	timeline, err := strconv.ParseUint(name[0:8], 16, 32)
+	var log, segment uint64
+	if err == nil {
+		log, err = strconv.ParseUint(name[8:16], 16, 32)
+	}
+	if err == nil {
+		segment, err = strconv.ParseUint(name[16:24], 16, 32)
+	}
+	return timeline, log<<32 | segment, err == nil
+}
+
+// Info runs the pgBackRest "info" command and returns the backups and WAL in
+// each repository keyed by repository name, e.g. "repo1".
+func (exec Executor) Info(ctx context.Context) (map[string]RepoInfo, error) {
+	var stdout, stderr bytes.Buffer
+
+	if err := exec(ctx, nil, &stdout, &stderr, "pgbackrest", "info",
+		"--output=json", "--stanza="+DefaultStanzaName); err != nil {
+		return nil, errors.WithStack(fmt.Errorf("%w: %v", err, stderr.String()))
+	}
+
+	// The JSON output is an array of stanzas. Each backup and archive refers
+	// to its repository by number.
+	// - https://pgbackrest.org/command.html#command-info
+	var stanzas []struct {
+		Archive []struct {
+			Database struct {
+				RepoKey int `json:"repo-key"`
+			} `json:"database"`
+			Min string `json:"min"`
+			Max string `json:"max"`
+		} `json:"archive"`
+		Backup []struct {
+			Archive struct {
+				Start string `json:"start"`
+				Stop  string `json:"stop"`
+			} `json:"archive"`
+			Database struct {
+				RepoKey int `json:"repo-key"`
+			} `json:"database"`
+			Info struct {
+				Size       int64 `json:"size"`
+				Repository struct {
+					Delta int64 `json:"delta"`
+				} `json:"repository"`
+			} `json:"info"`
+			Label     string `json:"label"`
+			Timestamp struct {
+				Start int64 `json:"start"`
+				Stop  int64 `json:"stop"`
+			} `json:"timestamp"`
+			Type string `json:"type"`
+		} `json:"backup"`
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &stanzas); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	repos := make(map[string]RepoInfo)
+	for _, stanza := range stanzas {
+		if stanza.Name != DefaultStanzaName {
+			continue
+		}
+
+		// Archives are listed oldest database first. Keep the last of each
+		// repository.
+		for _, archive := range stanza.Archive {
+			name := fmt.Sprintf("repo%d", archive.Database.RepoKey)
+			repo := repos[name]
+			repo.ArchiveMin, repo.ArchiveMax = archive.Min, archive.Max
+			repos[name] = repo
+		}
+
+		// Backups are listed oldest first.
+		for _, backup := range stanza.Backup {
+			name := fmt.Sprintf("repo%d", backup.Database.RepoKey)
+			repo := repos[name]
+			repo.Backups = append(repo.Backups, BackupInfo{
+				Label:          backup.Label,
+				Type:           backup.Type,
+				Start:          time.Unix(backup.Timestamp.Start, 0).UTC(),
+				Stop:           time.Unix(backup.Timestamp.Stop, 0).UTC(),
+				DatabaseSize:   backup.Info.Size,
+				RepositorySize: backup.Info.Repository.Delta,
+				ArchiveStart:   backup.Archive.Start,
+				ArchiveStop:    backup.Archive.Stop,
+			})
+			repos[name] = repo
+		}
+	}
+
+	return repos, nil
+}
@@ -17,11 +17,14 @@ package pgbackrest
 
 import (
 	"context"
+	"errors"
 	"io"
 	"io/ioutil"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"gotest.tools/v3/assert"
 )
@@ -77,3 +80,155 @@ fi
 	output, err := cmd.CombinedOutput()
 	assert.NilError(t, err, "%q\n%s", cmd.Args, output)
 }
+
+func TestCheck(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Error", func(t *testing.T) {
+		err := Executor(func(
+			_ context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			assert.DeepEqual(t, command, strings.Fields(
+				`pgbackrest check --stanza=db --archive-timeout=30`))
+			assert.Assert(t, stdin == nil, "expected no stdin, got %T", stdin)
+			_, _ = stderr.Write([]byte("ERROR: [082]: WAL segment was not archived"))
+			return errors.New("exit status 82")
+		}).Check(ctx, 30*time.Second)
+
+		assert.ErrorContains(t, err, "exit status 82: ERROR: [082]")
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		assert.NilError(t, Executor(func(
+			_ context.Context, _ io.Reader, _, _ io.Writer, _ ...string,
+		) error {
+			return nil
+		}).Check(ctx, time.Minute))
+	})
+}
+
+func TestInfo(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Error", func(t *testing.T) {
+		_, err := Executor(func(
+			_ context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			assert.DeepEqual(t, command, strings.Fields(
+				`pgbackrest info --output=json --stanza=db`))
+			assert.Assert(t, stdin == nil, "expected no stdin, got %T", stdin)
+			_, _ = stderr.Write([]byte("ERROR: [055]: unable to load info file"))
+			return errors.New("exit status 55")
+		}).Info(ctx)
+
+		assert.ErrorContains(t, err, "exit status 55: ERROR: [055]")
+	})
+
+	t.Run("Result", func(t *testing.T) {
+		repos, err := Executor(func(
+			_ context.Context, _ io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			_, err := stdout.Write([]byte(`[{
+				"archive": [
+					{"database":{"id":1,"repo-key":1},"id":"12-1","max":"000000010000000000000009","min":"000000010000000000000001"},
+					{"database":{"id":2,"repo-key":1},"id":"13-2","max":"000000020000000000000012","min":"000000020000000000000010"},
+					{"database":{"id":2,"repo-key":2},"id":"13-2","max":"000000020000000000000012","min":"000000020000000000000011"}
+				],
+				"backup": [
+					{"archive":{"start":"000000020000000000000010","stop":"000000020000000000000010"},
+					 "database":{"id":2,"repo-key":1},
+					 "info":{"delta":31457280,"repository":{"delta":3932160,"size":3932160},"size":31457280},
+					 "label":"20210601-120000F","timestamp":{"start":1622548800,"stop":1622548830},"type":"full"},
+					{"archive":{"start":"000000020000000000000010","stop":"000000020000000000000010"},
+					 "database":{"id":2,"repo-key":2},
+					 "info":{"delta":31457280,"repository":{"delta":3932160,"size":3932160},"size":31457280},
+					 "label":"20210601-120100F","timestamp":{"start":1622548860,"stop":1622548890},"type":"full"},
+					{"archive":{"start":"000000020000000000000012","stop":"000000020000000000000012"},
+					 "database":{"id":2,"repo-key":2},
+					 "info":{"delta":1048576,"repository":{"delta":131072,"size":131072},"size":31457280},
+					 "label":"20210601-120100F_20210602-120000I","timestamp":{"start":1622635200,"stop":1622635210},"type":"incr"}
+				],
+				"name": "db"
+			}]`))
+			return err
+		}).Info(ctx)
+		assert.NilError(t, err)
+
+		assert.DeepEqual(t, repos, map[string]RepoInfo{
+			"repo1": {
+				ArchiveMin: "000000020000000000000010",
+				ArchiveMax: "000000020000000000000012",
+				Backups: []BackupInfo{{
+					Label: "20210601-120000F", Type: "full",
+					Start:        time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC),
+					Stop:         time.Date(2021, 6, 1, 12, 0, 30, 0, time.UTC),
+					DatabaseSize: 31457280, RepositorySize: 3932160,
+					ArchiveStart: "000000020000000000000010",
+					ArchiveStop:  "000000020000000000000010",
+				}},
+			},
+			"repo2": {
+				ArchiveMin: "000000020000000000000011",
+				ArchiveMax: "000000020000000000000012",
+				Backups: []BackupInfo{{
+					Label: "20210601-120100F", Type: "full",
+					Start:        time.Date(2021, 6, 1, 12, 1, 0, 0, time.UTC),
+					Stop:         time.Date(2021, 6, 1, 12, 1, 30, 0, time.UTC),
+					DatabaseSize: 31457280, RepositorySize: 3932160,
+					ArchiveStart: "000000020000000000000010",
+					ArchiveStop:  "000000020000000000000010",
+				}, {
+					Label: "20210601-120100F_20210602-120000I", Type: "incr",
+					Start:        time.Date(2021, 6, 2, 12, 0, 0, 0, time.UTC),
+					Stop:         time.Date(2021, 6, 2, 12, 0, 10, 0, time.UTC),
+					DatabaseSize: 31457280, RepositorySize: 131072,
+					ArchiveStart: "000000020000000000000012",
+					ArchiveStop:  "000000020000000000000012",
+				}},
+			},
+		})
+
+		// The WAL of the first backup in repo2 has expired.
+		earliest, ok := repos["repo1"].EarliestRecoveryTime()
+		assert.Assert(t, ok)
+		assert.Equal(t, earliest, time.Date(2021, 6, 1, 12, 0, 30, 0, time.UTC))
+
+		earliest, ok = repos["repo2"].EarliestRecoveryTime()
+		assert.Assert(t, ok)
+		assert.Equal(t, earliest, time.Date(2021, 6, 2, 12, 0, 10, 0, time.UTC))
+
+		_, ok = RepoInfo{}.EarliestRecoveryTime()
+		assert.Assert(t, !ok)
+
+		// WAL of a later timeline is newer only when it is later in the WAL.
+		earliest, ok = RepoInfo{
+			ArchiveMin: "000000010000000000000011",
+			Backups: []BackupInfo{{
+				Stop:         time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC),
+				ArchiveStart: "000000020000000000000010",
+			}, {
+				Stop:         time.Date(2021, 6, 2, 12, 0, 0, 0, time.UTC),
+				ArchiveStart: "000000020000000000000012",
+			}},
+		}.EarliestRecoveryTime()
+		assert.Assert(t, ok)
+		assert.Equal(t, earliest, time.Date(2021, 6, 2, 12, 0, 0, 0, time.UTC))
+	})
+}
+
+func TestWALSegment(t *testing.T) {
+	timeline, position, ok := walSegment("0000000A000000010000002F")
+	assert.Assert(t, ok)
+	assert.Equal(t, timeline, uint64(10))
+	assert.Equal(t, position, uint64(1<<32|0x2F))
+
+	// Later WAL sorts after earlier WAL regardless of timeline.
+	_, earlier, _ := walSegment("00000003000000000000FFFF")
+	_, later, _ := walSegment("000000020000000100000000")
+	assert.Assert(t, later > earlier)
+
+	for _, name := range []string{"", "00000001", "00000001000000000000001G", "000000010000000000000001.partial"} {
+		_, _, ok := walSegment(name)
+		assert.Assert(t, !ok, "expected %q to be invalid", name)
+	}
+}
@@ -81,6 +81,94 @@ func AddRepoVolumesToPod(postgresCluster *v1beta1.PostgresCluster, template *cor
 	return nil
 }
 
+// AddSharedRepoVolumesToPod adds the volumes of pgBackRest repositories on shared filesystems
+// to the provided Pod template spec, while also mounting them to the containers specified.
+func AddSharedRepoVolumesToPod(postgresCluster *v1beta1.PostgresCluster,
+	template *corev1.PodTemplateSpec, containerNames ...string) error {
+
+	for _, repo := range postgresCluster.Spec.Backups.PGBackRest.Repos {
+		if repo.SharedVolume == nil {
+			continue
+		}
+
+		volume := corev1.Volume{Name: repo.Name}
+		if repo.SharedVolume.NFS != nil {
+			volume.NFS = repo.SharedVolume.NFS
+		} else {
+			volume.PersistentVolumeClaim = &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: repo.SharedVolume.ClaimName,
+			}
+		}
+		template.Spec.Volumes = append(template.Spec.Volumes, volume)
+
+		for _, name := range containerNames {
+			var containerFound bool
+			var index int
+			for index = range template.Spec.Containers {
+				if template.Spec.Containers[index].Name == name {
+					containerFound = true
+					break
+				}
+			}
+			if !containerFound {
+				return errors.Errorf("Unable to find container %q when adding pgBackRest repo volumes",
+					name)
+			}
+			template.Spec.Containers[index].VolumeMounts =
+				append(template.Spec.Containers[index].VolumeMounts, corev1.VolumeMount{
+					Name:      repo.Name,
+					MountPath: repoMountPath + "/" + repo.Name,
+				})
+		}
+	}
+
+	return nil
+}
+
+// AddSpoolVolumeToPod adds the pgBackRest spool volume to the provided Pod template spec when
+// asynchronous archiving is enabled, while also mounting it to the containers specified. The
+// spool is the provided PVC or, when that is nil, an emptyDir volume.
+func AddSpoolVolumeToPod(postgresCluster *v1beta1.PostgresCluster,
+	template *corev1.PodTemplateSpec, spoolVolume *corev1.PersistentVolumeClaim,
+	containerNames ...string) error {
+
+	if postgresCluster.Spec.Backups.PGBackRest.ArchiveAsync == nil {
+		return nil
+	}
+
+	volume := corev1.Volume{Name: naming.RolePGBackRestSpool}
+	if spoolVolume != nil {
+		volume.PersistentVolumeClaim = &corev1.PersistentVolumeClaimVolumeSource{
+			ClaimName: spoolVolume.Name,
+		}
+	} else {
+		volume.EmptyDir = &corev1.EmptyDirVolumeSource{}
+	}
+	template.Spec.Volumes = append(template.Spec.Volumes, volume)
+
+	for _, name := range containerNames {
+		var containerFound bool
+		var index int
+		for index = range template.Spec.Containers {
+			if template.Spec.Containers[index].Name == name {
+				containerFound = true
+				break
+			}
+		}
+		if !containerFound {
+			return errors.Errorf("Unable to find container %q when adding pgBackRest spool volume",
+				name)
+		}
+		template.Spec.Containers[index].VolumeMounts =
+			append(template.Spec.Containers[index].VolumeMounts, corev1.VolumeMount{
+				Name:      volume.Name,
+				MountPath: spoolMountPath,
+			})
+	}
+
+	return nil
+}
+
 // AddConfigsToPod populates a Pod template Spec with with pgBackRest configuration volumes while
 // then mounting that configuration to the specified containers.
 func AddConfigsToPod(postgresCluster *v1beta1.PostgresCluster, template *corev1.PodTemplateSpec,
@@ -102,6 +190,54 @@ func AddConfigsToPod(postgresCluster *v1beta1.PostgresCluster, template *corev1.
 	}
 	pgBackRestConfigs = append(pgBackRestConfigs, defaultConfig)
 
+	// add the private keys of any SFTP repos
+	for _, repo := range postgresCluster.Spec.Backups.PGBackRest.Repos {
+		if repo.SFTP != nil {
+			selector := repo.SFTP.PrivateKeySecret
+			pgBackRestConfigs = append(pgBackRestConfigs, corev1.VolumeProjection{
+				Secret: &corev1.SecretProjection{
+					LocalObjectReference: selector.LocalObjectReference,
+					Items: []corev1.KeyToPath{{
+						Key:  selector.Key,
+						Path: repo.Name + "-sftp.key",
+						Mode: initialize.Int32(0o040),
+					}},
+				},
+			})
+		}
+	}
+
+	// add the certificate authorities of any object storage repos
+	for _, repo := range postgresCluster.Spec.Backups.PGBackRest.Repos {
+		if connection := storageConnection(repo); connection != nil &&
+			connection.CABundle != nil {
+			selector := connection.CABundle
+			pgBackRestConfigs = append(pgBackRestConfigs, corev1.VolumeProjection{
+				ConfigMap: &corev1.ConfigMapProjection{
+					LocalObjectReference: selector.LocalObjectReference,
+					Items: []corev1.KeyToPath{{
+						Key:  selector.Key,
+						Path: repo.Name + "-storage-ca.crt",
+					}},
+				},
+			})
+		}
+	}
+
+	// add the passphrases of any encrypted repos
+	if CipherEnabled(postgresCluster) {
+		pgBackRestConfigs = append(pgBackRestConfigs, corev1.VolumeProjection{
+			Secret: &corev1.SecretProjection{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: naming.PGBackRestCipherSecret(postgresCluster).Name,
+				},
+				Items: []corev1.KeyToPath{
+					{Key: CipherConfigKey, Path: CipherConfigKey},
+				},
+			},
+		})
+	}
+
 	template.Spec.Volumes = append(template.Spec.Volumes, corev1.Volume{
 		Name: ConfigVol,
 		VolumeSource: corev1.VolumeSource{
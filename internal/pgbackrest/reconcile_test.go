@@ -17,6 +17,7 @@ package pgbackrest
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"gotest.tools/v3/assert"
@@ -230,6 +231,189 @@ func TestAddConfigsToPod(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("Encryption", func(t *testing.T) {
+		cluster := postgresCluster.DeepCopy()
+		cluster.Spec.Backups.PGBackRest.Configuration = nil
+		cluster.Spec.Backups.PGBackRest.Repos = []v1beta1.PGBackRestRepo{{
+			Name:       "repo1",
+			Encryption: &v1beta1.PGBackRestEncryption{},
+		}}
+		template := &corev1.PodTemplateSpec{}
+		template.Spec.Containers = []corev1.Container{{Name: "pgbackrest"}}
+
+		assert.NilError(t, AddConfigsToPod(cluster, template, CMRepoKey, "pgbackrest"))
+		assert.Assert(t, marshalEquals(template.Spec.Volumes, strings.Trim(`
+- name: pgbackrest-config
+  projected:
+    sources:
+    - configMap:
+        items:
+        - key: pgbackrest_repo.conf
+          path: pgbackrest_repo.conf
+        - key: config-hash
+          path: config-hash
+        name: hippo-pgbackrest-config
+    - secret:
+        items:
+        - key: pgbackrest_cipher.conf
+          path: pgbackrest_cipher.conf
+        name: hippo-pgbackrest-cipher
+		`, "\t\n")+"\n"))
+	})
+
+	t.Run("SFTP", func(t *testing.T) {
+		cluster := postgresCluster.DeepCopy()
+		cluster.Spec.Backups.PGBackRest.Configuration = nil
+		cluster.Spec.Backups.PGBackRest.Repos = []v1beta1.PGBackRestRepo{{
+			Name: "repo2",
+			SFTP: &v1beta1.RepoSFTP{
+				PrivateKeySecret: corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "sftp-keys"},
+					Key:                  "id_ed25519",
+				},
+			},
+		}}
+		template := &corev1.PodTemplateSpec{}
+		template.Spec.Containers = []corev1.Container{{Name: "database"}}
+
+		assert.NilError(t, AddConfigsToPod(cluster, template, CMInstanceKey, "database"))
+		assert.Assert(t, marshalEquals(template.Spec.Volumes[0].Projected.Sources[1], strings.Trim(`
+secret:
+  items:
+  - key: id_ed25519
+    mode: 32
+    path: repo2-sftp.key
+  name: sftp-keys
+		`, "\t\n")+"\n"))
+	})
+
+	t.Run("CABundle", func(t *testing.T) {
+		cluster := postgresCluster.DeepCopy()
+		cluster.Spec.Backups.PGBackRest.Configuration = nil
+		cluster.Spec.Backups.PGBackRest.Repos = []v1beta1.PGBackRestRepo{{
+			Name: "repo1",
+			S3: &v1beta1.RepoS3{
+				Connection: &v1beta1.RepoStorageConnection{
+					CABundle: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "minio-ca"},
+						Key:                  "ca.crt",
+					},
+				},
+			},
+		}}
+		template := &corev1.PodTemplateSpec{}
+		template.Spec.Containers = []corev1.Container{{Name: "database"}}
+
+		assert.NilError(t, AddConfigsToPod(cluster, template, CMInstanceKey, "database"))
+		assert.Assert(t, marshalEquals(template.Spec.Volumes[0].Projected.Sources[1], strings.Trim(`
+configMap:
+  items:
+  - key: ca.crt
+    path: repo1-storage-ca.crt
+  name: minio-ca
+		`, "\t\n")+"\n"))
+	})
+}
+
+func TestAddSharedRepoVolumesToPod(t *testing.T) {
+	cluster := &v1beta1.PostgresCluster{ObjectMeta: metav1.ObjectMeta{Name: "hippo"}}
+	cluster.Spec.Backups.PGBackRest.Repos = []v1beta1.PGBackRestRepo{
+		{Name: "repo1", Volume: &v1beta1.RepoPVC{}},
+		{Name: "repo2", SharedVolume: &v1beta1.RepoSharedVolume{ClaimName: "smb-share"}},
+		{Name: "repo3", SharedVolume: &v1beta1.RepoSharedVolume{
+			NFS: &corev1.NFSVolumeSource{Server: "nfs.example.com", Path: "/exports/hippo"},
+		}},
+	}
+
+	template := &corev1.PodTemplateSpec{}
+	template.Spec.Containers = []corev1.Container{{Name: "database"}, {Name: "other"}}
+
+	assert.NilError(t, AddSharedRepoVolumesToPod(cluster, template, "database"))
+	assert.Assert(t, marshalEquals(template.Spec, strings.Trim(`
+containers:
+- name: database
+  resources: {}
+  volumeMounts:
+  - mountPath: /pgbackrest/repo2
+    name: repo2
+  - mountPath: /pgbackrest/repo3
+    name: repo3
+- name: other
+  resources: {}
+volumes:
+- name: repo2
+  persistentVolumeClaim:
+    claimName: smb-share
+- name: repo3
+  nfs:
+    path: /exports/hippo
+    server: nfs.example.com
+	`, "\t\n")+"\n"))
+
+	assert.ErrorContains(t,
+		AddSharedRepoVolumesToPod(cluster, &corev1.PodTemplateSpec{}, "database"),
+		`Unable to find container "database"`)
+}
+
+func TestAddSpoolVolumeToPod(t *testing.T) {
+	cluster := &v1beta1.PostgresCluster{ObjectMeta: metav1.ObjectMeta{Name: "hippo"}}
+
+	template := &corev1.PodTemplateSpec{}
+	template.Spec.Containers = []corev1.Container{{Name: "database"}, {Name: "other"}}
+
+	t.Run("Disabled", func(t *testing.T) {
+		template := template.DeepCopy()
+		assert.NilError(t, AddSpoolVolumeToPod(cluster, template, nil, "database"))
+		assert.Assert(t, len(template.Spec.Volumes) == 0)
+	})
+
+	cluster.Spec.Backups.PGBackRest.ArchiveAsync = &v1beta1.PGBackRestArchiveAsync{}
+
+	t.Run("EmptyDir", func(t *testing.T) {
+		template := template.DeepCopy()
+		assert.NilError(t, AddSpoolVolumeToPod(cluster, template, nil, "database"))
+		assert.Assert(t, marshalEquals(template.Spec, strings.Trim(`
+containers:
+- name: database
+  resources: {}
+  volumeMounts:
+  - mountPath: /pgbackrest/spool
+    name: pgbackrest-spool
+- name: other
+  resources: {}
+volumes:
+- emptyDir: {}
+  name: pgbackrest-spool
+		`, "\t\n")+"\n"))
+	})
+
+	t.Run("PersistentVolumeClaim", func(t *testing.T) {
+		template := template.DeepCopy()
+		pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "spool"}}
+		assert.NilError(t, AddSpoolVolumeToPod(cluster, template, pvc, "database", "other"))
+		assert.Assert(t, marshalEquals(template.Spec, strings.Trim(`
+containers:
+- name: database
+  resources: {}
+  volumeMounts:
+  - mountPath: /pgbackrest/spool
+    name: pgbackrest-spool
+- name: other
+  resources: {}
+  volumeMounts:
+  - mountPath: /pgbackrest/spool
+    name: pgbackrest-spool
+volumes:
+- name: pgbackrest-spool
+  persistentVolumeClaim:
+    claimName: spool
+		`, "\t\n")+"\n"))
+	})
+
+	assert.ErrorContains(t,
+		AddSpoolVolumeToPod(cluster, &corev1.PodTemplateSpec{}, nil, "database"),
+		`Unable to find container "database"`)
 }
 
 func TestAddSSHToPod(t *testing.T) {
@@ -30,6 +30,13 @@ import (
 // multi-repository solution implemented within pgBackRest
 const maxPGBackrestRepos = 4
 
+// BackupFromStandbyEnabled returns whether or not backups of the PostgresCluster are taken from a
+// replica. This requires a dedicated repository host, which runs every backup.
+func BackupFromStandbyEnabled(postgresCluster *v1beta1.PostgresCluster) bool {
+	standby := postgresCluster.Spec.Backups.PGBackRest.BackupFromStandby
+	return standby != nil && standby.Enabled && DedicatedRepoHostEnabled(postgresCluster)
+}
+
 // DedicatedRepoHostEnabled determines whether not a pgBackRest dedicated repository host is
 // enabled according to the provided PostgresCluster
 func DedicatedRepoHostEnabled(postgresCluster *v1beta1.PostgresCluster) bool {
@@ -75,6 +82,20 @@ func CalculateConfigHashes(
 		case repo.S3 != nil:
 			hash, err = hashFunc([]string{repo.S3.Bucket, repo.S3.Endpoint, repo.S3.Region})
 			name = repo.Name
+		case repo.SFTP != nil:
+			port := "22"
+			if repo.SFTP.Port != nil {
+				port = fmt.Sprint(*repo.SFTP.Port)
+			}
+			hash, err = hashFunc([]string{repo.SFTP.Host, port, repo.SFTP.User})
+			name = repo.Name
+		case repo.SharedVolume != nil:
+			var server, path string
+			if repo.SharedVolume.NFS != nil {
+				server, path = repo.SharedVolume.NFS.Server, repo.SharedVolume.NFS.Path
+			}
+			hash, err = hashFunc([]string{repo.SharedVolume.ClaimName, server, path})
+			name = repo.Name
 		default:
 			return map[string]string{}, "", errors.New("found unexpected repo type")
 		}
@@ -92,6 +113,18 @@ func CalculateConfigHashes(
 			configHashes = append(configHashes, repoConfigHashes[configName])
 		}
 	}
+	// encrypted repos are included so that a stanza is not created until their
+	// passphrases have propagated to the container
+	repoCiphers := make(map[string]string)
+	for _, repo := range postgresCluster.Spec.Backups.PGBackRest.Repos {
+		repoCiphers[repo.Name] = RepoCipher(repo)
+	}
+	for i := 1; i <= maxPGBackrestRepos; i++ {
+		configName := fmt.Sprintf("repo%d", i)
+		if cipher := repoCiphers[configName]; cipher != "" {
+			configHashes = append(configHashes, configName+"-cipher-type="+cipher)
+		}
+	}
 	configHash, err := hashFunc(configHashes)
 	if err != nil {
 		return map[string]string{}, "", errors.WithStack(err)
@@ -24,6 +24,7 @@ import (
 	"gotest.tools/v3/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/crunchydata/postgres-operator/internal/initialize"
 	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
 
@@ -113,6 +114,31 @@ func TestCalculateConfigHashes(t *testing.T) {
 		assert.Equal(t, configHash, hash)
 	}
 
+	// SFTP and shared volume repos are hashed like other external repos
+	otherCluster := postgresCluster.DeepCopy()
+	otherCluster.Spec.Backups.PGBackRest.Repos = []v1beta1.PGBackRestRepo{
+		{Name: "repo1", SFTP: &v1beta1.RepoSFTP{Host: "sftp", User: "user"}},
+		{Name: "repo2", SharedVolume: &v1beta1.RepoSharedVolume{ClaimName: "smb"}},
+	}
+	otherHashes, _, err := CalculateConfigHashes(otherCluster)
+	assert.NilError(t, err)
+	assert.Equal(t, len(otherHashes), 2)
+
+	otherCluster.Spec.Backups.PGBackRest.Repos[0].SFTP.Port = initialize.Int32(2222)
+	otherCluster.Spec.Backups.PGBackRest.Repos[1].SharedVolume.ClaimName = "other"
+	changedHashes, _, err := CalculateConfigHashes(otherCluster)
+	assert.NilError(t, err)
+	assert.Assert(t, otherHashes["repo1"] != changedHashes["repo1"])
+	assert.Assert(t, otherHashes["repo2"] != changedHashes["repo2"])
+
+	// encrypting a repo changes the config hash but not the hash of the repo
+	encryptedCluster := postgresCluster.DeepCopy()
+	encryptedCluster.Spec.Backups.PGBackRest.Repos[2].Encryption = &v1beta1.PGBackRestEncryption{}
+	hashMap, hash, err := CalculateConfigHashes(encryptedCluster)
+	assert.NilError(t, err)
+	assert.Assert(t, configHash != hash)
+	assert.Equal(t, configHashMap["repo3"], hashMap["repo3"])
+
 	// now modify some values in each repo and confirm we see a different result
 	for i := 0; i < 3; i++ {
 		modCluster := postgresCluster.DeepCopy()
@@ -132,3 +158,19 @@ func TestCalculateConfigHashes(t *testing.T) {
 		assert.Assert(t, hashMap[repo] != configHashMap[repo])
 	}
 }
+
+func TestBackupFromStandbyEnabled(t *testing.T) {
+	cluster := &v1beta1.PostgresCluster{}
+	cluster.Spec.Backups.PGBackRest.Repos = []v1beta1.PGBackRestRepo{{Name: "repo1"}}
+	assert.Assert(t, !BackupFromStandbyEnabled(cluster))
+
+	cluster.Spec.Backups.PGBackRest.BackupFromStandby =
+		&v1beta1.PGBackRestBackupFromStandby{Enabled: true}
+	assert.Assert(t, !BackupFromStandbyEnabled(cluster), "expected a repo host")
+
+	cluster.Spec.Backups.PGBackRest.Repos[0].Volume = &v1beta1.RepoPVC{}
+	assert.Assert(t, BackupFromStandbyEnabled(cluster))
+
+	cluster.Spec.Backups.PGBackRest.BackupFromStandby.Enabled = false
+	assert.Assert(t, !BackupFromStandbyEnabled(cluster))
+}
@@ -31,6 +31,16 @@ const (
 	MonitoringUser = "ccp_monitoring"
 )
 
+// archiveQueueFunction creates a function that counts the WAL files PostgreSQL
+// has yet to archive along with their total size. The exporter collects it
+// with the ccp_pgbackrest_archive_queue query in queries_pgbackrest_archive.yml.
+const archiveQueueFunction = `
+CREATE OR REPLACE FUNCTION monitor.pgbackrest_archive_queue(
+	OUT ready_count bigint, OUT ready_bytes bigint
+) LANGUAGE sql STABLE SECURITY DEFINER
+SET search_path TO pg_catalog, pg_temp AS $$` + postgres.ArchiveQueue + `
+$$;`
+
 // PostgreSQLHBAs provides the Postgres HBA rules for allowing the monitoring
 // exporter to be accessible
 func PostgreSQLHBAs(inCluster *v1beta1.PostgresCluster, outHBAs *postgres.HBAs) {
@@ -130,6 +140,14 @@ func EnableExporterInPostgreSQL(ctx context.Context, exec postgres.Executor,
 				// https://github.com/CrunchyData/pgmonitor/blob/master/postgres_exporter/common/queries_nodemx.yml
 				"CREATE EXTENSION IF NOT EXISTS pgnodemx WITH SCHEMA monitor;",
 
+				// WAL files that are waiting to be archived. This is the queue
+				// depth of pgBackRest asynchronous archiving. Reading pg_wal
+				// requires a superuser, so the function runs as its owner.
+				// - https://www.postgresql.org/docs/current/continuous-archiving.html
+				archiveQueueFunction,
+				`REVOKE ALL ON FUNCTION monitor.pgbackrest_archive_queue() FROM PUBLIC;`,
+				`GRANT EXECUTE ON FUNCTION monitor.pgbackrest_archive_queue() TO :"username";`,
+
 				// ccp_monitoring user is created in Setup.sql without a
 				// password; update the password and ensure that the ROLE
 				// can login to the database
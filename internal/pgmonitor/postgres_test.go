@@ -16,10 +16,14 @@
 package pgmonitor
 
 import (
+	"context"
+	"io"
+	"io/ioutil"
 	"strings"
 	"testing"
 
 	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/crunchydata/postgres-operator/internal/postgres"
 	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
@@ -97,3 +101,28 @@ func TestPostgreSQLParameters(t *testing.T) {
 		assert.Assert(t, strings.Contains(libs, "daisy"))
 	})
 }
+
+func TestEnableExporterInPostgreSQL(t *testing.T) {
+	var scripts []string
+	exec := postgres.Executor(func(
+		_ context.Context, stdin io.Reader, _, _ io.Writer, command ...string,
+	) error {
+		b, err := ioutil.ReadAll(stdin)
+		scripts = append(scripts, string(b))
+		return err
+	})
+
+	secret := &corev1.Secret{Data: map[string][]byte{"verifier": []byte("v")}}
+	assert.NilError(t, EnableExporterInPostgreSQL(context.Background(),
+		exec, secret, "postgres", "-- setup"))
+	assert.Equal(t, len(scripts), 2)
+
+	// The archive queue is readable by the monitoring user after setup.
+	setup := scripts[1]
+	assert.Assert(t, strings.Index(setup, "-- setup") <
+		strings.Index(setup, "FUNCTION monitor.pgbackrest_archive_queue("))
+	assert.Assert(t, strings.Contains(setup, "SECURITY DEFINER"))
+	assert.Assert(t, strings.Contains(setup, postgres.ArchiveQueue))
+	assert.Assert(t, strings.Contains(setup,
+		`GRANT EXECUTE ON FUNCTION monitor.pgbackrest_archive_queue() TO :"username";`))
+}
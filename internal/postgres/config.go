@@ -20,6 +20,7 @@ import (
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/crunchydata/postgres-operator/internal/naming"
 	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
@@ -44,6 +45,9 @@ safelink() (
 	// walMountPath is where to mount the optional WAL volume.
 	walMountPath = "/pgwal"
 
+	// tablespaceMountPath is where to mount tablespace volumes.
+	tablespaceMountPath = "/tablespaces"
+
 	// downwardAPIPath is where to mount the downwardAPI volume.
 	downwardAPIPath = "/etc/database-containerinfo"
 
@@ -81,6 +85,34 @@ func WALDirectory(
 	return fmt.Sprintf("%s/pg%d_wal", walStorage, cluster.Spec.PostgresVersion)
 }
 
+// TablespaceDirectory returns the absolute path to the directory where an
+// instance stores the files of tablespace.
+// - https://www.postgresql.org/docs/current/manage-ag-tablespaces.html
+func TablespaceDirectory(tablespace string) string {
+	// PostgreSQL expects to own this directory. Use a directory inside the
+	// volume rather than the mount point so its permissions can be set.
+	return fmt.Sprintf("%s/%s/data", tablespaceMountPath, tablespace)
+}
+
+// Tablespaces returns the names of tablespaces that have a volume in every
+// instance set of cluster. Each instance must be able to write to the directory
+// of a tablespace before it can be created in PostgreSQL.
+func Tablespaces(cluster *v1beta1.PostgresCluster) []string {
+	var names []string
+	for i, set := range cluster.Spec.InstanceSets {
+		defined := sets.NewString()
+		for _, volume := range set.TablespaceVolumes {
+			defined.Insert(volume.Name)
+		}
+		if i == 0 {
+			names = defined.List()
+		} else {
+			names = defined.Intersection(sets.NewString(names...)).List()
+		}
+	}
+	return names
+}
+
 // Environment returns the environment variables required to invoke PostgreSQL
 // utilities.
 func Environment(cluster *v1beta1.PostgresCluster) []corev1.EnvVar {
@@ -164,6 +196,9 @@ func startupCommand(
 	walDir := WALDirectory(cluster, instance)
 
 	args := []string{version, walDir}
+	for _, volume := range instance.TablespaceVolumes {
+		args = append(args, TablespaceDirectory(volume.Name))
+	}
 	script := strings.Join([]string{
 		`declare -r expected_major_version="$1" pgwal_directory="$2"`,
 
@@ -203,6 +238,10 @@ func startupCommand(
 		// - https://issue.k8s.io/93802#issuecomment-717646167
 		`install --directory --mode=0700 "${postgres_data_directory}"`,
 
+		// Tablespace directories have the same requirement. Their paths follow
+		// the first two arguments.
+		`for tablespace_directory in "${@:3}"; do install --directory --mode=0700 "${tablespace_directory}"; done`,
+
 		// Copy replication client certificate files
 		// from the /pgconf/tls/replication directory to the /tmp/replication directory in order
 		// to set proper file permissions. This is required because the group permission settings
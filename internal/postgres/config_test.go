@@ -57,6 +57,33 @@ func TestWALDirectory(t *testing.T) {
 	assert.Equal(t, WALDirectory(cluster, instance), "/pgwal/pg13_wal")
 }
 
+func TestTablespaceDirectory(t *testing.T) {
+	assert.Equal(t, TablespaceDirectory("fast"), "/tablespaces/fast/data")
+}
+
+func TestTablespaces(t *testing.T) {
+	cluster := new(v1beta1.PostgresCluster)
+	assert.Assert(t, len(Tablespaces(cluster)) == 0)
+
+	cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{
+		{Name: "one", TablespaceVolumes: []v1beta1.TablespaceVolume{
+			{Name: "large"}, {Name: "fast"}, {Name: "only"},
+		}},
+	}
+	assert.DeepEqual(t, Tablespaces(cluster), []string{"fast", "large", "only"})
+
+	// Only tablespaces defined in every instance set are returned.
+	cluster.Spec.InstanceSets = append(cluster.Spec.InstanceSets,
+		v1beta1.PostgresInstanceSetSpec{Name: "two", TablespaceVolumes: []v1beta1.TablespaceVolume{
+			{Name: "fast"}, {Name: "large"}, {Name: "other"},
+		}})
+	assert.DeepEqual(t, Tablespaces(cluster), []string{"fast", "large"})
+
+	cluster.Spec.InstanceSets = append(cluster.Spec.InstanceSets,
+		v1beta1.PostgresInstanceSetSpec{Name: "three"})
+	assert.Assert(t, len(Tablespaces(cluster)) == 0)
+}
+
 func TestBashSafeLink(t *testing.T) {
 	// macOS lacks `realpath` which is part of GNU coreutils.
 	if _, err := exec.LookPath("realpath"); err != nil {
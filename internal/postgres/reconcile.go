@@ -18,6 +18,7 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	batchv1 "k8s.io/api/batch/v1"
@@ -46,6 +47,57 @@ func WALVolumeMount() corev1.VolumeMount {
 	return corev1.VolumeMount{Name: "postgres-wal", MountPath: walMountPath}
 }
 
+// TablespaceVolumeMount returns the name and mount path of the volume for the
+// PostgreSQL tablespace named tablespace.
+func TablespaceVolumeMount(tablespace string) corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      "tablespace-" + tablespace,
+		MountPath: tablespaceMountPath + "/" + tablespace,
+	}
+}
+
+// AddTablespaceVolumes adds the tablespace volumes in inVolumes, keyed by
+// tablespace name, to outPod. They are mounted in every container and init
+// container of outPod that mounts the PostgreSQL data volume.
+func AddTablespaceVolumes(
+	inVolumes map[string]*corev1.PersistentVolumeClaim, outPod *corev1.PodSpec,
+) {
+	names := make([]string, 0, len(inVolumes))
+	for name := range inVolumes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	mounts := make([]corev1.VolumeMount, 0, len(names))
+	for _, name := range names {
+		mount := TablespaceVolumeMount(name)
+		mounts = append(mounts, mount)
+		outPod.Volumes = append(outPod.Volumes, corev1.Volume{
+			Name: mount.Name,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: inVolumes[name].Name,
+					ReadOnly:  false,
+				},
+			},
+		})
+	}
+
+	addMounts := func(containers []corev1.Container) {
+		for i := range containers {
+			for _, mount := range containers[i].VolumeMounts {
+				if mount.Name == DataVolumeMount().Name {
+					containers[i].VolumeMounts = append(containers[i].VolumeMounts, mounts...)
+					break
+				}
+			}
+		}
+	}
+
+	addMounts(outPod.InitContainers)
+	addMounts(outPod.Containers)
+}
+
 // DownwardAPIVolumeMount returns the name and mount path of the DownwardAPI volume.
 func DownwardAPIVolumeMount() corev1.VolumeMount {
 	return corev1.VolumeMount{
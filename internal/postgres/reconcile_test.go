@@ -52,6 +52,65 @@ func TestWALVolumeMount(t *testing.T) {
 	})
 }
 
+func TestTablespaceVolumeMount(t *testing.T) {
+	mount := TablespaceVolumeMount("fast")
+
+	assert.DeepEqual(t, mount, corev1.VolumeMount{
+		Name:      "tablespace-fast",
+		MountPath: "/tablespaces/fast",
+		ReadOnly:  false,
+	})
+}
+
+func TestAddTablespaceVolumes(t *testing.T) {
+	pod := &corev1.PodSpec{
+		InitContainers: []corev1.Container{
+			{Name: "startup", VolumeMounts: []corev1.VolumeMount{DataVolumeMount()}},
+		},
+		Containers: []corev1.Container{
+			{Name: "database", VolumeMounts: []corev1.VolumeMount{DataVolumeMount()}},
+			{Name: "other"},
+		},
+	}
+
+	AddTablespaceVolumes(map[string]*corev1.PersistentVolumeClaim{
+		"large": {ObjectMeta: metav1.ObjectMeta{Name: "some-large-tablespace"}},
+		"fast":  {ObjectMeta: metav1.ObjectMeta{Name: "some-fast-tablespace"}},
+	}, pod)
+
+	assert.Assert(t, marshalMatches(pod, `
+containers:
+- name: database
+  resources: {}
+  volumeMounts:
+  - mountPath: /pgdata
+    name: postgres-data
+  - mountPath: /tablespaces/fast
+    name: tablespace-fast
+  - mountPath: /tablespaces/large
+    name: tablespace-large
+- name: other
+  resources: {}
+initContainers:
+- name: startup
+  resources: {}
+  volumeMounts:
+  - mountPath: /pgdata
+    name: postgres-data
+  - mountPath: /tablespaces/fast
+    name: tablespace-fast
+  - mountPath: /tablespaces/large
+    name: tablespace-large
+volumes:
+- name: tablespace-fast
+  persistentVolumeClaim:
+    claimName: some-fast-tablespace
+- name: tablespace-large
+  persistentVolumeClaim:
+    claimName: some-large-tablespace
+	`))
+}
+
 func TestDownwardAPIVolumeMount(t *testing.T) {
 	mount := DownwardAPIVolumeMount()
 
@@ -213,6 +272,7 @@ initContainers:
     [ -d "${bootstrap_dir}" ] && results 'bootstrap directory' "${bootstrap_dir}"
     [ -d "${bootstrap_dir}" ] && postgres_data_directory="${bootstrap_dir}"
     install --directory --mode=0700 "${postgres_data_directory}"
+    for tablespace_directory in "${@:3}"; do install --directory --mode=0700 "${tablespace_directory}"; done
     install -D --mode=0600 -t "/tmp/replication" "/pgconf/tls/replication"/{tls.crt,tls.key,ca.crt}
     [ -f "${postgres_data_directory}/PG_VERSION" ] || exit 0
     results 'data version' "${postgres_data_version:=$(< "${postgres_data_directory}/PG_VERSION")}"
@@ -0,0 +1,223 @@
+/*
+ Copyright 2021 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crunchydata/postgres-operator/internal/logging"
+)
+
+// ReplayDelay calls exec to measure the time between the commit of the last
+// transaction replayed by PostgreSQL and now. It returns zero when PostgreSQL
+// is not in recovery, has not replayed any transactions, or has replayed all
+// the WAL it received. Otherwise, the delay keeps growing while the primary is
+// idle because no newer commit arrives to be replayed.
+// - https://www.postgresql.org/docs/current/functions-admin.html#FUNCTIONS-RECOVERY-INFO-TABLE
+func ReplayDelay(ctx context.Context, exec Executor) (time.Duration, error) {
+	log := logging.FromContext(ctx)
+
+	// Print only the value without headers or alignment.
+	// - https://www.postgresql.org/docs/current/app-psql.html#APP-PSQL-META-COMMANDS-PSET
+	stdout, stderr, err := exec.Exec(ctx, strings.NewReader(`
+\pset format unaligned
+\pset tuples_only on
+SELECT CASE
+  WHEN pg_catalog.pg_last_wal_receive_lsn() = pg_catalog.pg_last_wal_replay_lsn() THEN 0
+  ELSE COALESCE(GREATEST(0, CAST(1000 * EXTRACT(epoch FROM
+       clock_timestamp() - pg_catalog.pg_last_xact_replay_timestamp()) AS bigint)), 0)
+END;
+`), map[string]string{
+		"ON_ERROR_STOP": "on", // Abort when any one statement fails.
+		"QUIET":         "on", // Do not print successful statements to stdout.
+	})
+
+	log.V(1).Info("measured PostgreSQL replay delay", "stdout", stdout, "stderr", stderr)
+
+	var milliseconds int64
+	if err == nil {
+		milliseconds, err = strconv.ParseInt(strings.TrimSpace(stdout), 10, 64)
+	}
+
+	return time.Duration(milliseconds) * time.Millisecond, err
+}
+
+// SystemIdentifier calls exec to read the system identifier of the PostgreSQL
+// cluster. Every member of a Patroni cluster has the same identifier.
+// - https://www.postgresql.org/docs/current/functions-info.html#FUNCTIONS-PG-CONTROL-SYSTEM
+func SystemIdentifier(ctx context.Context, exec Executor) (string, error) {
+	log := logging.FromContext(ctx)
+
+	stdout, stderr, err := exec.Exec(ctx, strings.NewReader(`
+\pset format unaligned
+\pset tuples_only on
+SELECT system_identifier FROM pg_catalog.pg_control_system();
+`), map[string]string{
+		"ON_ERROR_STOP": "on", // Abort when any one statement fails.
+		"QUIET":         "on", // Do not print successful statements to stdout.
+	})
+
+	log.V(1).Info("read PostgreSQL system identifier", "stdout", stdout, "stderr", stderr)
+
+	return strings.TrimSpace(stdout), err
+}
+
+// ReplicationStatus is the write-ahead log location of a primary, of the
+// standbys streaming from it, and of its replication slots.
+type ReplicationStatus struct {
+	// LSN is the current write-ahead log write location of the primary.
+	LSN string `json:"lsn"`
+
+	// Standbys are keyed by the "application_name" of their connection.
+	Standbys map[string]StandbyStatus `json:"standbys"`
+
+	// Slots are keyed by the name of the replication slot.
+	Slots map[string]SlotStatus `json:"slots"`
+}
+
+// SlotStatus is a replication slot and the amount of write-ahead log the
+// primary retains for it.
+type SlotStatus struct {
+	Type     string `json:"type"`
+	Active   bool   `json:"active"`
+	Database string `json:"database"`
+	Plugin   string `json:"plugin"`
+
+	// RetainedBytes is nil when the slot has never reserved WAL.
+	RetainedBytes *int64 `json:"retained"`
+
+	// WALStatus is empty before PostgreSQL 13.
+	WALStatus string `json:"wal_status"`
+}
+
+// StandbyStatus is the replay location of a standby and the number of bytes
+// between it and the primary.
+type StandbyStatus struct {
+	LSN      string `json:"lsn"`
+	LagBytes int64  `json:"lag"`
+}
+
+// GetReplicationStatus calls exec to read the write-ahead log location of a
+// primary, of the standbys streaming from it, and of its replication slots.
+// - https://www.postgresql.org/docs/current/monitoring-stats.html#MONITORING-PG-STAT-REPLICATION-VIEW
+// - https://www.postgresql.org/docs/current/view-pg-replication-slots.html
+//
+// The "wal_status" column is read through JSON so that the same query works
+// before PostgreSQL 13.
+func GetReplicationStatus(ctx context.Context, exec Executor) (ReplicationStatus, error) {
+	log := logging.FromContext(ctx)
+
+	var status ReplicationStatus
+	stdout, stderr, err := exec.Exec(ctx, strings.NewReader(`
+\pset format unaligned
+\pset tuples_only on
+SELECT pg_catalog.json_build_object(
+       'lsn', pg_catalog.pg_current_wal_lsn(),
+       'standbys', COALESCE((
+         SELECT pg_catalog.json_object_agg(application_name, pg_catalog.json_build_object(
+                'lsn', replay_lsn,
+                'lag', CAST(pg_catalog.pg_wal_lsn_diff(pg_catalog.pg_current_wal_lsn(), replay_lsn) AS bigint)))
+           FROM pg_catalog.pg_stat_replication
+          WHERE replay_lsn IS NOT NULL), '{}'),
+       'slots', COALESCE((
+         SELECT pg_catalog.json_object_agg(slot_name, pg_catalog.json_build_object(
+                'type', slot_type, 'active', active, 'database', database, 'plugin', plugin,
+                'retained', CAST(pg_catalog.pg_wal_lsn_diff(pg_catalog.pg_current_wal_lsn(), restart_lsn) AS bigint),
+                'wal_status', pg_catalog.to_jsonb(s)->>'wal_status'))
+           FROM pg_catalog.pg_replication_slots AS s), '{}'));
+`), map[string]string{
+		"ON_ERROR_STOP": "on", // Abort when any one statement fails.
+		"QUIET":         "on", // Do not print successful statements to stdout.
+	})
+
+	log.V(1).Info("read PostgreSQL replication status", "stdout", stdout, "stderr", stderr)
+
+	if err == nil {
+		err = json.Unmarshal([]byte(stdout), &status)
+	}
+
+	return status, err
+}
+
+// ArchiveQueue is a query that counts the WAL files PostgreSQL has yet to
+// archive in "ready_count" along with their total size in "ready_bytes".
+// Reading pg_wal requires a superuser.
+// - https://www.postgresql.org/docs/current/wal-internals.html
+const ArchiveQueue = `
+SELECT count(*) AS ready_count,
+       count(*) * pg_catalog.pg_size_bytes(
+         pg_catalog.current_setting('wal_segment_size')) AS ready_bytes
+  FROM pg_catalog.pg_ls_dir('pg_wal/archive_status') AS status
+ WHERE status LIKE '%.ready'`
+
+// ArchiverStatus is the activity of the WAL archiver of a primary and the
+// number of WAL files waiting to be archived.
+type ArchiverStatus struct {
+	ArchivedCount    int64      `json:"archived_count"`
+	LastArchivedWAL  string     `json:"last_archived_wal"`
+	LastArchivedTime *time.Time `json:"last_archived_time"`
+
+	FailedCount    int64      `json:"failed_count"`
+	LastFailedWAL  string     `json:"last_failed_wal"`
+	LastFailedTime *time.Time `json:"last_failed_time"`
+
+	// ReadyCount is the number of WAL files waiting to be archived.
+	ReadyCount int64 `json:"ready_count"`
+}
+
+// Failing returns whether or not the most recent attempt to archive a WAL
+// file failed.
+func (status ArchiverStatus) Failing() bool {
+	return status.LastFailedTime != nil && (status.LastArchivedTime == nil ||
+		status.LastFailedTime.After(*status.LastArchivedTime))
+}
+
+// GetArchiverStatus calls exec to read the statistics of the WAL archiver and
+// count the WAL files it has yet to archive.
+// - https://www.postgresql.org/docs/current/monitoring-stats.html#MONITORING-PG-STAT-ARCHIVER-VIEW
+func GetArchiverStatus(ctx context.Context, exec Executor) (ArchiverStatus, error) {
+	log := logging.FromContext(ctx)
+
+	var status ArchiverStatus
+	stdout, stderr, err := exec.Exec(ctx, strings.NewReader(`
+\pset format unaligned
+\pset tuples_only on
+SELECT pg_catalog.json_build_object(
+       'archived_count', archived_count,
+       'last_archived_wal', last_archived_wal,
+       'last_archived_time', last_archived_time,
+       'failed_count', failed_count,
+       'last_failed_wal', last_failed_wal,
+       'last_failed_time', last_failed_time,
+       'ready_count', (SELECT ready_count FROM (`+ArchiveQueue+`) AS queue))
+  FROM pg_catalog.pg_stat_archiver;
+`), map[string]string{
+		"ON_ERROR_STOP": "on", // Abort when any one statement fails.
+		"QUIET":         "on", // Do not print successful statements to stdout.
+	})
+
+	log.V(1).Info("read PostgreSQL archiver status", "stdout", stdout, "stderr", stderr)
+
+	if err == nil {
+		err = json.Unmarshal([]byte(stdout), &status)
+	}
+
+	return status, err
+}
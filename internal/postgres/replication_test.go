@@ -0,0 +1,240 @@
+/*
+ Copyright 2021 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestReplayDelay(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Arguments", func(t *testing.T) {
+		expected := errors.New("pass-through")
+		exec := func(
+			_ context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			assert.Assert(t, stdout != nil, "should capture stdout")
+			assert.Assert(t, stderr != nil, "should capture stderr")
+			assert.Assert(t, strings.Contains(strings.Join(command, "\n"),
+				"--set=ON_ERROR_STOP=on"))
+
+			b, err := ioutil.ReadAll(stdin)
+			assert.NilError(t, err)
+			assert.Assert(t, strings.Contains(string(b), "pg_last_xact_replay_timestamp()"))
+			assert.Assert(t, strings.Contains(string(b), "pg_last_wal_replay_lsn()"),
+				"expected no delay when all received WAL is replayed")
+			return expected
+		}
+
+		_, err := ReplayDelay(ctx, exec)
+		assert.Equal(t, expected, err)
+	})
+
+	t.Run("Output", func(t *testing.T) {
+		exec := func(
+			_ context.Context, _ io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			_, _ = stdout.Write([]byte("3600123\n"))
+			return nil
+		}
+
+		delay, err := ReplayDelay(ctx, exec)
+		assert.NilError(t, err)
+		assert.Equal(t, delay, time.Hour+123*time.Millisecond)
+	})
+
+	t.Run("Unexpected", func(t *testing.T) {
+		exec := func(
+			_ context.Context, _ io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			_, _ = stdout.Write([]byte("whoa"))
+			return nil
+		}
+
+		_, err := ReplayDelay(ctx, exec)
+		assert.ErrorContains(t, err, "whoa")
+	})
+}
+
+func TestGetReplicationStatus(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Arguments", func(t *testing.T) {
+		expected := errors.New("pass-through")
+		exec := func(
+			_ context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			assert.Assert(t, stdout != nil, "should capture stdout")
+			assert.Assert(t, stderr != nil, "should capture stderr")
+
+			b, err := ioutil.ReadAll(stdin)
+			assert.NilError(t, err)
+			assert.Assert(t, strings.Contains(string(b), "pg_catalog.pg_stat_replication"))
+			return expected
+		}
+
+		_, err := GetReplicationStatus(ctx, exec)
+		assert.Equal(t, expected, err)
+	})
+
+	t.Run("Output", func(t *testing.T) {
+		exec := func(
+			_ context.Context, _ io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			_, _ = stdout.Write([]byte(
+				`{"lsn" : "0/5000060", "standbys" : {"two-0" : {"lsn" : "0/5000000", "lag" : 96}}, ` +
+					`"slots" : {"two_0" : {"type" : "physical", "active" : true, "database" : null, ` +
+					`"plugin" : null, "retained" : 96, "wal_status" : "reserved"}, ` +
+					`"cdc" : {"type" : "logical", "active" : false, "database" : "app", ` +
+					`"plugin" : "pgoutput", "retained" : null, "wal_status" : null}}}` + "\n"))
+			return nil
+		}
+
+		status, err := GetReplicationStatus(ctx, exec)
+		assert.NilError(t, err)
+
+		retained := int64(96)
+		assert.DeepEqual(t, status, ReplicationStatus{
+			LSN: "0/5000060",
+			Standbys: map[string]StandbyStatus{
+				"two-0": {LSN: "0/5000000", LagBytes: 96},
+			},
+			Slots: map[string]SlotStatus{
+				"two_0": {Type: "physical", Active: true, RetainedBytes: &retained, WALStatus: "reserved"},
+				"cdc":   {Type: "logical", Database: "app", Plugin: "pgoutput"},
+			},
+		})
+	})
+}
+
+func TestGetArchiverStatus(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Arguments", func(t *testing.T) {
+		expected := errors.New("pass-through")
+		exec := func(
+			_ context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			assert.Assert(t, stdout != nil, "should capture stdout")
+			assert.Assert(t, stderr != nil, "should capture stderr")
+
+			b, err := ioutil.ReadAll(stdin)
+			assert.NilError(t, err)
+			assert.Assert(t, strings.Contains(string(b), "pg_catalog.pg_stat_archiver"))
+			assert.Assert(t, strings.Contains(string(b), ArchiveQueue))
+			return expected
+		}
+
+		_, err := GetArchiverStatus(ctx, exec)
+		assert.Equal(t, expected, err)
+	})
+
+	t.Run("Output", func(t *testing.T) {
+		exec := func(
+			_ context.Context, _ io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			_, _ = stdout.Write([]byte(
+				`{"archived_count" : 12, "last_archived_wal" : "00000001000000000000000B", ` +
+					`"last_archived_time" : "2021-06-01T12:00:00.123456+00:00", ` +
+					`"failed_count" : 3, "last_failed_wal" : "00000001000000000000000C", ` +
+					`"last_failed_time" : "2021-06-01T12:05:00+00:00", "ready_count" : 2}` + "\n"))
+			return nil
+		}
+
+		status, err := GetArchiverStatus(ctx, exec)
+		assert.NilError(t, err)
+
+		assert.Equal(t, status.ArchivedCount, int64(12))
+		assert.Equal(t, status.LastArchivedWAL, "00000001000000000000000B")
+		assert.Equal(t, status.FailedCount, int64(3))
+		assert.Equal(t, status.LastFailedWAL, "00000001000000000000000C")
+		assert.Equal(t, status.LastFailedTime.UTC().Format(time.RFC3339), "2021-06-01T12:05:00Z")
+		assert.Equal(t, status.ReadyCount, int64(2))
+		assert.Assert(t, status.Failing())
+	})
+
+	t.Run("Never", func(t *testing.T) {
+		exec := func(
+			_ context.Context, _ io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			_, _ = stdout.Write([]byte(
+				`{"archived_count" : 0, "last_archived_wal" : null, "last_archived_time" : null, ` +
+					`"failed_count" : 0, "last_failed_wal" : null, "last_failed_time" : null, ` +
+					`"ready_count" : 0}` + "\n"))
+			return nil
+		}
+
+		status, err := GetArchiverStatus(ctx, exec)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, status, ArchiverStatus{})
+		assert.Assert(t, !status.Failing())
+	})
+}
+
+func TestArchiverStatusFailing(t *testing.T) {
+	earlier := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Minute)
+
+	assert.Assert(t, !ArchiverStatus{}.Failing())
+	assert.Assert(t, !ArchiverStatus{LastArchivedTime: &earlier}.Failing())
+	assert.Assert(t, ArchiverStatus{LastFailedTime: &earlier}.Failing())
+	assert.Assert(t, ArchiverStatus{LastArchivedTime: &earlier, LastFailedTime: &later}.Failing())
+	assert.Assert(t, !ArchiverStatus{LastArchivedTime: &later, LastFailedTime: &earlier}.Failing())
+}
+
+func TestSystemIdentifier(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Arguments", func(t *testing.T) {
+		expected := errors.New("pass-through")
+		exec := func(
+			_ context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			assert.Assert(t, stdout != nil, "should capture stdout")
+			assert.Assert(t, stderr != nil, "should capture stderr")
+
+			b, err := ioutil.ReadAll(stdin)
+			assert.NilError(t, err)
+			assert.Assert(t, strings.Contains(string(b), "pg_catalog.pg_control_system()"))
+			return expected
+		}
+
+		_, err := SystemIdentifier(ctx, exec)
+		assert.Equal(t, expected, err)
+	})
+
+	t.Run("Output", func(t *testing.T) {
+		exec := func(
+			_ context.Context, _ io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			_, _ = stdout.Write([]byte("7012345678901234567\n"))
+			return nil
+		}
+
+		identifier, err := SystemIdentifier(ctx, exec)
+		assert.NilError(t, err)
+		assert.Equal(t, identifier, "7012345678901234567")
+	})
+}
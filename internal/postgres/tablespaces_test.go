@@ -0,0 +1,70 @@
+/*
+ Copyright 2021 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCreateTablespacesInPostgreSQL(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Arguments", func(t *testing.T) {
+		expected := errors.New("pass-through")
+		exec := func(
+			_ context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			assert.Assert(t, stdout != nil, "should capture stdout")
+			assert.Assert(t, stderr != nil, "should capture stderr")
+			return expected
+		}
+
+		assert.Equal(t, expected, CreateTablespacesInPostgreSQL(ctx, exec, nil))
+	})
+
+	t.Run("Full", func(t *testing.T) {
+		calls := 0
+		exec := func(
+			_ context.Context, stdin io.Reader, _, _ io.Writer, command ...string,
+		) error {
+			calls++
+
+			b, err := ioutil.ReadAll(stdin)
+			assert.NilError(t, err)
+			assert.Assert(t, strings.Contains(string(b), `
+\copy input (data) from stdin with (format text)
+{"location":"/tablespaces/fast/data","tablespace":"fast"}
+{"location":"/tablespaces/large/data","tablespace":"large"}
+\.
+`), "got:\n%s", b)
+			assert.Assert(t, strings.Contains(string(b),
+				`pg_catalog.format('CREATE TABLESPACE %I LOCATION %L',`))
+			return nil
+		}
+
+		assert.NilError(t, CreateTablespacesInPostgreSQL(ctx, exec,
+			[]string{"fast", "large"},
+		))
+		assert.Equal(t, calls, 1)
+	})
+}
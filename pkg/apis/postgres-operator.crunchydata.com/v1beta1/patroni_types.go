@@ -16,10 +16,26 @@
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
 type PatroniSpec struct {
+	// How the operator calls the Patroni API. Exec runs "patronictl" inside
+	// an instance Pod. REST calls the Patroni REST API on port over mutual TLS
+	// using certificates signed by the cluster certificate authority.
+	// +optional
+	// +kubebuilder:default=Exec
+	// +kubebuilder:validation:Enum={Exec,REST}
+	APIClient string `json:"apiClient,omitempty"`
+
+	// Whether or not the operator takes a new full backup, the one used to
+	// create replicas, after the primary changes unexpectedly.
+	// +optional
+	BackupAfterFailover bool `json:"backupAfterFailover,omitempty"`
+
 	// TODO(cbandy): Find a better way to have a map[string]interface{} here.
 	// See: https://github.com/kubernetes-sigs/controller-tools/commit/557da250b8
 
@@ -31,6 +47,37 @@ type PatroniSpec struct {
 	// +kubebuilder:validation:XPreserveUnknownFields
 	DynamicConfiguration runtime.RawExtension `json:"dynamicConfiguration,omitempty"`
 
+	// Limits on when Patroni promotes a replica. These override any failover
+	// settings in dynamicConfiguration. When this is not set, those settings
+	// and Patroni's own defaults apply.
+	// +optional
+	Failover *PatroniFailoverLimits `json:"failover,omitempty"`
+
+	// Settings for reinitializing replicas that cannot start. A replica can
+	// also be reinitialized by annotating its Pod with
+	// "postgres-operator.crunchydata.com/trigger-reinitialize".
+	// +optional
+	Reinitialization *PatroniReinitialization `json:"reinitialization,omitempty"`
+
+	// Whether or not Patroni creates a physical replication slot for each
+	// replica so that the primary keeps the WAL it needs. Defaults to true on
+	// PostgreSQL 13 and later, where max_slot_wal_keep_size limits that WAL to
+	// half the smallest WAL volume, and when there are permanentSlots. This
+	// default applies to existing clusters too; set false to keep WAL only as
+	// long as PostgreSQL otherwise would.
+	// More info: https://www.postgresql.org/docs/current/warm-standby.html#STREAMING-REPLICATION-SLOTS
+	// +optional
+	UseSlots *bool `json:"useSlots,omitempty"`
+
+	// Replication slots that Patroni keeps on the primary and copies to
+	// replicas so they survive failover. These override any slots in
+	// dynamicConfiguration.
+	// More info: https://patroni.readthedocs.io/en/latest/SETTINGS.html#dynamic-configuration-settings
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	PermanentSlots []PatroniPermanentSlot `json:"permanentSlots,omitempty"`
+
 	// TTL of the cluster leader lock. "Think of it as the
 	// length of time before initiation of the automatic failover process."
 	// Changing this value causes PostgreSQL to restart.
@@ -39,6 +86,19 @@ type PatroniSpec struct {
 	// +kubebuilder:validation:Minimum=3
 	LeaderLeaseDurationSeconds *int32 `json:"leaderLeaseDurationSeconds,omitempty"`
 
+	// The amount of WAL a replica can be behind the primary before the
+	// ReplicationLagHigh condition is true. Replicas with recoveryMinApplyDelay
+	// are not considered. Defaults to 16Mi, the size of one WAL segment.
+	// +optional
+	ReplicationLagThreshold *resource.Quantity `json:"replicationLagThreshold,omitempty"`
+
+	// Whether or not Patroni is in maintenance mode. While paused, Patroni does
+	// not promote, demote, or restart PostgreSQL, and the operator does not
+	// redeploy, restart, or switchover instances.
+	// More info: https://patroni.readthedocs.io/en/latest/pause.html
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
 	// The port on which Patroni should listen.
 	// Changing this value causes PostgreSQL to restart.
 	// +optional
@@ -58,10 +118,18 @@ type PatroniSpec struct {
 	// +optional
 	Switchover *PatroniSwitchover `json:"switchover,omitempty"`
 
-	// TODO(cbandy): Add UseConfigMaps bool, default false.
-	// TODO(cbandy): Allow other DCS: etcd, raft, etc?
-	// N.B. changing this will cause downtime.
-	// - https://patroni.readthedocs.io/en/latest/kubernetes.html
+	// Synchronous replication settings. These override any synchronous
+	// settings in dynamicConfiguration.
+	// More info: https://patroni.readthedocs.io/en/latest/replication_modes.html
+	// +optional
+	Synchronous *PatroniSynchronous `json:"synchronous,omitempty"`
+
+	// Where Patroni stores the state of the cluster and elects a leader. This
+	// is chosen when the cluster is created. Changing it takes effect only
+	// while the cluster is shutdown; see status.patroni.dcs.
+	// More info: https://patroni.readthedocs.io/en/latest/SETTINGS.html
+	// +optional
+	DCS *PatroniDCS `json:"dcs,omitempty"`
 }
 
 type PatroniSwitchover struct {
@@ -89,6 +157,185 @@ type PatroniSwitchover struct {
 	// +kubebuilder:default:=switchover
 	// +optional
 	Type string `json:"type,omitempty"`
+
+	// The time at which a requested switchover should happen. When this is in
+	// the future, the operator waits until then. Removing it while a switchover
+	// is waiting cancels that switchover. A TargetInstance must be ready and
+	// caught up with the primary before a scheduled or targeted switchover
+	// happens.
+	// +optional
+	ScheduledAt *metav1.Time `json:"scheduledAt,omitempty"`
+}
+
+// PatroniDCS is the distributed configuration store (DCS) where Patroni keeps
+// the state of the cluster. In-place restores and major upgrades are supported
+// only when the DCS is Endpoints.
+type PatroniDCS struct {
+	// The kind of distributed configuration store. Endpoints and ConfigMaps
+	// store the state of the cluster in Kubernetes. Etcd uses an existing
+	// etcd v3 cluster. Defaults to Endpoints.
+	// +optional
+	// +kubebuilder:default=Endpoints
+	// +kubebuilder:validation:Enum={Endpoints,ConfigMaps,Etcd}
+	Type string `json:"type,omitempty"`
+
+	// Settings for connecting to etcd when type is Etcd.
+	// +optional
+	Etcd *PatroniEtcd `json:"etcd,omitempty"`
+}
+
+// PatroniEtcd describes an existing etcd v3 cluster that Patroni connects to
+// as a client.
+// More info: https://patroni.readthedocs.io/en/latest/SETTINGS.html#etcdv3
+type PatroniEtcd struct {
+	// The host:port of each etcd member. Every PostgresCluster that shares an
+	// etcd cluster has its own keys, prefixed by its namespace.
+	// +kubebuilder:validation:MinItems=1
+	Hosts []string `json:"hosts"`
+
+	// A Secret containing the Certificate Authority certificate, client
+	// certificate and client key used to connect to etcd over TLS. The data
+	// keys must be ca.crt, tls.crt, and tls.key, respectively.
+	// More info: https://k8s.io/docs/concepts/configuration/secret/#projection-of-secret-keys-to-specific-paths
+	// +optional
+	TLSSecret *corev1.SecretProjection `json:"tlsSecret,omitempty"`
+}
+
+type PatroniPermanentSlot struct {
+	// The name of the replication slot.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9_]{1,63}$`
+	Name string `json:"name"`
+
+	// The kind of replication slot: Physical or Logical. Defaults to Physical.
+	// +optional
+	// +kubebuilder:default=Physical
+	// +kubebuilder:validation:Enum={Physical,Logical}
+	Type string `json:"type,omitempty"`
+
+	// The database of a Logical slot. Required for Logical slots.
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	Database string `json:"database,omitempty"`
+
+	// The output plugin of a Logical slot. Defaults to pgoutput.
+	// +optional
+	Plugin string `json:"plugin,omitempty"`
+}
+
+// PatroniFailoverLimits decide which replicas Patroni can promote during a
+// failover. The defaults apply to fields that are omitted when this is set.
+// More info: https://patroni.readthedocs.io/en/latest/SETTINGS.html#dynamic-configuration-settings
+type PatroniFailoverLimits struct {
+	// The largest amount of WAL a replica can be behind the primary and still
+	// be promoted during a failover. Defaults to 1Mi.
+	// More info: https://patroni.readthedocs.io/en/latest/SETTINGS.html#dynamic-configuration-settings
+	// +optional
+	MaximumLag *resource.Quantity `json:"maximumLag,omitempty"`
+
+	// The number of seconds a primary has to recover after PostgreSQL fails
+	// before a replica is promoted. Zero promotes a replica immediately.
+	// Defaults to 300.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	PrimaryStartTimeoutSeconds *int32 `json:"primaryStartTimeoutSeconds,omitempty"`
+
+	// Whether or not a replica must be on the same timeline as the primary to
+	// be promoted. Defaults to true, unlike Patroni which defaults to false.
+	// +optional
+	CheckTimeline *bool `json:"checkTimeline,omitempty"`
+
+	// Whether or not the primary keeps running while the DCS is unavailable,
+	// provided every member can still reach it. Requires Patroni 3.0 or later.
+	// Changing this value causes PostgreSQL to restart.
+	// More info: https://patroni.readthedocs.io/en/latest/dcs_failsafe_mode.html
+	// +optional
+	FailsafeMode bool `json:"failsafeMode,omitempty"`
+}
+
+type PatroniReinitialization struct {
+	// Whether replicas are reinitialized only when requested, Manual, or also
+	// when they fail to start for failedSeconds, Automatic. Defaults to Manual.
+	// +optional
+	// +kubebuilder:default=Manual
+	// +kubebuilder:validation:Enum={Manual,Automatic}
+	Policy string `json:"policy,omitempty"`
+
+	// Where replicas copy their data from when they are created or
+	// reinitialized. PGBackRest restores the most recent backup and falls back
+	// to the primary when there is none. Primary copies from the primary using
+	// pg_basebackup. Defaults to PGBackRest.
+	// +optional
+	// +kubebuilder:default=PGBackRest
+	// +kubebuilder:validation:Enum={PGBackRest,Primary}
+	Source string `json:"source,omitempty"`
+
+	// The number of seconds a replica must fail to start or crash before it is
+	// reinitialized automatically. Defaults to 300.
+	// +optional
+	// +kubebuilder:validation:Minimum=30
+	FailedSeconds *int32 `json:"failedSeconds,omitempty"`
+
+	// The most replicas that can be reinitializing at once. Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrent *int32 `json:"maxConcurrent,omitempty"`
+}
+
+// PatroniSynchronous configures synchronous replication. Patroni chooses the
+// synchronous standbys from among the eligible replicas.
+// More info: https://patroni.readthedocs.io/en/latest/replication_modes.html
+type PatroniSynchronous struct {
+	// Whether or not transactions wait for synchronous standbys. When On,
+	// Patroni stops waiting when no standbys are available. When Strict,
+	// writes stop until a standby is available.
+	// +optional
+	// +kubebuilder:default=On
+	// +kubebuilder:validation:Enum={Off,On,Strict}
+	Mode string `json:"mode,omitempty"`
+
+	// The number of synchronous standbys. Defaults to one. None of these
+	// settings are applied while this exceeds the number of instances that can
+	// be synchronous standbys; see the PatroniSynchronousReplication condition.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	StandbyCount *int32 `json:"standbyCount,omitempty"`
+
+	// Names of the instance sets whose instances can be synchronous standbys.
+	// When empty, every instance set without the noSync tag is eligible.
+	// Changing this value causes PostgreSQL to restart.
+	// +optional
+	// +listType=set
+	InstanceSets []string `json:"instanceSets,omitempty"`
+}
+
+// PatroniTags change how Patroni treats the members of an instance set.
+// Changing these values causes PostgreSQL to restart.
+// More info: https://patroni.readthedocs.io/en/latest/SETTINGS.html#tags
+type PatroniTags struct {
+	// Whether or not other replicas can be created from a base backup of
+	// these instances.
+	// +optional
+	CloneFrom bool `json:"cloneFrom,omitempty"`
+
+	// Whether or not these instances should be promoted during a failover or
+	// switchover. At least one instance set must be able to be promoted.
+	// +optional
+	NoFailover bool `json:"noFailover,omitempty"`
+
+	// Whether or not these instances should receive traffic from the replica
+	// Service.
+	// +optional
+	NoLoadBalance bool `json:"noLoadBalance,omitempty"`
+
+	// Whether or not these instances should be chosen as synchronous replicas.
+	// +optional
+	NoSync bool `json:"noSync,omitempty"`
+
+	// Name of an instance from which these instances should stream WAL rather
+	// than from the primary. The name is the same as a switchover targetInstance.
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	ReplicateFrom string `json:"replicateFrom,omitempty"`
 }
 
 // Default sets the default values for certain Patroni configuration attributes,
@@ -121,7 +368,124 @@ type PatroniStatus struct {
 	// +optional
 	SystemIdentifier string `json:"systemIdentifier,omitempty"`
 
+	// The distributed configuration store Patroni is using: Endpoints,
+	// ConfigMaps, or Etcd.
+	// +optional
+	DCS string `json:"dcs,omitempty"`
+
 	// Tracks the execution of the switchover requests.
 	// +optional
 	Switchover *string `json:"switchover,omitempty"`
+
+	// The time at which a pending switchover request will happen.
+	// +optional
+	SwitchoverScheduledAt *metav1.Time `json:"switchoverScheduledAt,omitempty"`
+
+	// The name of the member that is the primary, as last observed.
+	// +optional
+	Primary string `json:"primary,omitempty"`
+
+	// The last time a replica was promoted because the primary failed or
+	// disappeared.
+	// +optional
+	LastFailoverTime *metav1.Time `json:"lastFailoverTime,omitempty"`
+
+	// The last time the primary stepped down gracefully and a replica was
+	// promoted, as in a switchover.
+	// +optional
+	LastSwitchoverTime *metav1.Time `json:"lastSwitchoverTime,omitempty"`
+
+	// Names of the members that are currently synchronous standbys.
+	// +optional
+	SynchronousStandbys []string `json:"synchronousStandbys,omitempty"`
+
+	// The members of the Patroni cluster and their state, as reported by Patroni.
+	// Instances whose database container is crash looping are included without
+	// a state when Patroni no longer reports them.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Members []PatroniMemberStatus `json:"members,omitempty"`
+
+	// The last time members were observed.
+	// +optional
+	MembersObservedTime *metav1.Time `json:"membersObservedTime,omitempty"`
+
+	// The replication slots on the primary, as last observed.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	ReplicationSlots []PatroniSlotStatus `json:"replicationSlots,omitempty"`
+}
+
+type PatroniSlotStatus struct {
+	// The name of the replication slot.
+	Name string `json:"name"`
+
+	// The kind of replication slot: physical or logical.
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// Whether or not a client is streaming from the slot.
+	// +optional
+	Active bool `json:"active,omitempty"`
+
+	// The database of a logical slot.
+	// +optional
+	Database string `json:"database,omitempty"`
+
+	// The output plugin of a logical slot.
+	// +optional
+	Plugin string `json:"plugin,omitempty"`
+
+	// The number of bytes of WAL the primary retains for the slot.
+	// +optional
+	RetainedBytes *int64 `json:"retainedBytes,omitempty"`
+
+	// Whether or not the WAL needed by the slot is available: reserved,
+	// extended, unreserved, or lost. Reported by PostgreSQL 13 and later.
+	// +optional
+	WALStatus string `json:"walStatus,omitempty"`
+}
+
+// PatroniMemberStatus is the state of one member of a Patroni cluster.
+// More info: https://patroni.readthedocs.io/en/latest/rest_api.html#cluster-status-endpoint
+type PatroniMemberStatus struct {
+	// The name of the member. This is the name of its Pod.
+	Name string `json:"name"`
+
+	// The role of the member, e.g. leader, replica, or sync_standby.
+	// +optional
+	Role string `json:"role,omitempty"`
+
+	// The state of PostgreSQL on the member, e.g. running or starting.
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// The PostgreSQL timeline of the member.
+	// +optional
+	Timeline int64 `json:"timeline,omitempty"`
+
+	// The write-ahead log location of the member. This is the write location
+	// on the leader and the replay location on replicas streaming from it.
+	// +optional
+	LSN string `json:"lsn,omitempty"`
+
+	// The number of bytes of WAL between the member and the leader.
+	// +optional
+	ReplicationLagBytes *int64 `json:"replicationLagBytes,omitempty"`
+
+	// Whether or not PostgreSQL needs to restart to apply its configuration.
+	// +optional
+	PendingRestart bool `json:"pendingRestart,omitempty"`
+
+	// The Patroni tags of the member.
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// The first time the member was observed failing to start, crashed, or
+	// restarting its database container. This is cleared when it is running
+	// again.
+	// +optional
+	FailedTime *metav1.Time `json:"failedTime,omitempty"`
 }
@@ -17,6 +17,7 @@ package v1beta1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // PGAdminPodSpec defines the desired state of a pgAdmin deployment.
@@ -42,6 +43,12 @@ type PGAdminPodSpec struct {
 	// +optional
 	Image string `json:"image,omitempty"`
 
+	// Minimum number of pods that should be available at a time. There is
+	// at most one pgAdmin pod, so a PodDisruptionBudget is created only when
+	// this is set explicitly.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
 	// Port on which pgAdmin should listen for client connections. Changing
 	// this value causes pgAdmin to restart.
 	// +optional
@@ -17,7 +17,9 @@ package v1beta1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // PGBackRestJobStatus contains information about the state of a pgBackRest Job.
@@ -140,6 +142,28 @@ type PGBackRestArchive struct {
 	// +optional
 	Manual *PGBackRestManualBackup `json:"manual,omitempty"`
 
+	// Defines whether backups are taken from a replica rather than the primary.
+	// Backups are then run by the dedicated repository host, so at least one
+	// "volume" repository must be defined in the "repos" section.
+	// More info: https://pgbackrest.org/user-guide.html#standby-backup
+	// +optional
+	BackupFromStandby *PGBackRestBackupFromStandby `json:"backupFromStandby,omitempty"`
+
+	// Archive and fetch WAL files asynchronously using a spool volume on each
+	// instance. PostgreSQL does not wait for each WAL file to reach the
+	// repositories. Changing this value causes PostgreSQL to restart.
+	// More info: https://pgbackrest.org/user-guide.html#async-archiving
+	// +optional
+	ArchiveAsync *PGBackRestArchiveAsync `json:"archiveAsync,omitempty"`
+
+	// Whether or not to stop redeploying the primary while WAL archiving is
+	// failing. Redeploying the primary changes it, and WAL files that were not
+	// archived might never reach the repositories. Replicas are still
+	// redeployed, and a failure older than fifteen minutes is ignored.
+	// Defaults to false.
+	// +optional
+	PauseRolloutsOnArchiveFailure bool `json:"pauseRolloutsOnArchiveFailure,omitempty"`
+
 	// Defines details for performing an in-place restore using pgBackRest
 	// +optional
 	Restore *PGBackRestRestore `json:"restore,omitempty"`
@@ -149,6 +173,51 @@ type PGBackRestArchive struct {
 	Sidecars *PGBackRestSidecars `json:"sidecars,omitempty"`
 }
 
+// PGBackRestBackupFromStandby defines how backups are taken from a replica.
+type PGBackRestBackupFromStandby struct {
+	// Whether or not backups are taken from a replica
+	// +kubebuilder:validation:Required
+	Enabled bool `json:"enabled"`
+
+	// What happens when no replica is available: backups are taken from the
+	// "Primary" instead, or they "Fail". Defaults to "Primary", which requires
+	// pgBackRest v2.52 or later.
+	// +optional
+	// +kubebuilder:validation:Enum={Primary,Fail}
+	Fallback string `json:"fallback,omitempty"`
+}
+
+// PGBackRestArchiveAsync defines asynchronous WAL archiving.
+type PGBackRestArchiveAsync struct {
+	// The number of processes that push and fetch WAL files in parallel.
+	// Defaults to 1.
+	// More info: https://pgbackrest.org/configuration.html#section-general/option-process-max
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=999
+	ProcessMax *int32 `json:"processMax,omitempty"`
+
+	// The most WAL that can wait to be archived. When exceeded, pgBackRest
+	// reports waiting WAL files as archived so that PostgreSQL can remove
+	// them. Point-in-time recovery across the gap is not possible and a new
+	// backup should be taken. There is no limit by default.
+	// More info: https://pgbackrest.org/configuration.html#section-archive/option-archive-push-queue-max
+	// +optional
+	PushQueueMax *resource.Quantity `json:"pushQueueMax,omitempty"`
+
+	// The most WAL that is fetched ahead of recovery and kept in the spool.
+	// Defaults to 128Mi.
+	// More info: https://pgbackrest.org/configuration.html#section-archive/option-archive-get-queue-max
+	// +optional
+	GetQueueMax *resource.Quantity `json:"getQueueMax,omitempty"`
+
+	// Defines a PersistentVolumeClaim for the spool of each instance. When
+	// omitted, the spool is an emptyDir volume.
+	// More info: https://kubernetes.io/docs/concepts/storage/persistent-volumes/#persistentvolumeclaims
+	// +optional
+	VolumeClaimSpec *corev1.PersistentVolumeClaimSpec `json:"volumeClaimSpec,omitempty"`
+}
+
 // PGBackRestSidecars defines the configuration for pgBackRest sidecar containers
 type PGBackRestSidecars struct {
 	// Defines the configuration for the pgBackRest sidecar container
@@ -167,6 +236,58 @@ type BackupJobs struct {
 	// More info: https://kubernetes.io/docs/concepts/scheduling-eviction/pod-priority-preemption/
 	// +optional
 	PriorityClassName *string `json:"priorityClassName,omitempty"`
+
+	// Scheduling constraints of the pgBackRest backup Job pods.
+	// More info: https://kubernetes.io/docs/concepts/scheduling-eviction/assign-pod-node
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// Node labels the pgBackRest backup Job pods must be scheduled onto.
+	// More info: https://kubernetes.io/docs/concepts/scheduling-eviction/assign-pod-node/#nodeselector
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations of the pgBackRest backup Job pods.
+	// More info: https://kubernetes.io/docs/concepts/scheduling-eviction/taint-and-toleration
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Topology spread constraints of the pgBackRest backup Job pods.
+	// More info: https://kubernetes.io/docs/concepts/workloads/pods/pod-topology-spread-constraints/
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// The number of seconds a backup Job can run before it is stopped and
+	// marked failed.
+	// More info: https://kubernetes.io/docs/concepts/workloads/controllers/job/#job-termination-and-cleanup
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+
+	// The number of times a backup Job retries before it is marked failed.
+	// Defaults to 6.
+	// More info: https://kubernetes.io/docs/concepts/workloads/controllers/job/#pod-backoff-failure-policy
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+
+	// The number of seconds a finished backup Job is kept before it is
+	// deleted. Finished Jobs are kept until they are replaced by default.
+	// More info: https://kubernetes.io/docs/concepts/workloads/controllers/ttlafterfinished/
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+
+	// The number of successful Jobs kept for each backup schedule.
+	// Defaults to 3.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	SuccessfulJobsHistoryLimit *int32 `json:"successfulJobsHistoryLimit,omitempty"`
+
+	// The number of failed Jobs kept for each backup schedule. Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	FailedJobsHistoryLimit *int32 `json:"failedJobsHistoryLimit,omitempty"`
 }
 
 // PGBackRestManualBackup contains information that is used for creating a
@@ -192,6 +313,12 @@ type PGBackRestRepoHost struct {
 	// +optional
 	Affinity *corev1.Affinity `json:"affinity,omitempty"`
 
+	// Minimum number of pods that should be available at a time. There is
+	// at most one repo host pod, so a PodDisruptionBudget is created only when
+	// this is set explicitly.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
 	// Priority class name for the pgBackRest repo host pod. Changing this value
 	// causes PostgreSQL to restart.
 	// More info: https://kubernetes.io/docs/concepts/scheduling-eviction/pod-priority-preemption/
@@ -232,7 +359,10 @@ type PGBackRestRestore struct {
 	*PostgresClusterDataSource `json:",inline"`
 }
 
-// PGBackRestBackupSchedules defines a pgBackRest scheduled backup
+// PGBackRestBackupSchedules defines a pgBackRest scheduled backup. Schedules
+// are in the time zone of the Kubernetes controller manager, usually UTC. Only
+// one backup of each schedule runs at a time; when a backup is still running
+// at its next scheduled time, that next backup is skipped.
 type PGBackRestBackupSchedules struct {
 	// Validation set to minimum length of six to account for @daily option
 
@@ -256,6 +386,11 @@ type PGBackRestBackupSchedules struct {
 	// +optional
 	// +kubebuilder:validation:MinLength=6
 	Incremental *string `json:"incremental,omitempty"`
+
+	// Whether or not the scheduled backups of this repository are suspended.
+	// Backups that have already started continue.
+	// +optional
+	Suspend *bool `json:"suspend,omitempty"`
 }
 
 // PGBackRestStatus defines the status of pgBackRest within a PostgresCluster
@@ -282,6 +417,64 @@ type PGBackRestStatus struct {
 	// Status information for in-place restores
 	// +optional
 	Restore *PGBackRestJobStatus `json:"restore,omitempty"`
+
+	// The last time the operator ran "pgbackrest info" to read the backups in
+	// each repository.
+	// +optional
+	BackupsObservedTime *metav1.Time `json:"backupsObservedTime,omitempty"`
+
+	// The results of the most recent restore verification of each repository
+	// +optional
+	// +listType=map
+	// +listMapKey=repo
+	Verifications []PGBackRestVerificationStatus `json:"verifications,omitempty"`
+
+	// Status information for WAL archiving on the primary
+	// +optional
+	Archiving *PGBackRestArchivingStatus `json:"archiving,omitempty"`
+}
+
+// PGBackRestArchivingStatus is the activity of the WAL archiver of the primary
+// as reported by PostgreSQL. Counts start over when PostgreSQL statistics are
+// reset or the primary changes.
+// More info: https://www.postgresql.org/docs/current/monitoring-stats.html#MONITORING-PG-STAT-ARCHIVER-VIEW
+type PGBackRestArchivingStatus struct {
+	// The number of WAL files archived successfully
+	// +optional
+	ArchivedCount int64 `json:"archivedCount,omitempty"`
+
+	// The name of the WAL file most recently archived
+	// +optional
+	LastArchivedWAL string `json:"lastArchivedWAL,omitempty"`
+
+	// The time of the most recent successful archive
+	// +optional
+	LastArchivedTime *metav1.Time `json:"lastArchivedTime,omitempty"`
+
+	// The number of failed attempts to archive WAL files
+	// +optional
+	FailedCount int64 `json:"failedCount,omitempty"`
+
+	// The name of the WAL file of the most recent failed archive
+	// +optional
+	LastFailedWAL string `json:"lastFailedWAL,omitempty"`
+
+	// The time of the most recent failed archive
+	// +optional
+	LastFailedTime *metav1.Time `json:"lastFailedTime,omitempty"`
+
+	// The number of WAL files waiting to be archived
+	// +optional
+	ReadyCount int64 `json:"readyCount,omitempty"`
+
+	// The last time the operator ran "pgbackrest check" because archiving was
+	// failing
+	// +optional
+	CheckTime *metav1.Time `json:"checkTime,omitempty"`
+
+	// The last time the operator tried to read the archiver status
+	// +optional
+	ObservedTime *metav1.Time `json:"observedTime,omitempty"`
 }
 
 // PGBackRestRepo represents a pgBackRest repository.  Only one of its members may be specified.
@@ -317,6 +510,221 @@ type PGBackRestRepo struct {
 	// Represents a pgBackRest repository that is created using a PersistentVolumeClaim
 	// +optional
 	Volume *RepoPVC `json:"volume,omitempty"`
+
+	// Represents a pgBackRest repository on an SFTP server
+	// +optional
+	SFTP *RepoSFTP `json:"sftp,omitempty"`
+
+	// Represents a pgBackRest repository on a filesystem that every pod of the
+	// cluster mounts, such as an NFS export or an SMB share
+	// +optional
+	SharedVolume *RepoSharedVolume `json:"sharedVolume,omitempty"`
+
+	// How long backups and WAL are kept in this repository. These override
+	// the "repoN-retention" options in global.
+	// More info: https://pgbackrest.org/configuration.html#section-repository
+	// +optional
+	Retention *PGBackRestRetention `json:"retention,omitempty"`
+
+	// How files are compressed by backups to this repository. WAL archived by
+	// PostgreSQL is compressed the same way in every repository, using the
+	// "compress-type" option in global.
+	// +optional
+	Compression *PGBackRestCompression `json:"compression,omitempty"`
+
+	// The number of processes a backup to this repository uses to copy and
+	// compress files.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=999
+	ProcessMax *int32 `json:"processMax,omitempty"`
+
+	// Whether or not backups combine small files into bundles. This reduces
+	// the number of files in the repository. Requires pgBackRest v2.39 or
+	// later.
+	// +optional
+	Bundle *bool `json:"bundle,omitempty"`
+
+	// Whether or not backups copy only the changed blocks of each file. This
+	// requires bundle. Requires pgBackRest v2.46 or later.
+	// +optional
+	BlockIncremental *bool `json:"blockIncremental,omitempty"`
+
+	// Encrypts the repository using a passphrase. The passphrase of a
+	// repository cannot change once its stanza is created.
+	// More info: https://pgbackrest.org/user-guide.html#quickstart/configure-encryption
+	// +optional
+	Encryption *PGBackRestEncryption `json:"encryption,omitempty"`
+
+	// Defines a schedule for restoring the latest backup in this repository
+	// to a temporary volume and checking that PostgreSQL starts and answers
+	// queries. The volume is deleted after each verification.
+	// +optional
+	Verification *PGBackRestVerification `json:"verification,omitempty"`
+}
+
+// PGBackRestVerification defines how and when the backups of a pgBackRest
+// repository are verified by restoring them. A verification that fails is not
+// retried until its next scheduled time.
+type PGBackRestVerification struct {
+	// Defines the Cron schedule for verifying the latest backup.
+	// Follows the standard Cron schedule syntax:
+	// https://k8s.io/docs/concepts/workloads/controllers/cron-jobs/#cron-schedule-syntax
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=6
+	Schedule string `json:"schedule"`
+
+	// Defines the temporary PersistentVolumeClaim the backup is restored into.
+	// It must be large enough to hold the entire database. The volume is created
+	// ahead of each verification and deleted once that verification finishes.
+	// +kubebuilder:validation:Required
+	VolumeClaimSpec corev1.PersistentVolumeClaimSpec `json:"volumeClaimSpec"`
+
+	// The database in which checks run. Defaults to "postgres".
+	// +optional
+	Database string `json:"database,omitempty"`
+
+	// SQL checks that run once PostgreSQL is consistent. Verification fails
+	// when any check fails.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Checks []PGBackRestVerificationCheck `json:"checks,omitempty"`
+
+	// Resource requirements for the verification container.
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Scheduling constraints of the verification Job. Defaults to those of
+	// backup Jobs.
+	// More info: https://kubernetes.io/docs/concepts/scheduling-eviction/assign-pod-node
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// Tolerations of the verification Job. Defaults to those of backup Jobs.
+	// More info: https://kubernetes.io/docs/concepts/scheduling-eviction/taint-and-toleration
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Priority class name for the verification Job. Defaults to that of
+	// backup Jobs.
+	// More info: https://kubernetes.io/docs/concepts/scheduling-eviction/pod-priority-preemption/
+	// +optional
+	PriorityClassName *string `json:"priorityClassName,omitempty"`
+
+	// How long, in seconds, a verification may run before it is stopped and
+	// considered failed.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+}
+
+// PGBackRestVerificationCheck is a SQL query that runs against a restored backup.
+type PGBackRestVerificationCheck struct {
+	// The name of the check
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The SQL to execute. The check fails when it raises an error or returns
+	// false, e.g. "SELECT count(*) > 0 FROM orders".
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	SQL string `json:"sql"`
+}
+
+// PGBackRestVerificationStatus is the result of the most recent verification
+// of a pgBackRest repository.
+type PGBackRestVerificationStatus struct {
+	// The name of the pgBackRest repository
+	// +kubebuilder:validation:Required
+	RepoName string `json:"repo"`
+
+	// The name of the Job that verified the repository
+	// +optional
+	JobName string `json:"jobName,omitempty"`
+
+	// Whether or not the backup was restored and every check passed
+	// +optional
+	Succeeded bool `json:"succeeded"`
+
+	// The time the verification Job started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// The time the verification Job finished
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// How long the verification took
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// The end of the verification output when it failed
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// PGBackRestEncryption defines how a pgBackRest repository is encrypted.
+type PGBackRestEncryption struct {
+	// The cipher used to encrypt the repository. Defaults to aes-256-cbc.
+	// +optional
+	// +kubebuilder:validation:Enum={aes-256-cbc}
+	Cipher string `json:"cipher,omitempty"`
+
+	// A key of a Secret in the PostgresCluster namespace that contains the
+	// passphrase. When omitted, the operator generates a passphrase and
+	// stores it in the "<cluster>-pgbackrest-cipher" Secret.
+	// +optional
+	PassphraseSecret *corev1.SecretKeySelector `json:"passphraseSecret,omitempty"`
+}
+
+// PGBackRestRetention defines how long backups and WAL are kept in a pgBackRest
+// repository.
+type PGBackRestRetention struct {
+	// The number of full backups to keep, or the number of days to keep them
+	// when fullType is time.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=9999999
+	Full *int32 `json:"full,omitempty"`
+
+	// Whether full is a number of backups, count, or a number of days, time.
+	// Defaults to count.
+	// +optional
+	// +kubebuilder:validation:Enum={count,time}
+	FullType string `json:"fullType,omitempty"`
+
+	// The number of differential backups to keep.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=9999999
+	Diff *int32 `json:"diff,omitempty"`
+
+	// The number of backups of archiveType for which WAL is kept. WAL needed
+	// to make the remaining backups consistent is always kept.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=9999999
+	Archive *int32 `json:"archive,omitempty"`
+
+	// The type of backup counted by archive: full, diff, or incr. Defaults to
+	// full.
+	// +optional
+	// +kubebuilder:validation:Enum={full,diff,incr}
+	ArchiveType string `json:"archiveType,omitempty"`
+}
+
+// PGBackRestCompression defines how a pgBackRest backup compresses files.
+type PGBackRestCompression struct {
+	// The compression algorithm.
+	// +kubebuilder:validation:Enum={none,bz2,gz,lz4,zst}
+	Type string `json:"type"`
+
+	// The compression level. The range depends on type: bz2 is 1 to 9, gz is
+	// 0 to 9, lz4 is -5 to 12, and zst is -7 to 22.
+	// +optional
+	Level *int32 `json:"level,omitempty"`
 }
 
 // RepoHostStatus defines the status of a pgBackRest repository host
@@ -331,6 +739,10 @@ type RepoHostStatus struct {
 // RepoPVC represents a pgBackRest repository that is created using a PersistentVolumeClaim
 type RepoPVC struct {
 
+	// Increases the storage request of the repository volume as it fills.
+	// +optional
+	AutoGrow *VolumeAutoGrow `json:"autoGrow,omitempty"`
+
 	// Defines a PersistentVolumeClaim spec used to create and/or bind a volume
 	// +kubebuilder:validation:Required
 	VolumeClaimSpec corev1.PersistentVolumeClaimSpec `json:"volumeClaimSpec"`
@@ -342,6 +754,31 @@ type RepoAzure struct {
 	// The Azure container utilized for the repository
 	// +kubebuilder:validation:Required
 	Container string `json:"container"`
+
+	// The Azure storage account. This may also be set in a configuration file
+	// along with the account key.
+	// +optional
+	Account string `json:"account,omitempty"`
+
+	// The type of the key set in the configuration: "shared" for a shared key
+	// or "sas" for a shared access signature. Defaults to "shared".
+	// +optional
+	// +kubebuilder:validation:Enum={shared,sas}
+	KeyType string `json:"keyType,omitempty"`
+
+	// The endpoint of the Azure Blob service. Defaults to blob.core.windows.net.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Whether the account is part of the hostname ("host") or the path
+	// ("path") of requests. Defaults to "host".
+	// +optional
+	// +kubebuilder:validation:Enum={host,path}
+	URIStyle string `json:"uriStyle,omitempty"`
+
+	// Defines how to connect to the storage service.
+	// +optional
+	Connection *RepoStorageConnection `json:"connection,omitempty"`
 }
 
 // RepoGCS represents a pgBackRest repository that is created using Google Cloud Storage
@@ -350,6 +787,21 @@ type RepoGCS struct {
 	// The GCS bucket utilized for the repository
 	// +kubebuilder:validation:Required
 	Bucket string `json:"bucket"`
+
+	// How pgBackRest authenticates: "service" for a service account key file,
+	// "token" for a token in the configuration, or "auto" for the credentials
+	// of the instance or workload identity. Defaults to "service".
+	// +optional
+	// +kubebuilder:validation:Enum={service,token,auto}
+	KeyType string `json:"keyType,omitempty"`
+
+	// The endpoint of the GCS service. Defaults to storage.googleapis.com.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Defines how to connect to the storage service.
+	// +optional
+	Connection *RepoStorageConnection `json:"connection,omitempty"`
 }
 
 // RepoS3 represents a pgBackRest repository that is created using AWS S3 (or S3-compatible)
@@ -367,6 +819,109 @@ type RepoS3 struct {
 	// The region corresponding to the S3 bucket
 	// +kubebuilder:validation:Required
 	Region string `json:"region"`
+
+	// How pgBackRest authenticates: "shared" for keys in the configuration,
+	// "auto" for the role of the instance, or "web-id" for a web identity token
+	// such as IAM Roles for Service Accounts. Defaults to "shared".
+	// +optional
+	// +kubebuilder:validation:Enum={shared,auto,web-id}
+	KeyType string `json:"keyType,omitempty"`
+
+	// The name of the role to assume when keyType is "auto".
+	// +optional
+	Role string `json:"role,omitempty"`
+
+	// Whether the bucket is part of the hostname ("host") or the path ("path")
+	// of requests. Many S3-compatible services, such as MinIO, require "path".
+	// Defaults to "host".
+	// +optional
+	// +kubebuilder:validation:Enum={host,path}
+	URIStyle string `json:"uriStyle,omitempty"`
+
+	// The ID of the KMS key used to encrypt objects on the server.
+	// +optional
+	KMSKeyID string `json:"kmsKeyID,omitempty"`
+
+	// The storage class of new objects, such as STANDARD_IA.
+	// +optional
+	StorageClass string `json:"storageClass,omitempty"`
+
+	// Defines how to connect to the storage service.
+	// +optional
+	Connection *RepoStorageConnection `json:"connection,omitempty"`
+}
+
+// RepoStorageConnection defines how pgBackRest connects to the object storage
+// service of a repository.
+type RepoStorageConnection struct {
+
+	// The port of the storage service. Defaults to 443.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port *int32 `json:"port,omitempty"`
+
+	// Whether or not to verify the TLS certificate of the storage service.
+	// Defaults to true.
+	// +optional
+	VerifyTLS *bool `json:"verifyTLS,omitempty"`
+
+	// A ConfigMap key holding the certificate authorities, in PEM format, that
+	// are trusted to sign the certificate of the storage service. The ConfigMap
+	// must be in the namespace of the PostgresCluster.
+	// +optional
+	CABundle *corev1.ConfigMapKeySelector `json:"caBundle,omitempty"`
+}
+
+// RepoSFTP represents a pgBackRest repository on an SFTP server. Requires
+// pgBackRest v2.46 or later.
+type RepoSFTP struct {
+
+	// The hostname or IP address of the SFTP server
+	// +kubebuilder:validation:Required
+	Host string `json:"host"`
+
+	// The port of the SFTP server. Defaults to 22.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port *int32 `json:"port,omitempty"`
+
+	// The user that logs into the SFTP server
+	// +kubebuilder:validation:Required
+	User string `json:"user"`
+
+	// The fingerprint of the host key of the SFTP server, in hexadecimal. The
+	// connection fails when the server presents a different key.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[0-9A-Fa-f:]+$`
+	HostKeyFingerprint string `json:"hostKeyFingerprint"`
+
+	// The hash algorithm of hostKeyFingerprint. Defaults to sha256.
+	// +optional
+	// +kubebuilder:validation:Enum={md5,sha1,sha256}
+	HostKeyHashType string `json:"hostKeyHashType,omitempty"`
+
+	// A key of a Secret in the PostgresCluster namespace that contains the
+	// private key used to log into the SFTP server
+	// +kubebuilder:validation:Required
+	PrivateKeySecret corev1.SecretKeySelector `json:"privateKeySecret"`
+}
+
+// RepoSharedVolume represents a pgBackRest repository on a filesystem that
+// every pod of the cluster mounts. Only one of its members may be specified.
+type RepoSharedVolume struct {
+
+	// The name of an existing PersistentVolumeClaim in the PostgresCluster
+	// namespace. Its volume must support the ReadWriteMany access mode, e.g.
+	// an SMB share mounted by a CSI driver.
+	// +optional
+	ClaimName string `json:"claimName,omitempty"`
+
+	// An NFS export
+	// More info: https://kubernetes.io/docs/concepts/storage/volumes#nfs
+	// +optional
+	NFS *corev1.NFSVolumeSource `json:"nfs,omitempty"`
 }
 
 // RepoStatus the status of a pgBackRest repository
@@ -397,4 +952,62 @@ type RepoStatus struct {
 	// commands accordingly.
 	// +optional
 	RepoOptionsHash string `json:"repoOptionsHash,omitempty"`
+
+	// The 20 most recent backups in the repository, oldest first, as reported
+	// by "pgbackrest info".
+	// +optional
+	Backups []PGBackRestBackupInfo `json:"backups,omitempty"`
+
+	// The oldest WAL file archived in the repository.
+	// +optional
+	ArchiveMin string `json:"archiveMin,omitempty"`
+
+	// The newest WAL file archived in the repository.
+	// +optional
+	ArchiveMax string `json:"archiveMax,omitempty"`
+
+	// The earliest time the cluster can be restored to using this repository.
+	// This is when the oldest backup with its WAL still in the repository
+	// finished.
+	// +optional
+	EarliestRecoveryTime *metav1.Time `json:"earliestRecoveryTime,omitempty"`
+
+	// The cipher that encrypts the repository, if any.
+	// +optional
+	Cipher string `json:"cipher,omitempty"`
+}
+
+// PGBackRestBackupInfo describes one backup in a pgBackRest repository.
+type PGBackRestBackupInfo struct {
+	// The label pgBackRest gave the backup, e.g. 20210601-120000F.
+	Label string `json:"label"`
+
+	// The type of backup: full, diff, or incr.
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// When the backup started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// When the backup finished.
+	// +optional
+	StopTime *metav1.Time `json:"stopTime,omitempty"`
+
+	// The size of the database when it was backed up.
+	// +optional
+	DatabaseSizeBytes int64 `json:"databaseSizeBytes,omitempty"`
+
+	// The size of this backup in the repository, after compression. Differential
+	// and incremental backups count only the files they added.
+	// +optional
+	RepositorySizeBytes int64 `json:"repositorySizeBytes,omitempty"`
+
+	// The first WAL file needed to make the backup consistent.
+	// +optional
+	ArchiveStart string `json:"archiveStart,omitempty"`
+
+	// The last WAL file needed to make the backup consistent.
+	// +optional
+	ArchiveStop string `json:"archiveStop,omitempty"`
 }
@@ -17,6 +17,7 @@ package v1beta1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // PGBouncerConfiguration represents PgBouncer configuration files.
@@ -87,6 +88,12 @@ type PGBouncerPodSpec struct {
 	// +optional
 	Image string `json:"image,omitempty"`
 
+	// Minimum number of pods that should be available at a time.
+	// Defaults to one when the replicas field is greater than one.
+	// A PodDisruptionBudget is not created when this resolves to zero.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
 	// Port on which PgBouncer should listen for client connections. Changing
 	// this value causes PgBouncer to restart.
 	// +optional
@@ -20,6 +20,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // PostgresClusterSpec defines the desired state of PostgresCluster
@@ -355,6 +356,16 @@ type PostgresClusterStatus struct {
 	// Identifies the users that have been installed into PostgreSQL.
 	UsersRevision string `json:"usersRevision,omitempty"`
 
+	// Names of the volumes that have passed their autoGrow threshold but
+	// cannot grow any further.
+	// +optional
+	VolumeAutoGrowBlocked []string `json:"volumeAutoGrowBlocked,omitempty"`
+
+	// The last time the filesystems of volumes that grow automatically were
+	// measured.
+	// +optional
+	VolumeAutoGrowObservedTime *metav1.Time `json:"volumeAutoGrowObservedTime,omitempty"`
+
 	// Current state of PostgreSQL cluster monitoring tool configuration
 	// +optional
 	Monitoring MonitoringStatus `json:"monitoring,omitempty"`
@@ -382,6 +393,8 @@ type PostgresClusterStatus struct {
 const (
 	PersistentVolumeResizing = "PersistentVolumeResizing"
 	ProxyAvailable           = "ProxyAvailable"
+	PatroniPaused            = "PatroniPaused"
+	ReplicationLagHigh       = "ReplicationLagHigh"
 )
 
 type PostgresInstanceSetSpec struct {
@@ -406,17 +419,40 @@ type PostgresInstanceSetSpec struct {
 	// +optional
 	Affinity *corev1.Affinity `json:"affinity,omitempty"`
 
+	// Increases the storage request of the PostgreSQL data volume as it fills.
+	// +optional
+	DataVolumeAutoGrow *VolumeAutoGrow `json:"dataVolumeAutoGrow,omitempty"`
+
 	// Defines a PersistentVolumeClaim for PostgreSQL data.
 	// More info: https://kubernetes.io/docs/concepts/storage/persistent-volumes
 	// +kubebuilder:validation:Required
 	DataVolumeClaimSpec corev1.PersistentVolumeClaimSpec `json:"dataVolumeClaimSpec"`
 
+	// Minimum number of pods that should be available at a time.
+	// Defaults to one when the replicas field is greater than one.
+	// A PodDisruptionBudget is not created when this resolves to zero.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// Patroni tags of the PostgreSQL instances in this set.
+	// +optional
+	PatroniTags *PatroniTags `json:"patroniTags,omitempty"`
+
 	// Priority class name for the PostgreSQL pod. Changing this value causes
 	// PostgreSQL to restart.
 	// More info: https://kubernetes.io/docs/concepts/scheduling-eviction/pod-priority-preemption/
 	// +optional
 	PriorityClassName *string `json:"priorityClassName,omitempty"`
 
+	// Delays the replay of WAL on instances in this set by at least this
+	// amount of time. Instances in this set are never promoted to primary and
+	// receive no traffic from the replica Service. At least one instance set
+	// must be neither delayed nor tagged noFailover. Changing this value causes
+	// PostgreSQL to restart.
+	// More info: https://www.postgresql.org/docs/current/runtime-config-replication.html#GUC-RECOVERY-MIN-APPLY-DELAY
+	// +optional
+	RecoveryMinApplyDelay *metav1.Duration `json:"recoveryMinApplyDelay,omitempty"`
+
 	// Number of desired PostgreSQL pods.
 	// +optional
 	// +kubebuilder:default=1
@@ -431,6 +467,15 @@ type PostgresInstanceSetSpec struct {
 	// +optional
 	Sidecars *InstanceSidecars `json:"sidecars,omitempty"`
 
+	// Defines additional PersistentVolumeClaims for PostgreSQL tablespaces.
+	// Each instance gets one volume per tablespace. A tablespace is created in
+	// PostgreSQL when every instance set defines a volume for it.
+	// More info: https://www.postgresql.org/docs/current/manage-ag-tablespaces.html
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	TablespaceVolumes []TablespaceVolume `json:"tablespaceVolumes,omitempty"`
+
 	// Tolerations of a PostgreSQL pod. Changing this value causes PostgreSQL to restart.
 	// More info: https://kubernetes.io/docs/concepts/scheduling-eviction/taint-and-toleration
 	// +optional
@@ -442,6 +487,11 @@ type PostgresInstanceSetSpec struct {
 	// +optional
 	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
 
+	// Increases the storage request of the PostgreSQL WAL volume as it fills.
+	// Requires walVolumeClaimSpec.
+	// +optional
+	WALVolumeAutoGrow *VolumeAutoGrow `json:"walVolumeAutoGrow,omitempty"`
+
 	// Defines a separate PersistentVolumeClaim for PostgreSQL's write-ahead log.
 	// More info: https://www.postgresql.org/docs/current/wal.html
 	// +optional
@@ -455,6 +505,22 @@ type InstanceSidecars struct {
 	ReplicaCertCopy *Sidecar `json:"replicaCertCopy,omitempty"`
 }
 
+// TablespaceVolume defines a PersistentVolumeClaim for a PostgreSQL tablespace.
+type TablespaceVolume struct {
+	// The name of the tablespace in PostgreSQL. It is also part of the name of
+	// each volume and the path where that volume is mounted. Changing this
+	// value does not rename an existing tablespace.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=50
+	// +kubebuilder:validation:Pattern=`^[a-z][a-z0-9]*$`
+	Name string `json:"name"`
+
+	// Defines a PersistentVolumeClaim for the tablespace.
+	// More info: https://kubernetes.io/docs/concepts/storage/persistent-volumes
+	// +kubebuilder:validation:Required
+	DataVolumeClaimSpec corev1.PersistentVolumeClaimSpec `json:"dataVolumeClaimSpec"`
+}
+
 // Default sets the default values for an instance set spec, including the name
 // suffix and number of replicas.
 func (s *PostgresInstanceSetSpec) Default(i int) {
@@ -478,6 +544,19 @@ type PostgresInstanceSetStatus struct {
 	// +optional
 	Replicas int32 `json:"replicas,omitempty"`
 
+	// The greatest amount of time between a transaction committing on the
+	// primary and being replayed on an instance of this set. This is only
+	// measured when recoveryMinApplyDelay is set. An instance that has replayed
+	// all the WAL it received has no delay. Otherwise, the delay is measured
+	// from the last transaction it replayed and grows while the primary is
+	// idle.
+	// +optional
+	ReplayDelay *metav1.Duration `json:"replayDelay,omitempty"`
+
+	// The last time the replay delay of this set was measured.
+	// +optional
+	ReplayDelayObservedTime *metav1.Time `json:"replayDelayObservedTime,omitempty"`
+
 	// Total number of non-terminated pods that have the desired specification.
 	// +optional
 	UpdatedReplicas int32 `json:"updatedReplicas,omitempty"`
@@ -15,7 +15,10 @@
 
 package v1beta1
 
-import corev1 "k8s.io/api/core/v1"
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
 
 type ServiceSpec struct {
 	// More info: https://kubernetes.io/docs/concepts/services-networking/service/#publishing-services-service-types
@@ -31,3 +34,26 @@ type Sidecar struct {
 	// +optional
 	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
 }
+
+// VolumeAutoGrow defines when and how much to increase the storage request of
+// a PersistentVolumeClaim based on the usage of its filesystem. The volume can
+// only grow when its StorageClass allows volume expansion.
+// More info: https://kubernetes.io/docs/concepts/storage/persistent-volumes/#expanding-persistent-volumes-claims
+type VolumeAutoGrow struct {
+	// Amount by which to increase the storage request each time the threshold
+	// is passed.
+	// +kubebuilder:validation:Required
+	Increase resource.Quantity `json:"increase"`
+
+	// Largest storage request the volume is allowed to grow to.
+	// +kubebuilder:validation:Required
+	Limit resource.Quantity `json:"limit"`
+
+	// Percentage of the filesystem that must be in use before the storage
+	// request is increased.
+	// +optional
+	// +kubebuilder:default=90
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=99
+	Threshold int32 `json:"threshold,omitempty"`
+}
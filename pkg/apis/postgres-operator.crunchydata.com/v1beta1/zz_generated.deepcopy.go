@@ -24,6 +24,7 @@ import (
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -35,6 +36,57 @@ func (in *BackupJobs) DeepCopyInto(out *BackupJobs) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]v1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ActiveDeadlineSeconds != nil {
+		in, out := &in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SuccessfulJobsHistoryLimit != nil {
+		in, out := &in.SuccessfulJobsHistoryLimit, &out.SuccessfulJobsHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailedJobsHistoryLimit != nil {
+		in, out := &in.FailedJobsHistoryLimit, &out.FailedJobsHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupJobs.
@@ -269,6 +321,11 @@ func (in *PGAdminPodSpec) DeepCopyInto(out *PGAdminPodSpec) {
 		(*in).DeepCopyInto(*out)
 	}
 	in.DataVolumeClaimSpec.DeepCopyInto(&out.DataVolumeClaimSpec)
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
 	if in.Port != nil {
 		in, out := &in.Port, &out.Port
 		*out = new(int32)
@@ -375,6 +432,16 @@ func (in *PGBackRestArchive) DeepCopyInto(out *PGBackRestArchive) {
 		*out = new(PGBackRestManualBackup)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.BackupFromStandby != nil {
+		in, out := &in.BackupFromStandby, &out.BackupFromStandby
+		*out = new(PGBackRestBackupFromStandby)
+		**out = **in
+	}
+	if in.ArchiveAsync != nil {
+		in, out := &in.ArchiveAsync, &out.ArchiveAsync
+		*out = new(PGBackRestArchiveAsync)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Restore != nil {
 		in, out := &in.Restore, &out.Restore
 		*out = new(PGBackRestRestore)
@@ -397,6 +464,110 @@ func (in *PGBackRestArchive) DeepCopy() *PGBackRestArchive {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGBackRestArchiveAsync) DeepCopyInto(out *PGBackRestArchiveAsync) {
+	*out = *in
+	if in.ProcessMax != nil {
+		in, out := &in.ProcessMax, &out.ProcessMax
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PushQueueMax != nil {
+		in, out := &in.PushQueueMax, &out.PushQueueMax
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.GetQueueMax != nil {
+		in, out := &in.GetQueueMax, &out.GetQueueMax
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.VolumeClaimSpec != nil {
+		in, out := &in.VolumeClaimSpec, &out.VolumeClaimSpec
+		*out = new(v1.PersistentVolumeClaimSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestArchiveAsync.
+func (in *PGBackRestArchiveAsync) DeepCopy() *PGBackRestArchiveAsync {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBackRestArchiveAsync)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGBackRestArchivingStatus) DeepCopyInto(out *PGBackRestArchivingStatus) {
+	*out = *in
+	if in.LastArchivedTime != nil {
+		in, out := &in.LastArchivedTime, &out.LastArchivedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastFailedTime != nil {
+		in, out := &in.LastFailedTime, &out.LastFailedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CheckTime != nil {
+		in, out := &in.CheckTime, &out.CheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ObservedTime != nil {
+		in, out := &in.ObservedTime, &out.ObservedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestArchivingStatus.
+func (in *PGBackRestArchivingStatus) DeepCopy() *PGBackRestArchivingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBackRestArchivingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGBackRestBackupFromStandby) DeepCopyInto(out *PGBackRestBackupFromStandby) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestBackupFromStandby.
+func (in *PGBackRestBackupFromStandby) DeepCopy() *PGBackRestBackupFromStandby {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBackRestBackupFromStandby)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGBackRestBackupInfo) DeepCopyInto(out *PGBackRestBackupInfo) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.StopTime != nil {
+		in, out := &in.StopTime, &out.StopTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestBackupInfo.
+func (in *PGBackRestBackupInfo) DeepCopy() *PGBackRestBackupInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBackRestBackupInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PGBackRestBackupSchedules) DeepCopyInto(out *PGBackRestBackupSchedules) {
 	*out = *in
@@ -415,6 +586,11 @@ func (in *PGBackRestBackupSchedules) DeepCopyInto(out *PGBackRestBackupSchedules
 		*out = new(string)
 		**out = **in
 	}
+	if in.Suspend != nil {
+		in, out := &in.Suspend, &out.Suspend
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestBackupSchedules.
@@ -427,6 +603,46 @@ func (in *PGBackRestBackupSchedules) DeepCopy() *PGBackRestBackupSchedules {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGBackRestCompression) DeepCopyInto(out *PGBackRestCompression) {
+	*out = *in
+	if in.Level != nil {
+		in, out := &in.Level, &out.Level
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestCompression.
+func (in *PGBackRestCompression) DeepCopy() *PGBackRestCompression {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBackRestCompression)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGBackRestEncryption) DeepCopyInto(out *PGBackRestEncryption) {
+	*out = *in
+	if in.PassphraseSecret != nil {
+		in, out := &in.PassphraseSecret, &out.PassphraseSecret
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestEncryption.
+func (in *PGBackRestEncryption) DeepCopy() *PGBackRestEncryption {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBackRestEncryption)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PGBackRestJobStatus) DeepCopyInto(out *PGBackRestJobStatus) {
 	*out = *in
@@ -481,23 +697,68 @@ func (in *PGBackRestRepo) DeepCopyInto(out *PGBackRestRepo) {
 	if in.Azure != nil {
 		in, out := &in.Azure, &out.Azure
 		*out = new(RepoAzure)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.GCS != nil {
 		in, out := &in.GCS, &out.GCS
 		*out = new(RepoGCS)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.S3 != nil {
 		in, out := &in.S3, &out.S3
 		*out = new(RepoS3)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Volume != nil {
 		in, out := &in.Volume, &out.Volume
 		*out = new(RepoPVC)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.SFTP != nil {
+		in, out := &in.SFTP, &out.SFTP
+		*out = new(RepoSFTP)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SharedVolume != nil {
+		in, out := &in.SharedVolume, &out.SharedVolume
+		*out = new(RepoSharedVolume)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(PGBackRestRetention)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Compression != nil {
+		in, out := &in.Compression, &out.Compression
+		*out = new(PGBackRestCompression)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProcessMax != nil {
+		in, out := &in.ProcessMax, &out.ProcessMax
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Bundle != nil {
+		in, out := &in.Bundle, &out.Bundle
+		*out = new(bool)
+		**out = **in
+	}
+	if in.BlockIncremental != nil {
+		in, out := &in.BlockIncremental, &out.BlockIncremental
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(PGBackRestEncryption)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(PGBackRestVerification)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestRepo.
@@ -518,6 +779,11 @@ func (in *PGBackRestRepoHost) DeepCopyInto(out *PGBackRestRepoHost) {
 		*out = new(v1.Affinity)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
 	if in.PriorityClassName != nil {
 		in, out := &in.PriorityClassName, &out.PriorityClassName
 		*out = new(string)
@@ -585,6 +851,36 @@ func (in *PGBackRestRestore) DeepCopy() *PGBackRestRestore {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGBackRestRetention) DeepCopyInto(out *PGBackRestRetention) {
+	*out = *in
+	if in.Full != nil {
+		in, out := &in.Full, &out.Full
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Diff != nil {
+		in, out := &in.Diff, &out.Diff
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Archive != nil {
+		in, out := &in.Archive, &out.Archive
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestRetention.
+func (in *PGBackRestRetention) DeepCopy() *PGBackRestRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBackRestRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PGBackRestScheduledBackupStatus) DeepCopyInto(out *PGBackRestScheduledBackupStatus) {
 	*out = *in
@@ -651,13 +947,31 @@ func (in *PGBackRestStatus) DeepCopyInto(out *PGBackRestStatus) {
 	if in.Repos != nil {
 		in, out := &in.Repos, &out.Repos
 		*out = make([]RepoStatus, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Restore != nil {
 		in, out := &in.Restore, &out.Restore
 		*out = new(PGBackRestJobStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.BackupsObservedTime != nil {
+		in, out := &in.BackupsObservedTime, &out.BackupsObservedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Verifications != nil {
+		in, out := &in.Verifications, &out.Verifications
+		*out = make([]PGBackRestVerificationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Archiving != nil {
+		in, out := &in.Archiving, &out.Archiving
+		*out = new(PGBackRestArchivingStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestStatus.
@@ -670,6 +984,93 @@ func (in *PGBackRestStatus) DeepCopy() *PGBackRestStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGBackRestVerification) DeepCopyInto(out *PGBackRestVerification) {
+	*out = *in
+	in.VolumeClaimSpec.DeepCopyInto(&out.VolumeClaimSpec)
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]PGBackRestVerificationCheck, len(*in))
+		copy(*out, *in)
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PriorityClassName != nil {
+		in, out := &in.PriorityClassName, &out.PriorityClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.ActiveDeadlineSeconds != nil {
+		in, out := &in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestVerification.
+func (in *PGBackRestVerification) DeepCopy() *PGBackRestVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBackRestVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGBackRestVerificationCheck) DeepCopyInto(out *PGBackRestVerificationCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestVerificationCheck.
+func (in *PGBackRestVerificationCheck) DeepCopy() *PGBackRestVerificationCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBackRestVerificationCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGBackRestVerificationStatus) DeepCopyInto(out *PGBackRestVerificationStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestVerificationStatus.
+func (in *PGBackRestVerificationStatus) DeepCopy() *PGBackRestVerificationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBackRestVerificationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PGBouncerConfiguration) DeepCopyInto(out *PGBouncerConfiguration) {
 	*out = *in
@@ -732,6 +1133,11 @@ func (in *PGBouncerPodSpec) DeepCopyInto(out *PGBouncerPodSpec) {
 		*out = new(v1.SecretProjection)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
 	if in.Port != nil {
 		in, out := &in.Port, &out.Port
 		*out = new(int32)
@@ -774,120 +1180,286 @@ func (in *PGBouncerPodSpec) DeepCopyInto(out *PGBouncerPodSpec) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBouncerPodSpec.
-func (in *PGBouncerPodSpec) DeepCopy() *PGBouncerPodSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBouncerPodSpec.
+func (in *PGBouncerPodSpec) DeepCopy() *PGBouncerPodSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBouncerPodSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGBouncerPodStatus) DeepCopyInto(out *PGBouncerPodStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBouncerPodStatus.
+func (in *PGBouncerPodStatus) DeepCopy() *PGBouncerPodStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBouncerPodStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGBouncerSidecars) DeepCopyInto(out *PGBouncerSidecars) {
+	*out = *in
+	if in.PGBouncerConfig != nil {
+		in, out := &in.PGBouncerConfig, &out.PGBouncerConfig
+		*out = new(Sidecar)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBouncerSidecars.
+func (in *PGBouncerSidecars) DeepCopy() *PGBouncerSidecars {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBouncerSidecars)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGMajorUpgrade) DeepCopyInto(out *PGMajorUpgrade) {
+	*out = *in
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = new(Metadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGMajorUpgrade.
+func (in *PGMajorUpgrade) DeepCopy() *PGMajorUpgrade {
+	if in == nil {
+		return nil
+	}
+	out := new(PGMajorUpgrade)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGMonitorSpec) DeepCopyInto(out *PGMonitorSpec) {
+	*out = *in
+	if in.Exporter != nil {
+		in, out := &in.Exporter, &out.Exporter
+		*out = new(ExporterSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGMonitorSpec.
+func (in *PGMonitorSpec) DeepCopy() *PGMonitorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PGMonitorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGUpgradeStatus) DeepCopyInto(out *PGUpgradeStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGUpgradeStatus.
+func (in *PGUpgradeStatus) DeepCopy() *PGUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PGUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatroniDCS) DeepCopyInto(out *PatroniDCS) {
+	*out = *in
+	if in.Etcd != nil {
+		in, out := &in.Etcd, &out.Etcd
+		*out = new(PatroniEtcd)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatroniDCS.
+func (in *PatroniDCS) DeepCopy() *PatroniDCS {
+	if in == nil {
+		return nil
+	}
+	out := new(PatroniDCS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatroniEtcd) DeepCopyInto(out *PatroniEtcd) {
+	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TLSSecret != nil {
+		in, out := &in.TLSSecret, &out.TLSSecret
+		*out = new(v1.SecretProjection)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatroniEtcd.
+func (in *PatroniEtcd) DeepCopy() *PatroniEtcd {
 	if in == nil {
 		return nil
 	}
-	out := new(PGBouncerPodSpec)
+	out := new(PatroniEtcd)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PGBouncerPodStatus) DeepCopyInto(out *PGBouncerPodStatus) {
+func (in *PatroniFailoverLimits) DeepCopyInto(out *PatroniFailoverLimits) {
 	*out = *in
+	if in.MaximumLag != nil {
+		in, out := &in.MaximumLag, &out.MaximumLag
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.PrimaryStartTimeoutSeconds != nil {
+		in, out := &in.PrimaryStartTimeoutSeconds, &out.PrimaryStartTimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CheckTimeline != nil {
+		in, out := &in.CheckTimeline, &out.CheckTimeline
+		*out = new(bool)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBouncerPodStatus.
-func (in *PGBouncerPodStatus) DeepCopy() *PGBouncerPodStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatroniFailoverLimits.
+func (in *PatroniFailoverLimits) DeepCopy() *PatroniFailoverLimits {
 	if in == nil {
 		return nil
 	}
-	out := new(PGBouncerPodStatus)
+	out := new(PatroniFailoverLimits)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PGBouncerSidecars) DeepCopyInto(out *PGBouncerSidecars) {
+func (in *PatroniMemberStatus) DeepCopyInto(out *PatroniMemberStatus) {
 	*out = *in
-	if in.PGBouncerConfig != nil {
-		in, out := &in.PGBouncerConfig, &out.PGBouncerConfig
-		*out = new(Sidecar)
-		(*in).DeepCopyInto(*out)
+	if in.ReplicationLagBytes != nil {
+		in, out := &in.ReplicationLagBytes, &out.ReplicationLagBytes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.FailedTime != nil {
+		in, out := &in.FailedTime, &out.FailedTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBouncerSidecars.
-func (in *PGBouncerSidecars) DeepCopy() *PGBouncerSidecars {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatroniMemberStatus.
+func (in *PatroniMemberStatus) DeepCopy() *PatroniMemberStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PGBouncerSidecars)
+	out := new(PatroniMemberStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PGMajorUpgrade) DeepCopyInto(out *PGMajorUpgrade) {
+func (in *PatroniPermanentSlot) DeepCopyInto(out *PatroniPermanentSlot) {
 	*out = *in
-	if in.Metadata != nil {
-		in, out := &in.Metadata, &out.Metadata
-		*out = new(Metadata)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = new(bool)
-		**out = **in
-	}
-	if in.Image != nil {
-		in, out := &in.Image, &out.Image
-		*out = new(string)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGMajorUpgrade.
-func (in *PGMajorUpgrade) DeepCopy() *PGMajorUpgrade {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatroniPermanentSlot.
+func (in *PatroniPermanentSlot) DeepCopy() *PatroniPermanentSlot {
 	if in == nil {
 		return nil
 	}
-	out := new(PGMajorUpgrade)
+	out := new(PatroniPermanentSlot)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PGMonitorSpec) DeepCopyInto(out *PGMonitorSpec) {
+func (in *PatroniReinitialization) DeepCopyInto(out *PatroniReinitialization) {
 	*out = *in
-	if in.Exporter != nil {
-		in, out := &in.Exporter, &out.Exporter
-		*out = new(ExporterSpec)
-		(*in).DeepCopyInto(*out)
+	if in.FailedSeconds != nil {
+		in, out := &in.FailedSeconds, &out.FailedSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxConcurrent != nil {
+		in, out := &in.MaxConcurrent, &out.MaxConcurrent
+		*out = new(int32)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGMonitorSpec.
-func (in *PGMonitorSpec) DeepCopy() *PGMonitorSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatroniReinitialization.
+func (in *PatroniReinitialization) DeepCopy() *PatroniReinitialization {
 	if in == nil {
 		return nil
 	}
-	out := new(PGMonitorSpec)
+	out := new(PatroniReinitialization)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PGUpgradeStatus) DeepCopyInto(out *PGUpgradeStatus) {
+func (in *PatroniSlotStatus) DeepCopyInto(out *PatroniSlotStatus) {
 	*out = *in
-	if in.StartTime != nil {
-		in, out := &in.StartTime, &out.StartTime
-		*out = (*in).DeepCopy()
-	}
-	if in.CompletionTime != nil {
-		in, out := &in.CompletionTime, &out.CompletionTime
-		*out = (*in).DeepCopy()
+	if in.RetainedBytes != nil {
+		in, out := &in.RetainedBytes, &out.RetainedBytes
+		*out = new(int64)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGUpgradeStatus.
-func (in *PGUpgradeStatus) DeepCopy() *PGUpgradeStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatroniSlotStatus.
+func (in *PatroniSlotStatus) DeepCopy() *PatroniSlotStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PGUpgradeStatus)
+	out := new(PatroniSlotStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -896,11 +1468,36 @@ func (in *PGUpgradeStatus) DeepCopy() *PGUpgradeStatus {
 func (in *PatroniSpec) DeepCopyInto(out *PatroniSpec) {
 	*out = *in
 	in.DynamicConfiguration.DeepCopyInto(&out.DynamicConfiguration)
+	if in.Failover != nil {
+		in, out := &in.Failover, &out.Failover
+		*out = new(PatroniFailoverLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Reinitialization != nil {
+		in, out := &in.Reinitialization, &out.Reinitialization
+		*out = new(PatroniReinitialization)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UseSlots != nil {
+		in, out := &in.UseSlots, &out.UseSlots
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PermanentSlots != nil {
+		in, out := &in.PermanentSlots, &out.PermanentSlots
+		*out = make([]PatroniPermanentSlot, len(*in))
+		copy(*out, *in)
+	}
 	if in.LeaderLeaseDurationSeconds != nil {
 		in, out := &in.LeaderLeaseDurationSeconds, &out.LeaderLeaseDurationSeconds
 		*out = new(int32)
 		**out = **in
 	}
+	if in.ReplicationLagThreshold != nil {
+		in, out := &in.ReplicationLagThreshold, &out.ReplicationLagThreshold
+		x := (*in).DeepCopy()
+		*out = &x
+	}
 	if in.Port != nil {
 		in, out := &in.Port, &out.Port
 		*out = new(int32)
@@ -916,6 +1513,16 @@ func (in *PatroniSpec) DeepCopyInto(out *PatroniSpec) {
 		*out = new(PatroniSwitchover)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Synchronous != nil {
+		in, out := &in.Synchronous, &out.Synchronous
+		*out = new(PatroniSynchronous)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DCS != nil {
+		in, out := &in.DCS, &out.DCS
+		*out = new(PatroniDCS)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatroniSpec.
@@ -936,6 +1543,41 @@ func (in *PatroniStatus) DeepCopyInto(out *PatroniStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.SwitchoverScheduledAt != nil {
+		in, out := &in.SwitchoverScheduledAt, &out.SwitchoverScheduledAt
+		*out = (*in).DeepCopy()
+	}
+	if in.LastFailoverTime != nil {
+		in, out := &in.LastFailoverTime, &out.LastFailoverTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSwitchoverTime != nil {
+		in, out := &in.LastSwitchoverTime, &out.LastSwitchoverTime
+		*out = (*in).DeepCopy()
+	}
+	if in.SynchronousStandbys != nil {
+		in, out := &in.SynchronousStandbys, &out.SynchronousStandbys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]PatroniMemberStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MembersObservedTime != nil {
+		in, out := &in.MembersObservedTime, &out.MembersObservedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ReplicationSlots != nil {
+		in, out := &in.ReplicationSlots, &out.ReplicationSlots
+		*out = make([]PatroniSlotStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatroniStatus.
@@ -956,6 +1598,10 @@ func (in *PatroniSwitchover) DeepCopyInto(out *PatroniSwitchover) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.ScheduledAt != nil {
+		in, out := &in.ScheduledAt, &out.ScheduledAt
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatroniSwitchover.
@@ -968,6 +1614,46 @@ func (in *PatroniSwitchover) DeepCopy() *PatroniSwitchover {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatroniSynchronous) DeepCopyInto(out *PatroniSynchronous) {
+	*out = *in
+	if in.StandbyCount != nil {
+		in, out := &in.StandbyCount, &out.StandbyCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.InstanceSets != nil {
+		in, out := &in.InstanceSets, &out.InstanceSets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatroniSynchronous.
+func (in *PatroniSynchronous) DeepCopy() *PatroniSynchronous {
+	if in == nil {
+		return nil
+	}
+	out := new(PatroniSynchronous)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatroniTags) DeepCopyInto(out *PatroniTags) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatroniTags.
+func (in *PatroniTags) DeepCopy() *PatroniTags {
+	if in == nil {
+		return nil
+	}
+	out := new(PatroniTags)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresCluster) DeepCopyInto(out *PostgresCluster) {
 	*out = *in
@@ -1191,7 +1877,9 @@ func (in *PostgresClusterStatus) DeepCopyInto(out *PostgresClusterStatus) {
 	if in.InstanceSets != nil {
 		in, out := &in.InstanceSets, &out.InstanceSets
 		*out = make([]PostgresInstanceSetStatus, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	in.Patroni.DeepCopyInto(&out.Patroni)
 	if in.PGBackRest != nil {
@@ -1210,6 +1898,15 @@ func (in *PostgresClusterStatus) DeepCopyInto(out *PostgresClusterStatus) {
 		*out = new(PostgresUserInterfaceStatus)
 		**out = **in
 	}
+	if in.VolumeAutoGrowBlocked != nil {
+		in, out := &in.VolumeAutoGrowBlocked, &out.VolumeAutoGrowBlocked
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.VolumeAutoGrowObservedTime != nil {
+		in, out := &in.VolumeAutoGrowObservedTime, &out.VolumeAutoGrowObservedTime
+		*out = (*in).DeepCopy()
+	}
 	out.Monitoring = in.Monitoring
 	if in.DatabaseInitSQL != nil {
 		in, out := &in.DatabaseInitSQL, &out.DatabaseInitSQL
@@ -1248,12 +1945,32 @@ func (in *PostgresInstanceSetSpec) DeepCopyInto(out *PostgresInstanceSetSpec) {
 		*out = new(v1.Affinity)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DataVolumeAutoGrow != nil {
+		in, out := &in.DataVolumeAutoGrow, &out.DataVolumeAutoGrow
+		*out = new(VolumeAutoGrow)
+		(*in).DeepCopyInto(*out)
+	}
 	in.DataVolumeClaimSpec.DeepCopyInto(&out.DataVolumeClaimSpec)
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.PatroniTags != nil {
+		in, out := &in.PatroniTags, &out.PatroniTags
+		*out = new(PatroniTags)
+		**out = **in
+	}
 	if in.PriorityClassName != nil {
 		in, out := &in.PriorityClassName, &out.PriorityClassName
 		*out = new(string)
 		**out = **in
 	}
+	if in.RecoveryMinApplyDelay != nil {
+		in, out := &in.RecoveryMinApplyDelay, &out.RecoveryMinApplyDelay
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	if in.Replicas != nil {
 		in, out := &in.Replicas, &out.Replicas
 		*out = new(int32)
@@ -1265,6 +1982,13 @@ func (in *PostgresInstanceSetSpec) DeepCopyInto(out *PostgresInstanceSetSpec) {
 		*out = new(InstanceSidecars)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TablespaceVolumes != nil {
+		in, out := &in.TablespaceVolumes, &out.TablespaceVolumes
+		*out = make([]TablespaceVolume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Tolerations != nil {
 		in, out := &in.Tolerations, &out.Tolerations
 		*out = make([]v1.Toleration, len(*in))
@@ -1279,6 +2003,11 @@ func (in *PostgresInstanceSetSpec) DeepCopyInto(out *PostgresInstanceSetSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.WALVolumeAutoGrow != nil {
+		in, out := &in.WALVolumeAutoGrow, &out.WALVolumeAutoGrow
+		*out = new(VolumeAutoGrow)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.WALVolumeClaimSpec != nil {
 		in, out := &in.WALVolumeClaimSpec, &out.WALVolumeClaimSpec
 		*out = new(v1.PersistentVolumeClaimSpec)
@@ -1299,6 +2028,15 @@ func (in *PostgresInstanceSetSpec) DeepCopy() *PostgresInstanceSetSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresInstanceSetStatus) DeepCopyInto(out *PostgresInstanceSetStatus) {
 	*out = *in
+	if in.ReplayDelay != nil {
+		in, out := &in.ReplayDelay, &out.ReplayDelay
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ReplayDelayObservedTime != nil {
+		in, out := &in.ReplayDelayObservedTime, &out.ReplayDelayObservedTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresInstanceSetStatus.
@@ -1401,6 +2139,11 @@ func (in *PostgresUserSpec) DeepCopy() *PostgresUserSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RepoAzure) DeepCopyInto(out *RepoAzure) {
 	*out = *in
+	if in.Connection != nil {
+		in, out := &in.Connection, &out.Connection
+		*out = new(RepoStorageConnection)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepoAzure.
@@ -1416,6 +2159,11 @@ func (in *RepoAzure) DeepCopy() *RepoAzure {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RepoGCS) DeepCopyInto(out *RepoGCS) {
 	*out = *in
+	if in.Connection != nil {
+		in, out := &in.Connection, &out.Connection
+		*out = new(RepoStorageConnection)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepoGCS.
@@ -1447,6 +2195,11 @@ func (in *RepoHostStatus) DeepCopy() *RepoHostStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RepoPVC) DeepCopyInto(out *RepoPVC) {
 	*out = *in
+	if in.AutoGrow != nil {
+		in, out := &in.AutoGrow, &out.AutoGrow
+		*out = new(VolumeAutoGrow)
+		(*in).DeepCopyInto(*out)
+	}
 	in.VolumeClaimSpec.DeepCopyInto(&out.VolumeClaimSpec)
 }
 
@@ -1463,6 +2216,11 @@ func (in *RepoPVC) DeepCopy() *RepoPVC {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RepoS3) DeepCopyInto(out *RepoS3) {
 	*out = *in
+	if in.Connection != nil {
+		in, out := &in.Connection, &out.Connection
+		*out = new(RepoStorageConnection)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepoS3.
@@ -1475,9 +2233,61 @@ func (in *RepoS3) DeepCopy() *RepoS3 {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepoSFTP) DeepCopyInto(out *RepoSFTP) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+	in.PrivateKeySecret.DeepCopyInto(&out.PrivateKeySecret)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepoSFTP.
+func (in *RepoSFTP) DeepCopy() *RepoSFTP {
+	if in == nil {
+		return nil
+	}
+	out := new(RepoSFTP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepoSharedVolume) DeepCopyInto(out *RepoSharedVolume) {
+	*out = *in
+	if in.NFS != nil {
+		in, out := &in.NFS, &out.NFS
+		*out = new(v1.NFSVolumeSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepoSharedVolume.
+func (in *RepoSharedVolume) DeepCopy() *RepoSharedVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(RepoSharedVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RepoStatus) DeepCopyInto(out *RepoStatus) {
 	*out = *in
+	if in.Backups != nil {
+		in, out := &in.Backups, &out.Backups
+		*out = make([]PGBackRestBackupInfo, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EarliestRecoveryTime != nil {
+		in, out := &in.EarliestRecoveryTime, &out.EarliestRecoveryTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepoStatus.
@@ -1490,6 +2300,36 @@ func (in *RepoStatus) DeepCopy() *RepoStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepoStorageConnection) DeepCopyInto(out *RepoStorageConnection) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+	if in.VerifyTLS != nil {
+		in, out := &in.VerifyTLS, &out.VerifyTLS
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepoStorageConnection.
+func (in *RepoStorageConnection) DeepCopy() *RepoStorageConnection {
+	if in == nil {
+		return nil
+	}
+	out := new(RepoStorageConnection)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceSpec) DeepCopyInto(out *ServiceSpec) {
 	*out = *in
@@ -1525,6 +2365,22 @@ func (in *Sidecar) DeepCopy() *Sidecar {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TablespaceVolume) DeepCopyInto(out *TablespaceVolume) {
+	*out = *in
+	in.DataVolumeClaimSpec.DeepCopyInto(&out.DataVolumeClaimSpec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TablespaceVolume.
+func (in *TablespaceVolume) DeepCopy() *TablespaceVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(TablespaceVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UserInterfaceSpec) DeepCopyInto(out *UserInterfaceSpec) {
 	*out = *in
@@ -1544,3 +2400,20 @@ func (in *UserInterfaceSpec) DeepCopy() *UserInterfaceSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeAutoGrow) DeepCopyInto(out *VolumeAutoGrow) {
+	*out = *in
+	out.Increase = in.Increase.DeepCopy()
+	out.Limit = in.Limit.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeAutoGrow.
+func (in *VolumeAutoGrow) DeepCopy() *VolumeAutoGrow {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeAutoGrow)
+	in.DeepCopyInto(out)
+	return out
+}